@@ -0,0 +1,1704 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: eureka/v1/eureka.proto
+
+package eurekav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PCMEncoding is the sample format of headerless PCM audio.
+type PCMEncoding int32
+
+const (
+	PCMEncoding_PCM_ENCODING_UNSPECIFIED PCMEncoding = 0
+	PCMEncoding_PCM_ENCODING_S16LE       PCMEncoding = 1
+	PCMEncoding_PCM_ENCODING_S32LE       PCMEncoding = 2
+	PCMEncoding_PCM_ENCODING_F32LE       PCMEncoding = 3
+	PCMEncoding_PCM_ENCODING_U8          PCMEncoding = 4
+)
+
+// Enum value maps for PCMEncoding.
+var (
+	PCMEncoding_name = map[int32]string{
+		0: "PCM_ENCODING_UNSPECIFIED",
+		1: "PCM_ENCODING_S16LE",
+		2: "PCM_ENCODING_S32LE",
+		3: "PCM_ENCODING_F32LE",
+		4: "PCM_ENCODING_U8",
+	}
+	PCMEncoding_value = map[string]int32{
+		"PCM_ENCODING_UNSPECIFIED": 0,
+		"PCM_ENCODING_S16LE":       1,
+		"PCM_ENCODING_S32LE":       2,
+		"PCM_ENCODING_F32LE":       3,
+		"PCM_ENCODING_U8":          4,
+	}
+)
+
+func (x PCMEncoding) Enum() *PCMEncoding {
+	p := new(PCMEncoding)
+	*p = x
+	return p
+}
+
+func (x PCMEncoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PCMEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_eureka_v1_eureka_proto_enumTypes[0].Descriptor()
+}
+
+func (PCMEncoding) Type() protoreflect.EnumType {
+	return &file_eureka_v1_eureka_proto_enumTypes[0]
+}
+
+func (x PCMEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PCMEncoding.Descriptor instead.
+func (PCMEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{0}
+}
+
+// StreamEventType matches the events of the WebSocket live endpoint.
+type StreamEventType int32
+
+const (
+	StreamEventType_STREAM_EVENT_TYPE_UNSPECIFIED    StreamEventType = 0
+	StreamEventType_STREAM_EVENT_TYPE_LISTENING      StreamEventType = 1
+	StreamEventType_STREAM_EVENT_TYPE_POSSIBLE_MATCH StreamEventType = 2
+	StreamEventType_STREAM_EVENT_TYPE_MATCH          StreamEventType = 3
+	StreamEventType_STREAM_EVENT_TYPE_NO_MATCH       StreamEventType = 4
+)
+
+// Enum value maps for StreamEventType.
+var (
+	StreamEventType_name = map[int32]string{
+		0: "STREAM_EVENT_TYPE_UNSPECIFIED",
+		1: "STREAM_EVENT_TYPE_LISTENING",
+		2: "STREAM_EVENT_TYPE_POSSIBLE_MATCH",
+		3: "STREAM_EVENT_TYPE_MATCH",
+		4: "STREAM_EVENT_TYPE_NO_MATCH",
+	}
+	StreamEventType_value = map[string]int32{
+		"STREAM_EVENT_TYPE_UNSPECIFIED":    0,
+		"STREAM_EVENT_TYPE_LISTENING":      1,
+		"STREAM_EVENT_TYPE_POSSIBLE_MATCH": 2,
+		"STREAM_EVENT_TYPE_MATCH":          3,
+		"STREAM_EVENT_TYPE_NO_MATCH":       4,
+	}
+)
+
+func (x StreamEventType) Enum() *StreamEventType {
+	p := new(StreamEventType)
+	*p = x
+	return p
+}
+
+func (x StreamEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StreamEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_eureka_v1_eureka_proto_enumTypes[1].Descriptor()
+}
+
+func (StreamEventType) Type() protoreflect.EnumType {
+	return &file_eureka_v1_eureka_proto_enumTypes[1]
+}
+
+func (x StreamEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StreamEventType.Descriptor instead.
+func (StreamEventType) EnumDescriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{1}
+}
+
+// PCMFormat describes headerless, interleaved PCM audio.
+type PCMFormat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Encoding      PCMEncoding            `protobuf:"varint,1,opt,name=encoding,proto3,enum=eureka.v1.PCMEncoding" json:"encoding,omitempty"`
+	SampleRate    int32                  `protobuf:"varint,2,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	Channels      int32                  `protobuf:"varint,3,opt,name=channels,proto3" json:"channels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PCMFormat) Reset() {
+	*x = PCMFormat{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PCMFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PCMFormat) ProtoMessage() {}
+
+func (x *PCMFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PCMFormat.ProtoReflect.Descriptor instead.
+func (*PCMFormat) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PCMFormat) GetEncoding() PCMEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return PCMEncoding_PCM_ENCODING_UNSPECIFIED
+}
+
+func (x *PCMFormat) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *PCMFormat) GetChannels() int32 {
+	if x != nil {
+		return x.Channels
+	}
+	return 0
+}
+
+// Match is a song recognized in the submitted audio.
+type Match struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	SongId int64                  `protobuf:"varint,1,opt,name=song_id,json=songId,proto3" json:"song_id,omitempty"`
+	Title  string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Artist string                 `protobuf:"bytes,3,opt,name=artist,proto3" json:"artist,omitempty"`
+	Score  float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	// Position of the submitted audio in the song, in milliseconds.
+	OffsetMs      int64 `protobuf:"varint,5,opt,name=offset_ms,json=offsetMs,proto3" json:"offset_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Match) GetSongId() int64 {
+	if x != nil {
+		return x.SongId
+	}
+	return 0
+}
+
+func (x *Match) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Match) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *Match) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Match) GetOffsetMs() int64 {
+	if x != nil {
+		return x.OffsetMs
+	}
+	return 0
+}
+
+// Song is a catalog entry.
+type Song struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Artist        string                 `protobuf:"bytes,3,opt,name=artist,proto3" json:"artist,omitempty"`
+	Fingerprinted bool                   `protobuf:"varint,4,opt,name=fingerprinted,proto3" json:"fingerprinted,omitempty"`
+	FileSha1      string                 `protobuf:"bytes,5,opt,name=file_sha1,json=fileSha1,proto3" json:"file_sha1,omitempty"`
+	TotalHashes   int64                  `protobuf:"varint,6,opt,name=total_hashes,json=totalHashes,proto3" json:"total_hashes,omitempty"`
+	DateCreated   string                 `protobuf:"bytes,7,opt,name=date_created,json=dateCreated,proto3" json:"date_created,omitempty"`
+	Album         string                 `protobuf:"bytes,8,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Song) Reset() {
+	*x = Song{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Song) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Song) ProtoMessage() {}
+
+func (x *Song) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Song.ProtoReflect.Descriptor instead.
+func (*Song) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Song) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Song) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Song) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *Song) GetFingerprinted() bool {
+	if x != nil {
+		return x.Fingerprinted
+	}
+	return false
+}
+
+func (x *Song) GetFileSha1() string {
+	if x != nil {
+		return x.FileSha1
+	}
+	return ""
+}
+
+func (x *Song) GetTotalHashes() int64 {
+	if x != nil {
+		return x.TotalHashes
+	}
+	return 0
+}
+
+func (x *Song) GetDateCreated() string {
+	if x != nil {
+		return x.DateCreated
+	}
+	return ""
+}
+
+func (x *Song) GetAlbum() string {
+	if x != nil {
+		return x.Album
+	}
+	return ""
+}
+
+type RecognizeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Encoded audio (WAV, FLAC or MP3, detected from the content), or raw PCM
+	// when raw_format is set. Only the first 30 seconds are used.
+	Audio         []byte     `protobuf:"bytes,1,opt,name=audio,proto3" json:"audio,omitempty"`
+	RawFormat     *PCMFormat `protobuf:"bytes,2,opt,name=raw_format,json=rawFormat,proto3" json:"raw_format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecognizeRequest) Reset() {
+	*x = RecognizeRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecognizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecognizeRequest) ProtoMessage() {}
+
+func (x *RecognizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecognizeRequest.ProtoReflect.Descriptor instead.
+func (*RecognizeRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RecognizeRequest) GetAudio() []byte {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *RecognizeRequest) GetRawFormat() *PCMFormat {
+	if x != nil {
+		return x.RawFormat
+	}
+	return nil
+}
+
+type RecognizeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*Match               `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecognizeResponse) Reset() {
+	*x = RecognizeResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecognizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecognizeResponse) ProtoMessage() {}
+
+func (x *RecognizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecognizeResponse.ProtoReflect.Descriptor instead.
+func (*RecognizeResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RecognizeResponse) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type StreamRecognizeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*StreamRecognizeRequest_Format
+	//	*StreamRecognizeRequest_Audio
+	Request       isStreamRecognizeRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRecognizeRequest) Reset() {
+	*x = StreamRecognizeRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRecognizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRecognizeRequest) ProtoMessage() {}
+
+func (x *StreamRecognizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRecognizeRequest.ProtoReflect.Descriptor instead.
+func (*StreamRecognizeRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamRecognizeRequest) GetRequest() isStreamRecognizeRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *StreamRecognizeRequest) GetFormat() *PCMFormat {
+	if x != nil {
+		if x, ok := x.Request.(*StreamRecognizeRequest_Format); ok {
+			return x.Format
+		}
+	}
+	return nil
+}
+
+func (x *StreamRecognizeRequest) GetAudio() []byte {
+	if x != nil {
+		if x, ok := x.Request.(*StreamRecognizeRequest_Audio); ok {
+			return x.Audio
+		}
+	}
+	return nil
+}
+
+type isStreamRecognizeRequest_Request interface {
+	isStreamRecognizeRequest_Request()
+}
+
+type StreamRecognizeRequest_Format struct {
+	// Must be the first message of the stream.
+	Format *PCMFormat `protobuf:"bytes,1,opt,name=format,proto3,oneof"`
+}
+
+type StreamRecognizeRequest_Audio struct {
+	// PCM in the configured format, samples may be split across chunks.
+	Audio []byte `protobuf:"bytes,2,opt,name=audio,proto3,oneof"`
+}
+
+func (*StreamRecognizeRequest_Format) isStreamRecognizeRequest_Request() {}
+
+func (*StreamRecognizeRequest_Audio) isStreamRecognizeRequest_Request() {}
+
+type StreamRecognizeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  StreamEventType        `protobuf:"varint,1,opt,name=type,proto3,enum=eureka.v1.StreamEventType" json:"type,omitempty"`
+	// Amount of audio analyzed when the event fired.
+	Seconds float64 `protobuf:"fixed64,2,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	// Set for possible_match and match events.
+	Match *Match `protobuf:"bytes,3,opt,name=match,proto3" json:"match,omitempty"`
+	// Why the stream ended without a match: timeout, audio_limit or end.
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRecognizeResponse) Reset() {
+	*x = StreamRecognizeResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRecognizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRecognizeResponse) ProtoMessage() {}
+
+func (x *StreamRecognizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRecognizeResponse.ProtoReflect.Descriptor instead.
+func (*StreamRecognizeResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StreamRecognizeResponse) GetType() StreamEventType {
+	if x != nil {
+		return x.Type
+	}
+	return StreamEventType_STREAM_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *StreamRecognizeResponse) GetSeconds() float64 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+func (x *StreamRecognizeResponse) GetMatch() *Match {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *StreamRecognizeResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type IngestSongRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Encoded audio, its format is taken from the file name extension.
+	Audio    []byte `protobuf:"bytes,1,opt,name=audio,proto3" json:"audio,omitempty"`
+	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	// Default to the "Artist--Title.ext" file name.
+	Title         string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Artist        string `protobuf:"bytes,4,opt,name=artist,proto3" json:"artist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestSongRequest) Reset() {
+	*x = IngestSongRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestSongRequest) ProtoMessage() {}
+
+func (x *IngestSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestSongRequest.ProtoReflect.Descriptor instead.
+func (*IngestSongRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IngestSongRequest) GetAudio() []byte {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *IngestSongRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *IngestSongRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *IngestSongRequest) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+type IngestSongResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Song          *Song                  `protobuf:"bytes,1,opt,name=song,proto3" json:"song,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestSongResponse) Reset() {
+	*x = IngestSongResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestSongResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestSongResponse) ProtoMessage() {}
+
+func (x *IngestSongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestSongResponse.ProtoReflect.Descriptor instead.
+func (*IngestSongResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IngestSongResponse) GetSong() *Song {
+	if x != nil {
+		return x.Song
+	}
+	return nil
+}
+
+type ListSongsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 500, defaults to 50.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response, empty for the first page. Keep the
+	// other fields unchanged while paging.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only songs whose title contains this text, ignoring case.
+	TitleContains string `protobuf:"bytes,3,opt,name=title_contains,json=titleContains,proto3" json:"title_contains,omitempty"`
+	// Only songs whose artist contains this text, ignoring case.
+	ArtistContains string `protobuf:"bytes,4,opt,name=artist_contains,json=artistContains,proto3" json:"artist_contains,omitempty"`
+	// Only fingerprinted or incomplete songs, any when unset.
+	Fingerprinted *bool `protobuf:"varint,5,opt,name=fingerprinted,proto3,oneof" json:"fingerprinted,omitempty"`
+	// Only songs added at or after this time.
+	CreatedAfter *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// Only songs added before this time.
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// One of id, title, artist or date_created, optionally followed by " desc".
+	// Defaults to id.
+	OrderBy       string `protobuf:"bytes,8,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSongsRequest) Reset() {
+	*x = ListSongsRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSongsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSongsRequest) ProtoMessage() {}
+
+func (x *ListSongsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSongsRequest.ProtoReflect.Descriptor instead.
+func (*ListSongsRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListSongsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSongsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListSongsRequest) GetTitleContains() string {
+	if x != nil {
+		return x.TitleContains
+	}
+	return ""
+}
+
+func (x *ListSongsRequest) GetArtistContains() string {
+	if x != nil {
+		return x.ArtistContains
+	}
+	return ""
+}
+
+func (x *ListSongsRequest) GetFingerprinted() bool {
+	if x != nil && x.Fingerprinted != nil {
+		return *x.Fingerprinted
+	}
+	return false
+}
+
+func (x *ListSongsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListSongsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListSongsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type ListSongsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Songs []*Song                `protobuf:"bytes,1,rep,name=songs,proto3" json:"songs,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalSize     int32  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSongsResponse) Reset() {
+	*x = ListSongsResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSongsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSongsResponse) ProtoMessage() {}
+
+func (x *ListSongsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSongsResponse.ProtoReflect.Descriptor instead.
+func (*ListSongsResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListSongsResponse) GetSongs() []*Song {
+	if x != nil {
+		return x.Songs
+	}
+	return nil
+}
+
+func (x *ListSongsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListSongsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type GetSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSongRequest) Reset() {
+	*x = GetSongRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSongRequest) ProtoMessage() {}
+
+func (x *GetSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSongRequest.ProtoReflect.Descriptor instead.
+func (*GetSongRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetSongRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetSongResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Song          *Song                  `protobuf:"bytes,1,opt,name=song,proto3" json:"song,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSongResponse) Reset() {
+	*x = GetSongResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSongResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSongResponse) ProtoMessage() {}
+
+func (x *GetSongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSongResponse.ProtoReflect.Descriptor instead.
+func (*GetSongResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSongResponse) GetSong() *Song {
+	if x != nil {
+		return x.Song
+	}
+	return nil
+}
+
+type UpdateSongRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Fields left unset are unchanged. The title cannot be empty.
+	Title         *string `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Artist        *string `protobuf:"bytes,3,opt,name=artist,proto3,oneof" json:"artist,omitempty"`
+	Album         *string `protobuf:"bytes,4,opt,name=album,proto3,oneof" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSongRequest) Reset() {
+	*x = UpdateSongRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSongRequest) ProtoMessage() {}
+
+func (x *UpdateSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSongRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSongRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateSongRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateSongRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetArtist() string {
+	if x != nil && x.Artist != nil {
+		return *x.Artist
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetAlbum() string {
+	if x != nil && x.Album != nil {
+		return *x.Album
+	}
+	return ""
+}
+
+type UpdateSongResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Song          *Song                  `protobuf:"bytes,1,opt,name=song,proto3" json:"song,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSongResponse) Reset() {
+	*x = UpdateSongResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSongResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSongResponse) ProtoMessage() {}
+
+func (x *UpdateSongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSongResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSongResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateSongResponse) GetSong() *Song {
+	if x != nil {
+		return x.Song
+	}
+	return nil
+}
+
+type DeleteSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSongRequest) Reset() {
+	*x = DeleteSongRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSongRequest) ProtoMessage() {}
+
+func (x *DeleteSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSongRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSongRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteSongRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteSongResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSongResponse) Reset() {
+	*x = DeleteSongResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSongResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSongResponse) ProtoMessage() {}
+
+func (x *DeleteSongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSongResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSongResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{16}
+}
+
+// Recognition is a recognition recorded in the history.
+type Recognition struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RecognizedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=recognized_at,json=recognizedAt,proto3" json:"recognized_at,omitempty"`
+	// file, mic or api.
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// API client, from the x-client-id metadata or the peer address.
+	ClientId string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// Duration of the audio queried, in milliseconds.
+	AudioMs   int64 `protobuf:"varint,5,opt,name=audio_ms,json=audioMs,proto3" json:"audio_ms,omitempty"`
+	LatencyMs int64 `protobuf:"varint,6,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// Best first, empty when nothing matched.
+	Matches       []*Match `protobuf:"bytes,7,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recognition) Reset() {
+	*x = Recognition{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recognition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recognition) ProtoMessage() {}
+
+func (x *Recognition) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recognition.ProtoReflect.Descriptor instead.
+func (*Recognition) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Recognition) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Recognition) GetRecognizedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecognizedAt
+	}
+	return nil
+}
+
+func (x *Recognition) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Recognition) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *Recognition) GetAudioMs() int64 {
+	if x != nil {
+		return x.AudioMs
+	}
+	return 0
+}
+
+func (x *Recognition) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *Recognition) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type ListRecognitionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 500, defaults to 50.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response, empty for the first page. Keep the
+	// other fields unchanged while paging.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only recognitions from this source: file, mic or api.
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// Only recognitions requested by this client.
+	ClientId string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// Only recognitions whose best match is this song.
+	SongId int64 `protobuf:"varint,5,opt,name=song_id,json=songId,proto3" json:"song_id,omitempty"`
+	// Only recognitions that matched a song or not, any when unset.
+	Matched *bool `protobuf:"varint,6,opt,name=matched,proto3,oneof" json:"matched,omitempty"`
+	// Only recognitions at or after this time.
+	Since *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=since,proto3" json:"since,omitempty"`
+	// Only recognitions before this time.
+	Until         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecognitionsRequest) Reset() {
+	*x = ListRecognitionsRequest{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecognitionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecognitionsRequest) ProtoMessage() {}
+
+func (x *ListRecognitionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecognitionsRequest.ProtoReflect.Descriptor instead.
+func (*ListRecognitionsRequest) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListRecognitionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRecognitionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListRecognitionsRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ListRecognitionsRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ListRecognitionsRequest) GetSongId() int64 {
+	if x != nil {
+		return x.SongId
+	}
+	return 0
+}
+
+func (x *ListRecognitionsRequest) GetMatched() bool {
+	if x != nil && x.Matched != nil {
+		return *x.Matched
+	}
+	return false
+}
+
+func (x *ListRecognitionsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListRecognitionsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+type ListRecognitionsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Recognitions []*Recognition         `protobuf:"bytes,1,rep,name=recognitions,proto3" json:"recognitions,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalSize     int32  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecognitionsResponse) Reset() {
+	*x = ListRecognitionsResponse{}
+	mi := &file_eureka_v1_eureka_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecognitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecognitionsResponse) ProtoMessage() {}
+
+func (x *ListRecognitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eureka_v1_eureka_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecognitionsResponse.ProtoReflect.Descriptor instead.
+func (*ListRecognitionsResponse) Descriptor() ([]byte, []int) {
+	return file_eureka_v1_eureka_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListRecognitionsResponse) GetRecognitions() []*Recognition {
+	if x != nil {
+		return x.Recognitions
+	}
+	return nil
+}
+
+func (x *ListRecognitionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListRecognitionsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+var File_eureka_v1_eureka_proto protoreflect.FileDescriptor
+
+const file_eureka_v1_eureka_proto_rawDesc = "" +
+	"\n" +
+	"\x16eureka/v1/eureka.proto\x12\teureka.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"|\n" +
+	"\tPCMFormat\x122\n" +
+	"\bencoding\x18\x01 \x01(\x0e2\x16.eureka.v1.PCMEncodingR\bencoding\x12\x1f\n" +
+	"\vsample_rate\x18\x02 \x01(\x05R\n" +
+	"sampleRate\x12\x1a\n" +
+	"\bchannels\x18\x03 \x01(\x05R\bchannels\"\x81\x01\n" +
+	"\x05Match\x12\x17\n" +
+	"\asong_id\x18\x01 \x01(\x03R\x06songId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06artist\x18\x03 \x01(\tR\x06artist\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\x12\x1b\n" +
+	"\toffset_ms\x18\x05 \x01(\x03R\boffsetMs\"\xe3\x01\n" +
+	"\x04Song\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06artist\x18\x03 \x01(\tR\x06artist\x12$\n" +
+	"\rfingerprinted\x18\x04 \x01(\bR\rfingerprinted\x12\x1b\n" +
+	"\tfile_sha1\x18\x05 \x01(\tR\bfileSha1\x12!\n" +
+	"\ftotal_hashes\x18\x06 \x01(\x03R\vtotalHashes\x12!\n" +
+	"\fdate_created\x18\a \x01(\tR\vdateCreated\x12\x14\n" +
+	"\x05album\x18\b \x01(\tR\x05album\"]\n" +
+	"\x10RecognizeRequest\x12\x14\n" +
+	"\x05audio\x18\x01 \x01(\fR\x05audio\x123\n" +
+	"\n" +
+	"raw_format\x18\x02 \x01(\v2\x14.eureka.v1.PCMFormatR\trawFormat\"?\n" +
+	"\x11RecognizeResponse\x12*\n" +
+	"\amatches\x18\x01 \x03(\v2\x10.eureka.v1.MatchR\amatches\"k\n" +
+	"\x16StreamRecognizeRequest\x12.\n" +
+	"\x06format\x18\x01 \x01(\v2\x14.eureka.v1.PCMFormatH\x00R\x06format\x12\x16\n" +
+	"\x05audio\x18\x02 \x01(\fH\x00R\x05audioB\t\n" +
+	"\arequest\"\xa3\x01\n" +
+	"\x17StreamRecognizeResponse\x12.\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1a.eureka.v1.StreamEventTypeR\x04type\x12\x18\n" +
+	"\aseconds\x18\x02 \x01(\x01R\aseconds\x12&\n" +
+	"\x05match\x18\x03 \x01(\v2\x10.eureka.v1.MatchR\x05match\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"s\n" +
+	"\x11IngestSongRequest\x12\x14\n" +
+	"\x05audio\x18\x01 \x01(\fR\x05audio\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x16\n" +
+	"\x06artist\x18\x04 \x01(\tR\x06artist\"9\n" +
+	"\x12IngestSongResponse\x12#\n" +
+	"\x04song\x18\x01 \x01(\v2\x0f.eureka.v1.SongR\x04song\"\xfa\x02\n" +
+	"\x10ListSongsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12%\n" +
+	"\x0etitle_contains\x18\x03 \x01(\tR\rtitleContains\x12'\n" +
+	"\x0fartist_contains\x18\x04 \x01(\tR\x0eartistContains\x12)\n" +
+	"\rfingerprinted\x18\x05 \x01(\bH\x00R\rfingerprinted\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x19\n" +
+	"\border_by\x18\b \x01(\tR\aorderByB\x10\n" +
+	"\x0e_fingerprinted\"\x81\x01\n" +
+	"\x11ListSongsResponse\x12%\n" +
+	"\x05songs\x18\x01 \x03(\v2\x0f.eureka.v1.SongR\x05songs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\" \n" +
+	"\x0eGetSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"6\n" +
+	"\x0fGetSongResponse\x12#\n" +
+	"\x04song\x18\x01 \x01(\v2\x0f.eureka.v1.SongR\x04song\"\x95\x01\n" +
+	"\x11UpdateSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12\x1b\n" +
+	"\x06artist\x18\x03 \x01(\tH\x01R\x06artist\x88\x01\x01\x12\x19\n" +
+	"\x05album\x18\x04 \x01(\tH\x02R\x05album\x88\x01\x01B\b\n" +
+	"\x06_titleB\t\n" +
+	"\a_artistB\b\n" +
+	"\x06_album\"9\n" +
+	"\x12UpdateSongResponse\x12#\n" +
+	"\x04song\x18\x01 \x01(\v2\x0f.eureka.v1.SongR\x04song\"#\n" +
+	"\x11DeleteSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x14\n" +
+	"\x12DeleteSongResponse\"\xf9\x01\n" +
+	"\vRecognition\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12?\n" +
+	"\rrecognized_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\frecognizedAt\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x12\x19\n" +
+	"\baudio_ms\x18\x05 \x01(\x03R\aaudioMs\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x06 \x01(\x03R\tlatencyMs\x12*\n" +
+	"\amatches\x18\a \x03(\v2\x10.eureka.v1.MatchR\amatches\"\xb2\x02\n" +
+	"\x17ListRecognitionsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x1b\n" +
+	"\tclient_id\x18\x04 \x01(\tR\bclientId\x12\x17\n" +
+	"\asong_id\x18\x05 \x01(\x03R\x06songId\x12\x1d\n" +
+	"\amatched\x18\x06 \x01(\bH\x00R\amatched\x88\x01\x01\x120\n" +
+	"\x05since\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x05untilB\n" +
+	"\n" +
+	"\b_matched\"\x9d\x01\n" +
+	"\x18ListRecognitionsResponse\x12:\n" +
+	"\frecognitions\x18\x01 \x03(\v2\x16.eureka.v1.RecognitionR\frecognitions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize*\x88\x01\n" +
+	"\vPCMEncoding\x12\x1c\n" +
+	"\x18PCM_ENCODING_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12PCM_ENCODING_S16LE\x10\x01\x12\x16\n" +
+	"\x12PCM_ENCODING_S32LE\x10\x02\x12\x16\n" +
+	"\x12PCM_ENCODING_F32LE\x10\x03\x12\x13\n" +
+	"\x0fPCM_ENCODING_U8\x10\x04*\xb8\x01\n" +
+	"\x0fStreamEventType\x12!\n" +
+	"\x1dSTREAM_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bSTREAM_EVENT_TYPE_LISTENING\x10\x01\x12$\n" +
+	" STREAM_EVENT_TYPE_POSSIBLE_MATCH\x10\x02\x12\x1b\n" +
+	"\x17STREAM_EVENT_TYPE_MATCH\x10\x03\x12\x1e\n" +
+	"\x1aSTREAM_EVENT_TYPE_NO_MATCH\x10\x042\xfd\x04\n" +
+	"\rEurekaService\x12F\n" +
+	"\tRecognize\x12\x1b.eureka.v1.RecognizeRequest\x1a\x1c.eureka.v1.RecognizeResponse\x12\\\n" +
+	"\x0fStreamRecognize\x12!.eureka.v1.StreamRecognizeRequest\x1a\".eureka.v1.StreamRecognizeResponse(\x010\x01\x12I\n" +
+	"\n" +
+	"IngestSong\x12\x1c.eureka.v1.IngestSongRequest\x1a\x1d.eureka.v1.IngestSongResponse\x12F\n" +
+	"\tListSongs\x12\x1b.eureka.v1.ListSongsRequest\x1a\x1c.eureka.v1.ListSongsResponse\x12@\n" +
+	"\aGetSong\x12\x19.eureka.v1.GetSongRequest\x1a\x1a.eureka.v1.GetSongResponse\x12I\n" +
+	"\n" +
+	"UpdateSong\x12\x1c.eureka.v1.UpdateSongRequest\x1a\x1d.eureka.v1.UpdateSongResponse\x12I\n" +
+	"\n" +
+	"DeleteSong\x12\x1c.eureka.v1.DeleteSongRequest\x1a\x1d.eureka.v1.DeleteSongResponse\x12[\n" +
+	"\x10ListRecognitions\x12\".eureka.v1.ListRecognitionsRequest\x1a#.eureka.v1.ListRecognitionsResponseB5Z3github.com/media-luna/eureka/api/eureka/v1;eurekav1b\x06proto3"
+
+var (
+	file_eureka_v1_eureka_proto_rawDescOnce sync.Once
+	file_eureka_v1_eureka_proto_rawDescData []byte
+)
+
+func file_eureka_v1_eureka_proto_rawDescGZIP() []byte {
+	file_eureka_v1_eureka_proto_rawDescOnce.Do(func() {
+		file_eureka_v1_eureka_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_eureka_v1_eureka_proto_rawDesc), len(file_eureka_v1_eureka_proto_rawDesc)))
+	})
+	return file_eureka_v1_eureka_proto_rawDescData
+}
+
+var file_eureka_v1_eureka_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_eureka_v1_eureka_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_eureka_v1_eureka_proto_goTypes = []any{
+	(PCMEncoding)(0),                 // 0: eureka.v1.PCMEncoding
+	(StreamEventType)(0),             // 1: eureka.v1.StreamEventType
+	(*PCMFormat)(nil),                // 2: eureka.v1.PCMFormat
+	(*Match)(nil),                    // 3: eureka.v1.Match
+	(*Song)(nil),                     // 4: eureka.v1.Song
+	(*RecognizeRequest)(nil),         // 5: eureka.v1.RecognizeRequest
+	(*RecognizeResponse)(nil),        // 6: eureka.v1.RecognizeResponse
+	(*StreamRecognizeRequest)(nil),   // 7: eureka.v1.StreamRecognizeRequest
+	(*StreamRecognizeResponse)(nil),  // 8: eureka.v1.StreamRecognizeResponse
+	(*IngestSongRequest)(nil),        // 9: eureka.v1.IngestSongRequest
+	(*IngestSongResponse)(nil),       // 10: eureka.v1.IngestSongResponse
+	(*ListSongsRequest)(nil),         // 11: eureka.v1.ListSongsRequest
+	(*ListSongsResponse)(nil),        // 12: eureka.v1.ListSongsResponse
+	(*GetSongRequest)(nil),           // 13: eureka.v1.GetSongRequest
+	(*GetSongResponse)(nil),          // 14: eureka.v1.GetSongResponse
+	(*UpdateSongRequest)(nil),        // 15: eureka.v1.UpdateSongRequest
+	(*UpdateSongResponse)(nil),       // 16: eureka.v1.UpdateSongResponse
+	(*DeleteSongRequest)(nil),        // 17: eureka.v1.DeleteSongRequest
+	(*DeleteSongResponse)(nil),       // 18: eureka.v1.DeleteSongResponse
+	(*Recognition)(nil),              // 19: eureka.v1.Recognition
+	(*ListRecognitionsRequest)(nil),  // 20: eureka.v1.ListRecognitionsRequest
+	(*ListRecognitionsResponse)(nil), // 21: eureka.v1.ListRecognitionsResponse
+	(*timestamppb.Timestamp)(nil),    // 22: google.protobuf.Timestamp
+}
+var file_eureka_v1_eureka_proto_depIdxs = []int32{
+	0,  // 0: eureka.v1.PCMFormat.encoding:type_name -> eureka.v1.PCMEncoding
+	2,  // 1: eureka.v1.RecognizeRequest.raw_format:type_name -> eureka.v1.PCMFormat
+	3,  // 2: eureka.v1.RecognizeResponse.matches:type_name -> eureka.v1.Match
+	2,  // 3: eureka.v1.StreamRecognizeRequest.format:type_name -> eureka.v1.PCMFormat
+	1,  // 4: eureka.v1.StreamRecognizeResponse.type:type_name -> eureka.v1.StreamEventType
+	3,  // 5: eureka.v1.StreamRecognizeResponse.match:type_name -> eureka.v1.Match
+	4,  // 6: eureka.v1.IngestSongResponse.song:type_name -> eureka.v1.Song
+	22, // 7: eureka.v1.ListSongsRequest.created_after:type_name -> google.protobuf.Timestamp
+	22, // 8: eureka.v1.ListSongsRequest.created_before:type_name -> google.protobuf.Timestamp
+	4,  // 9: eureka.v1.ListSongsResponse.songs:type_name -> eureka.v1.Song
+	4,  // 10: eureka.v1.GetSongResponse.song:type_name -> eureka.v1.Song
+	4,  // 11: eureka.v1.UpdateSongResponse.song:type_name -> eureka.v1.Song
+	22, // 12: eureka.v1.Recognition.recognized_at:type_name -> google.protobuf.Timestamp
+	3,  // 13: eureka.v1.Recognition.matches:type_name -> eureka.v1.Match
+	22, // 14: eureka.v1.ListRecognitionsRequest.since:type_name -> google.protobuf.Timestamp
+	22, // 15: eureka.v1.ListRecognitionsRequest.until:type_name -> google.protobuf.Timestamp
+	19, // 16: eureka.v1.ListRecognitionsResponse.recognitions:type_name -> eureka.v1.Recognition
+	5,  // 17: eureka.v1.EurekaService.Recognize:input_type -> eureka.v1.RecognizeRequest
+	7,  // 18: eureka.v1.EurekaService.StreamRecognize:input_type -> eureka.v1.StreamRecognizeRequest
+	9,  // 19: eureka.v1.EurekaService.IngestSong:input_type -> eureka.v1.IngestSongRequest
+	11, // 20: eureka.v1.EurekaService.ListSongs:input_type -> eureka.v1.ListSongsRequest
+	13, // 21: eureka.v1.EurekaService.GetSong:input_type -> eureka.v1.GetSongRequest
+	15, // 22: eureka.v1.EurekaService.UpdateSong:input_type -> eureka.v1.UpdateSongRequest
+	17, // 23: eureka.v1.EurekaService.DeleteSong:input_type -> eureka.v1.DeleteSongRequest
+	20, // 24: eureka.v1.EurekaService.ListRecognitions:input_type -> eureka.v1.ListRecognitionsRequest
+	6,  // 25: eureka.v1.EurekaService.Recognize:output_type -> eureka.v1.RecognizeResponse
+	8,  // 26: eureka.v1.EurekaService.StreamRecognize:output_type -> eureka.v1.StreamRecognizeResponse
+	10, // 27: eureka.v1.EurekaService.IngestSong:output_type -> eureka.v1.IngestSongResponse
+	12, // 28: eureka.v1.EurekaService.ListSongs:output_type -> eureka.v1.ListSongsResponse
+	14, // 29: eureka.v1.EurekaService.GetSong:output_type -> eureka.v1.GetSongResponse
+	16, // 30: eureka.v1.EurekaService.UpdateSong:output_type -> eureka.v1.UpdateSongResponse
+	18, // 31: eureka.v1.EurekaService.DeleteSong:output_type -> eureka.v1.DeleteSongResponse
+	21, // 32: eureka.v1.EurekaService.ListRecognitions:output_type -> eureka.v1.ListRecognitionsResponse
+	25, // [25:33] is the sub-list for method output_type
+	17, // [17:25] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_eureka_v1_eureka_proto_init() }
+func file_eureka_v1_eureka_proto_init() {
+	if File_eureka_v1_eureka_proto != nil {
+		return
+	}
+	file_eureka_v1_eureka_proto_msgTypes[5].OneofWrappers = []any{
+		(*StreamRecognizeRequest_Format)(nil),
+		(*StreamRecognizeRequest_Audio)(nil),
+	}
+	file_eureka_v1_eureka_proto_msgTypes[9].OneofWrappers = []any{}
+	file_eureka_v1_eureka_proto_msgTypes[13].OneofWrappers = []any{}
+	file_eureka_v1_eureka_proto_msgTypes[18].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_eureka_v1_eureka_proto_rawDesc), len(file_eureka_v1_eureka_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_eureka_v1_eureka_proto_goTypes,
+		DependencyIndexes: file_eureka_v1_eureka_proto_depIdxs,
+		EnumInfos:         file_eureka_v1_eureka_proto_enumTypes,
+		MessageInfos:      file_eureka_v1_eureka_proto_msgTypes,
+	}.Build()
+	File_eureka_v1_eureka_proto = out.File
+	file_eureka_v1_eureka_proto_goTypes = nil
+	file_eureka_v1_eureka_proto_depIdxs = nil
+}
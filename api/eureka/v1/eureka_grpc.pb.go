@@ -0,0 +1,406 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: eureka/v1/eureka.proto
+
+package eurekav1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EurekaService_Recognize_FullMethodName        = "/eureka.v1.EurekaService/Recognize"
+	EurekaService_StreamRecognize_FullMethodName  = "/eureka.v1.EurekaService/StreamRecognize"
+	EurekaService_IngestSong_FullMethodName       = "/eureka.v1.EurekaService/IngestSong"
+	EurekaService_ListSongs_FullMethodName        = "/eureka.v1.EurekaService/ListSongs"
+	EurekaService_GetSong_FullMethodName          = "/eureka.v1.EurekaService/GetSong"
+	EurekaService_UpdateSong_FullMethodName       = "/eureka.v1.EurekaService/UpdateSong"
+	EurekaService_DeleteSong_FullMethodName       = "/eureka.v1.EurekaService/DeleteSong"
+	EurekaService_ListRecognitions_FullMethodName = "/eureka.v1.EurekaService/ListRecognitions"
+)
+
+// EurekaServiceClient is the client API for EurekaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EurekaService mirrors the Eureka API: recognition and catalog management.
+type EurekaServiceClient interface {
+	// Recognize matches a complete audio clip.
+	Recognize(ctx context.Context, in *RecognizeRequest, opts ...grpc.CallOption) (*RecognizeResponse, error)
+	// StreamRecognize matches live audio. The first request carries the stream
+	// configuration, the following ones PCM chunks. The server answers with match
+	// events and ends the stream after a match or a no_match event.
+	StreamRecognize(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamRecognizeRequest, StreamRecognizeResponse], error)
+	// IngestSong fingerprints a song and adds it to the catalog.
+	IngestSong(ctx context.Context, in *IngestSongRequest, opts ...grpc.CallOption) (*IngestSongResponse, error)
+	// ListSongs lists the catalog, one page at a time.
+	ListSongs(ctx context.Context, in *ListSongsRequest, opts ...grpc.CallOption) (*ListSongsResponse, error)
+	// GetSong returns a single song, NOT_FOUND if it does not exist.
+	GetSong(ctx context.Context, in *GetSongRequest, opts ...grpc.CallOption) (*GetSongResponse, error)
+	// UpdateSong changes the metadata of a song, its fingerprints are kept.
+	UpdateSong(ctx context.Context, in *UpdateSongRequest, opts ...grpc.CallOption) (*UpdateSongResponse, error)
+	// DeleteSong deletes a song and its fingerprints.
+	DeleteSong(ctx context.Context, in *DeleteSongRequest, opts ...grpc.CallOption) (*DeleteSongResponse, error)
+	// ListRecognitions lists the recognition history, newest first, one page at a time.
+	ListRecognitions(ctx context.Context, in *ListRecognitionsRequest, opts ...grpc.CallOption) (*ListRecognitionsResponse, error)
+}
+
+type eurekaServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEurekaServiceClient(cc grpc.ClientConnInterface) EurekaServiceClient {
+	return &eurekaServiceClient{cc}
+}
+
+func (c *eurekaServiceClient) Recognize(ctx context.Context, in *RecognizeRequest, opts ...grpc.CallOption) (*RecognizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecognizeResponse)
+	err := c.cc.Invoke(ctx, EurekaService_Recognize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eurekaServiceClient) StreamRecognize(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamRecognizeRequest, StreamRecognizeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EurekaService_ServiceDesc.Streams[0], EurekaService_StreamRecognize_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRecognizeRequest, StreamRecognizeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EurekaService_StreamRecognizeClient = grpc.BidiStreamingClient[StreamRecognizeRequest, StreamRecognizeResponse]
+
+func (c *eurekaServiceClient) IngestSong(ctx context.Context, in *IngestSongRequest, opts ...grpc.CallOption) (*IngestSongResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IngestSongResponse)
+	err := c.cc.Invoke(ctx, EurekaService_IngestSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eurekaServiceClient) ListSongs(ctx context.Context, in *ListSongsRequest, opts ...grpc.CallOption) (*ListSongsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSongsResponse)
+	err := c.cc.Invoke(ctx, EurekaService_ListSongs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eurekaServiceClient) GetSong(ctx context.Context, in *GetSongRequest, opts ...grpc.CallOption) (*GetSongResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSongResponse)
+	err := c.cc.Invoke(ctx, EurekaService_GetSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eurekaServiceClient) UpdateSong(ctx context.Context, in *UpdateSongRequest, opts ...grpc.CallOption) (*UpdateSongResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateSongResponse)
+	err := c.cc.Invoke(ctx, EurekaService_UpdateSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eurekaServiceClient) DeleteSong(ctx context.Context, in *DeleteSongRequest, opts ...grpc.CallOption) (*DeleteSongResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSongResponse)
+	err := c.cc.Invoke(ctx, EurekaService_DeleteSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eurekaServiceClient) ListRecognitions(ctx context.Context, in *ListRecognitionsRequest, opts ...grpc.CallOption) (*ListRecognitionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecognitionsResponse)
+	err := c.cc.Invoke(ctx, EurekaService_ListRecognitions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EurekaServiceServer is the server API for EurekaService service.
+// All implementations must embed UnimplementedEurekaServiceServer
+// for forward compatibility.
+//
+// EurekaService mirrors the Eureka API: recognition and catalog management.
+type EurekaServiceServer interface {
+	// Recognize matches a complete audio clip.
+	Recognize(context.Context, *RecognizeRequest) (*RecognizeResponse, error)
+	// StreamRecognize matches live audio. The first request carries the stream
+	// configuration, the following ones PCM chunks. The server answers with match
+	// events and ends the stream after a match or a no_match event.
+	StreamRecognize(grpc.BidiStreamingServer[StreamRecognizeRequest, StreamRecognizeResponse]) error
+	// IngestSong fingerprints a song and adds it to the catalog.
+	IngestSong(context.Context, *IngestSongRequest) (*IngestSongResponse, error)
+	// ListSongs lists the catalog, one page at a time.
+	ListSongs(context.Context, *ListSongsRequest) (*ListSongsResponse, error)
+	// GetSong returns a single song, NOT_FOUND if it does not exist.
+	GetSong(context.Context, *GetSongRequest) (*GetSongResponse, error)
+	// UpdateSong changes the metadata of a song, its fingerprints are kept.
+	UpdateSong(context.Context, *UpdateSongRequest) (*UpdateSongResponse, error)
+	// DeleteSong deletes a song and its fingerprints.
+	DeleteSong(context.Context, *DeleteSongRequest) (*DeleteSongResponse, error)
+	// ListRecognitions lists the recognition history, newest first, one page at a time.
+	ListRecognitions(context.Context, *ListRecognitionsRequest) (*ListRecognitionsResponse, error)
+	mustEmbedUnimplementedEurekaServiceServer()
+}
+
+// UnimplementedEurekaServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEurekaServiceServer struct{}
+
+func (UnimplementedEurekaServiceServer) Recognize(context.Context, *RecognizeRequest) (*RecognizeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Recognize not implemented")
+}
+func (UnimplementedEurekaServiceServer) StreamRecognize(grpc.BidiStreamingServer[StreamRecognizeRequest, StreamRecognizeResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamRecognize not implemented")
+}
+func (UnimplementedEurekaServiceServer) IngestSong(context.Context, *IngestSongRequest) (*IngestSongResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IngestSong not implemented")
+}
+func (UnimplementedEurekaServiceServer) ListSongs(context.Context, *ListSongsRequest) (*ListSongsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSongs not implemented")
+}
+func (UnimplementedEurekaServiceServer) GetSong(context.Context, *GetSongRequest) (*GetSongResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSong not implemented")
+}
+func (UnimplementedEurekaServiceServer) UpdateSong(context.Context, *UpdateSongRequest) (*UpdateSongResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateSong not implemented")
+}
+func (UnimplementedEurekaServiceServer) DeleteSong(context.Context, *DeleteSongRequest) (*DeleteSongResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteSong not implemented")
+}
+func (UnimplementedEurekaServiceServer) ListRecognitions(context.Context, *ListRecognitionsRequest) (*ListRecognitionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRecognitions not implemented")
+}
+func (UnimplementedEurekaServiceServer) mustEmbedUnimplementedEurekaServiceServer() {}
+func (UnimplementedEurekaServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeEurekaServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EurekaServiceServer will
+// result in compilation errors.
+type UnsafeEurekaServiceServer interface {
+	mustEmbedUnimplementedEurekaServiceServer()
+}
+
+func RegisterEurekaServiceServer(s grpc.ServiceRegistrar, srv EurekaServiceServer) {
+	// If the following call panics, it indicates UnimplementedEurekaServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EurekaService_ServiceDesc, srv)
+}
+
+func _EurekaService_Recognize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecognizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).Recognize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_Recognize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).Recognize(ctx, req.(*RecognizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EurekaService_StreamRecognize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EurekaServiceServer).StreamRecognize(&grpc.GenericServerStream[StreamRecognizeRequest, StreamRecognizeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EurekaService_StreamRecognizeServer = grpc.BidiStreamingServer[StreamRecognizeRequest, StreamRecognizeResponse]
+
+func _EurekaService_IngestSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IngestSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).IngestSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_IngestSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).IngestSong(ctx, req.(*IngestSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EurekaService_ListSongs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSongsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).ListSongs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_ListSongs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).ListSongs(ctx, req.(*ListSongsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EurekaService_GetSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).GetSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_GetSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).GetSong(ctx, req.(*GetSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EurekaService_UpdateSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).UpdateSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_UpdateSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).UpdateSong(ctx, req.(*UpdateSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EurekaService_DeleteSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).DeleteSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_DeleteSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).DeleteSong(ctx, req.(*DeleteSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EurekaService_ListRecognitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecognitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EurekaServiceServer).ListRecognitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EurekaService_ListRecognitions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EurekaServiceServer).ListRecognitions(ctx, req.(*ListRecognitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EurekaService_ServiceDesc is the grpc.ServiceDesc for EurekaService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EurekaService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "eureka.v1.EurekaService",
+	HandlerType: (*EurekaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Recognize",
+			Handler:    _EurekaService_Recognize_Handler,
+		},
+		{
+			MethodName: "IngestSong",
+			Handler:    _EurekaService_IngestSong_Handler,
+		},
+		{
+			MethodName: "ListSongs",
+			Handler:    _EurekaService_ListSongs_Handler,
+		},
+		{
+			MethodName: "GetSong",
+			Handler:    _EurekaService_GetSong_Handler,
+		},
+		{
+			MethodName: "UpdateSong",
+			Handler:    _EurekaService_UpdateSong_Handler,
+		},
+		{
+			MethodName: "DeleteSong",
+			Handler:    _EurekaService_DeleteSong_Handler,
+		},
+		{
+			MethodName: "ListRecognitions",
+			Handler:    _EurekaService_ListRecognitions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRecognize",
+			Handler:       _EurekaService_StreamRecognize_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "eureka/v1/eureka.proto",
+}
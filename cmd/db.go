@@ -0,0 +1,397 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/media-luna/eureka/internal/database"
+	"github.com/media-luna/eureka/internal/database/migrate"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+var dbCommand = &command{
+	name: "db",
+	subcommands: []*command{
+		{
+			name:    "cleanup",
+			summary: "Remove duplicate songs, unfingerprinted songs and orphaned fingerprints",
+			setup: func(flags *flag.FlagSet) action {
+				return func(ctx context.Context, g *globals, args []string) error {
+					if len(args) != 0 {
+						return usagef("unexpected arguments: %s", strings.Join(args, " "))
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					if err := app.Cleanup(ctx); err != nil {
+						return fmt.Errorf("error cleaning up duplicates: %v", err)
+					}
+					return nil
+				}
+			},
+		},
+		{
+			name:    "export",
+			args:    "<file|->",
+			summary: "Write the fingerprinted songs to a portable, compressed dump file, or stdout with -",
+			setup: func(flags *flag.FlagSet) action {
+				return func(ctx context.Context, g *globals, args []string) error {
+					if len(args) != 1 {
+						return usagef("expected one dump file")
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					return exportDump(ctx, app, args[0])
+				}
+			},
+		},
+		{
+			name:    "import",
+			args:    "<file|->",
+			summary: "Add the songs of a dump file, or stdin with -, skipping songs already stored",
+			setup: func(flags *flag.FlagSet) action {
+				return func(ctx context.Context, g *globals, args []string) error {
+					if len(args) != 1 {
+						return usagef("expected one dump file")
+					}
+
+					in := os.Stdin
+					if args[0] != "-" {
+						file, err := os.Open(args[0])
+						if err != nil {
+							return fmt.Errorf("error opening dump: %v", err)
+						}
+						defer file.Close()
+						in = file
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					result, err := app.Import(ctx, in)
+					if err != nil {
+						return fmt.Errorf("error importing dump: %v", err)
+					}
+					logger.Info("Imported dump", "songs", result.Songs, "skipped", result.Skipped,
+						"fingerprints", result.Fingerprints, "duration_ms", result.DurationMs)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "stats",
+			summary: "Show catalog statistics: songs, fingerprints, common hashes and table sizes",
+			setup: func(flags *flag.FlagSet) action {
+				var output string
+				outputFlag(flags, &output)
+				top := flags.Int("top", 10, "Number of most common hashes to show")
+
+				return func(ctx context.Context, g *globals, args []string) error {
+					if len(args) != 0 {
+						return usagef("unexpected arguments: %s", strings.Join(args, " "))
+					}
+					if err := checkOutput(output); err != nil {
+						return err
+					}
+					if strings.EqualFold(output, "csv") {
+						return usagef("db stats supports -output table or json")
+					}
+					if *top < 0 {
+						return usagef("invalid -top %d", *top)
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					logger.Info("Computing statistics, this scans every fingerprint")
+					stats, err := app.Stats(ctx, *top)
+					if err != nil {
+						return fmt.Errorf("error computing statistics: %v", err)
+					}
+					return eureka.WriteStats(os.Stdout, output, stats)
+				}
+			},
+		},
+		{
+			name: "stoplist",
+			subcommands: []*command{
+				stopListBuildCommand,
+				stopListShowCommand,
+				stopListClearCommand,
+			},
+		},
+		{
+			name: "migrate",
+			subcommands: []*command{
+				migrateUpCommand,
+				migrateDownCommand,
+				migrateStatusCommand,
+			},
+		},
+	},
+}
+
+var stopListBuildCommand = &command{
+	name:    "build",
+	summary: "Stop-list the hashes found in more than stoplist.max_document_frequency of the songs",
+	setup: func(flags *flag.FlagSet) action {
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			result, err := app.BuildStopList(ctx)
+			if err != nil {
+				return fmt.Errorf("error building stop-list: %v", err)
+			}
+			logger.Info("Stop-listed hashes", "hashes", result.Hashes, "fingerprints", result.Fingerprints,
+				"songs", result.Songs, "min_songs", result.MinSongs)
+			return nil
+		}
+	},
+}
+
+var stopListShowCommand = &command{
+	name:    "show",
+	summary: "List the stop-listed hashes",
+	setup: func(flags *flag.FlagSet) action {
+		var output string
+		outputFlag(flags, &output)
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if err := checkOutput(output); err != nil {
+				return err
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			hashes, err := app.StopList(ctx)
+			if err != nil {
+				return fmt.Errorf("error reading stop-list: %v", err)
+			}
+			if len(hashes) == 0 {
+				logger.Info("The stop-list is empty")
+			}
+			return eureka.WriteHashes(os.Stdout, output, hashes)
+		}
+	},
+}
+
+var stopListClearCommand = &command{
+	name:    "clear",
+	summary: "Remove every hash from the stop-list",
+	setup: func(flags *flag.FlagSet) action {
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			if err := app.ClearStopList(ctx); err != nil {
+				return fmt.Errorf("error clearing stop-list: %v", err)
+			}
+			logger.Info("Cleared the stop-list")
+			return nil
+		}
+	},
+}
+
+var migrateUpCommand = &command{
+	name:    "up",
+	summary: "Apply the pending schema migrations",
+	setup: func(flags *flag.FlagSet) action {
+		to := flags.Int("to", 0, "Stop at this schema version (default: the latest)")
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if *to < 0 {
+				return usagef("invalid -to version %d", *to)
+			}
+
+			migrator, closeDB, err := openMigrator(ctx, g)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			applied, err := migrator.Up(ctx, *to)
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				logger.Info("Schema is up to date")
+			}
+			return nil
+		}
+	},
+}
+
+var migrateDownCommand = &command{
+	name:    "down",
+	summary: "Revert the last schema migration, reverting version 1 drops every table",
+	setup: func(flags *flag.FlagSet) action {
+		to := flags.Int("to", -1, "Revert the migrations above this schema version, -1 reverts the last one only")
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			migrator, closeDB, err := openMigrator(ctx, g)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			target := *to
+			if target < 0 {
+				status, err := migrator.Status(ctx)
+				if err != nil {
+					return err
+				}
+				target = max(status.Current-1, 0)
+			}
+
+			reverted, err := migrator.Down(ctx, target)
+			if err != nil {
+				return err
+			}
+			if len(reverted) == 0 {
+				logger.Info("No migration to revert")
+			}
+			return nil
+		}
+	},
+}
+
+var migrateStatusCommand = &command{
+	name:    "status",
+	summary: "Show the applied and pending schema migrations",
+	setup: func(flags *flag.FlagSet) action {
+		var output string
+		outputFlag(flags, &output)
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if err := checkOutput(output); err != nil {
+				return err
+			}
+
+			migrator, closeDB, err := openMigrator(ctx, g)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			status, err := migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+			return eureka.WriteMigrationStatus(os.Stdout, output, status)
+		}
+	},
+}
+
+// exportDump writes the dump to path, or stdout for -. A failed export leaves no file.
+func exportDump(ctx context.Context, app *eureka.Eureka, path string) error {
+	if path == "-" {
+		summary, err := app.Export(ctx, os.Stdout)
+		if err != nil {
+			return fmt.Errorf("error exporting dump: %v", err)
+		}
+		logger.Info("Exported dump", "songs", summary.Songs, "fingerprints", summary.Fingerprints)
+		return nil
+	}
+
+	// Write next to the destination and rename, so that path is never a partial dump
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating dump: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	summary, err := app.Export(ctx, file)
+	if err != nil {
+		return fmt.Errorf("error exporting dump: %v", err)
+	}
+	// CreateTemp makes the file private, dumps are meant to be shipped
+	if err := file.Chmod(0o644); err != nil {
+		return fmt.Errorf("error writing dump: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing dump: %v", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error writing dump: %v", err)
+	}
+
+	logger.Info("Exported dump", "file", path, "songs", summary.Songs, "fingerprints", summary.Fingerprints)
+	return nil
+}
+
+// openMigrator connects to the database without applying migrations, unlike loadApp,
+// and returns its migrator with a function closing the connection
+func openMigrator(ctx context.Context, g *globals) (*migrate.Migrator, func() error, error) {
+	cfg, err := g.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := database.NewDatabase(ctx, *cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to the database: %v", err)
+	}
+
+	m, ok := db.(database.Migratable)
+	if !ok {
+		db.Close()
+		return nil, nil, fmt.Errorf("database type %s does not support migrations", cfg.Database.Type)
+	}
+	return m.Migrator(), db.Close, nil
+}
@@ -1,113 +1,342 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
 
 	config "github.com/media-luna/eureka/configs"
 	"github.com/media-luna/eureka/internal/eureka"
 	"github.com/media-luna/eureka/utils/logger"
 )
 
+// Exit codes, documented in the README
+const (
+	exitOK      = 0
+	exitError   = 1 // The command failed
+	exitUsage   = 2 // Unknown command, invalid flags or arguments
+	exitNoMatch = 3 // recognize or listen found no song
+)
+
+// errNoMatch is returned by recognition commands that ran successfully but found no song
+var errNoMatch = errors.New("no match found")
+
+// usageError reports invalid arguments, the command usage is printed with it
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+// usagef creates a usageError
+func usagef(format string, args ...interface{}) error {
+	return usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// defaultConfigPath is read when neither --config nor EUREKA_CONFIG is set and it exists
+const defaultConfigPath = "configs/config.yaml"
+
+// globals are the flags accepted before the command name and by every command
+type globals struct {
+	config    string
+	overrides overrideList
+	quiet     bool
+	verbose   bool
+	logLevel  string
+	logFormat string
+}
+
+// register adds the global flags to flags
+func (g *globals) register(flags *flag.FlagSet) {
+	flags.StringVar(&g.config, "config", g.config, "Path to the configuration file (default: $EUREKA_CONFIG, or "+defaultConfigPath+" if it exists)")
+	flags.Var(&g.overrides, "set", "Override a setting as key=value with a dotted key, e.g. database.host=db (repeatable)")
+	flags.BoolVar(&g.quiet, "quiet", g.quiet, "Only log warnings and errors, and hide progress bars")
+	flags.BoolVar(&g.verbose, "v", g.verbose, "Log debug messages")
+	flags.StringVar(&g.logLevel, "log-level", g.logLevel, "Log level: debug, info, warn or error (overrides -quiet and -v)")
+	flags.StringVar(&g.logFormat, "log-format", g.logFormat, "Log format on stderr: text or json")
+}
+
+// setupLogging configures the logger from the global flags
+func (g *globals) setupLogging() error {
+	opts := logger.Options{Level: slog.LevelInfo, Format: g.logFormat}
+	switch {
+	case g.logLevel != "":
+		level, err := logger.ParseLevel(g.logLevel)
+		if err != nil {
+			return usageError{message: err.Error()}
+		}
+		opts.Level = level
+	case g.quiet:
+		opts.Level = slog.LevelWarn
+	case g.verbose:
+		opts.Level = slog.LevelDebug
+	}
+
+	if err := logger.Setup(opts); err != nil {
+		return usageError{message: err.Error()}
+	}
+	return nil
+}
+
+// loadConfig layers the defaults, the configuration file, EUREKA_* environment
+// variables and -set overrides
+func (g *globals) loadConfig() (*config.Config, error) {
+	path := g.config
+	if path == "" {
+		path = os.Getenv("EUREKA_CONFIG")
+	}
+	if path == "" {
+		if _, err := os.Stat(defaultConfigPath); err == nil {
+			path = defaultConfigPath
+		}
+	}
+
+	cfg, err := config.Load(path, g.overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
+	}
+	return cfg, nil
+}
+
+// loadApp loads the configuration and connects Eureka to the database
+func (g *globals) loadApp(ctx context.Context) (*config.Config, *eureka.Eureka, error) {
+	cfg, err := g.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	app, err := connect(ctx, cfg)
+	return cfg, app, err
+}
+
+// connect validates cfg and connects Eureka to the database
+func connect(ctx context.Context, cfg *config.Config) (*eureka.Eureka, error) {
+	app, err := eureka.NewEureka(ctx, *cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Eureka: %v", err)
+	}
+	return app, nil
+}
+
+// overrideList collects repeated -set flags
+type overrideList []string
+
+func (l *overrideList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *overrideList) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected key=value")
+	}
+	*l = append(*l, value)
+	return nil
+}
+
+// action runs a command with its positional arguments
+type action func(ctx context.Context, g *globals, args []string) error
+
+// command is a CLI subcommand. Commands with subcommands have no setup function.
+type command struct {
+	name        string
+	args        string // Positional arguments shown in the usage line
+	summary     string
+	setup       func(flags *flag.FlagSet) action // Registers the command flags and returns the action using them
+	subcommands []*command
+}
+
+// commands are the top-level commands of the CLI
+var commands = []*command{
+	ingestCommand,
+	recognizeCommand,
+	listenCommand,
+	timelineCommand,
+	devicesCommand,
+	monitorCommand,
+	songsCommand,
+	historyCommand,
+	dbCommand,
+	serveCommand,
+}
+
 func main() {
-	// Parse command line arguments
-	audioFile := flag.String("file", "", "Path to the audio file to process")
-	recognizeFile := flag.String("recognize", "", "Path to the audio file to recognize")
-	microphoneCmd := flag.Bool("microphone", false, "Start Shazam-like recognition from microphone (listens until match or 30s timeout)")
-	listCmd := flag.Bool("list", false, "List all songs in the database")
-	cleanupCmd := flag.Bool("cleanup", false, "Clean up duplicate songs in the database")
-	deleteCmd := flag.Int("delete", -1, "Delete a song by its ID")
-	flag.Parse()
-
-	// Load configuration
-	dir, _ := os.Getwd()
-	configFilePath := filepath.Join(dir, "configs", "config.yaml")
-	config, err := config.LoadConfig(configFilePath)
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the command line and returns the process exit code
+func run(args []string) int {
+	g := &globals{logFormat: "text"}
+
+	root := flag.NewFlagSet("eureka", flag.ContinueOnError)
+	g.register(root)
+	root.Usage = func() { printCommands(root, "eureka", commands) }
+	if err := root.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	cmd, path, rest, err := findCommand(commands, "eureka", root.Args())
 	if err != nil {
-		logger.Error(fmt.Errorf("failed to load configuration: %v", err))
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "eureka: %v\n\n", err)
+		root.Usage()
+		return exitUsage
 	}
 
-	// Get Eureka app
-	app, err := eureka.NewEureka(*config)
+	flags := flag.NewFlagSet(path, flag.ContinueOnError)
+	act := cmd.setup(flags)
+	g.register(flags)
+	flags.Usage = func() { printUsage(flags, path, cmd) }
+
+	positional, err := parseInterspersed(flags, rest)
 	if err != nil {
-		logger.Error(fmt.Errorf("error initializing Eureka: %v", err))
-		os.Exit(1)
+		return parseExitCode(err)
 	}
 
-	if *deleteCmd >= 0 {
-		if err := app.Delete(*deleteCmd); err != nil {
-			logger.Error(fmt.Errorf("error deleting song: %v", err))
-			os.Exit(1)
-		}
-		return
+	if err := g.setupLogging(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return exitUsage
+	}
+
+	// Cancel running operations on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = act(ctx, g, positional)
+	var usage usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errNoMatch):
+		return exitNoMatch
+	case errors.As(err, &usage):
+		fmt.Fprintf(os.Stderr, "%s: %v\n\n", path, err)
+		flags.Usage()
+		return exitUsage
+	case errors.Is(err, context.Canceled):
+		return exitError
+	default:
+		logger.Error(err)
+		return exitError
 	}
+}
 
-	if *cleanupCmd {
-		if err := app.Cleanup(); err != nil {
-			logger.Error(fmt.Errorf("error cleaning up duplicates: %v", err))
-			os.Exit(1)
+// findCommand resolves the command named by the leading arguments and returns it with
+// its full name and the remaining arguments
+func findCommand(list []*command, path string, args []string) (*command, string, []string, error) {
+	if len(args) == 0 {
+		return nil, path, nil, fmt.Errorf("missing command, expected one of: %s", commandNames(list))
+	}
+
+	for _, cmd := range list {
+		if cmd.name != args[0] {
+			continue
+		}
+		path = path + " " + cmd.name
+		if len(cmd.subcommands) == 0 {
+			return cmd, path, args[1:], nil
 		}
-		return
+		return findCommand(cmd.subcommands, path, args[1:])
 	}
+	return nil, "", nil, fmt.Errorf("unknown command %q, expected one of: %s", args[0], commandNames(list))
+}
 
-	if *listCmd {
-		songs, err := app.List()
-		if err != nil {
-			logger.Error(fmt.Errorf("error listing songs: %v", err))
-			os.Exit(1)
+// parseInterspersed parses flags placed before, between or after positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
 		}
-		if len(songs) == 0 {
-			logger.Info("No songs found in the database")
-			return
+		rest := flags.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			// Everything after -- is positional
+			return append(positional, rest...), nil
 		}
-		logger.Info("Found songs in database:")
-		for _, song := range songs {
-			fmt.Printf("ID: %d | Name: %s | Artist: %s | Fingerprinted: %v | Hashes: %d | Created: %s\n",
-				song.ID, song.Name, song.Artist, song.Fingerprinted, song.TotalHashes, song.DateCreated)
+		if len(rest) == 0 {
+			return positional, nil
 		}
-		return
+		positional = append(positional, rest[0])
+		args = rest[1:]
 	}
+}
 
-	if *microphoneCmd {
-		err := app.RecognizeFromMicrophone()
-		if err != nil {
-			logger.Error(fmt.Errorf("error in microphone recognition: %v", err))
-			os.Exit(1)
-		}
-		return
+// parseExitCode maps a flag parsing error to an exit code, -h and --help are not errors
+func parseExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
 	}
+	return exitUsage
+}
 
-	if *recognizeFile != "" {
-		matches, err := app.Recognize(*recognizeFile)
-		if err != nil {
-			logger.Error(fmt.Errorf("error recognizing audio file: %v", err))
-			os.Exit(1)
-		}
+// commandNames lists command names for error messages
+func commandNames(list []*command) string {
+	names := make([]string, len(list))
+	for i, cmd := range list {
+		names[i] = cmd.name
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
 
-		if len(matches) == 0 {
-			logger.Info("No matches found")
-			return
-		}
+// printCommands prints the top-level help
+func printCommands(flags *flag.FlagSet, path string, list []*command) {
+	out := flags.Output()
+	fmt.Fprintf(out, "Usage: %s [global flags] <command> [flags] [arguments]\n\nCommands:\n", path)
+	printCommandList(out, "", list)
+	fmt.Fprintf(out, "\nRun '%s <command> -h' for the flags of a command.\n\nGlobal flags:\n", path)
+	flags.PrintDefaults()
+}
 
-		logger.Info("Found matches:")
-		for i, match := range matches {
-			fmt.Printf("%d. %s by %s (Score: %.3f, Offset: %dms)\n",
-				i+1, match.SongName, match.Artist, match.Score, match.Offset)
+// printCommandList prints the runnable commands of list and of their subcommands,
+// prefixed by the names of their parents
+func printCommandList(out io.Writer, prefix string, list []*command) {
+	for _, cmd := range list {
+		name := strings.TrimSpace(prefix + " " + cmd.name)
+		if len(cmd.subcommands) == 0 {
+			fmt.Fprintf(out, "  %-28s %s\n", strings.TrimSpace(name+" "+cmd.args), cmd.summary)
+			continue
 		}
-		return
+		printCommandList(out, name, cmd.subcommands)
 	}
+}
 
-	if *audioFile == "" {
-		logger.Error(fmt.Errorf("please provide an audio file path using -file flag for adding songs, -recognize flag for recognition, -microphone flag for real-time recognition, or use -list to see database contents"))
-		flag.Usage()
-		os.Exit(1)
-	}
+// printUsage prints the help of a single command
+func printUsage(flags *flag.FlagSet, path string, cmd *command) {
+	out := flags.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] %s\n\n%s\n\nFlags:\n", path, cmd.args, cmd.summary)
+	flags.PrintDefaults()
+}
 
-	if err := app.Save(*audioFile); err != nil {
-		logger.Error(fmt.Errorf("failed to process audio file: %v", err))
-		os.Exit(1)
+// outputFlag registers the -output flag of commands printing results, in the shared
+// formats and the extra ones of the command
+func outputFlag(flags *flag.FlagSet, output *string, extra ...string) {
+	formats := append(append([]string(nil), eureka.OutputFormats...), extra...)
+	usage := strings.Join(formats[:len(formats)-1], ", ") + " or " + formats[len(formats)-1]
+	flags.StringVar(output, "output", "table", "Output format: "+usage)
+}
+
+// checkOutput validates an -output value as a usage error, accepting the extra formats
+// of the command
+func checkOutput(output string, extra ...string) error {
+	for _, format := range extra {
+		if strings.EqualFold(output, format) {
+			return nil
+		}
+	}
+	if err := eureka.CheckOutputFormat(output); err != nil {
+		if len(extra) > 0 {
+			return usagef("%v or %s", err, strings.Join(extra, ", "))
+		}
+		return usageError{message: err.Error()}
 	}
+	return nil
 }
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+var recognizeCommand = &command{
+	name:    "recognize",
+	args:    "<file|->",
+	summary: "Recognize an audio file, or an encoded stream (WAV, FLAC, MP3) from stdin with -",
+	setup: func(flags *flag.FlagSet) action {
+		raw := flags.String("raw", "", "Treat the input as headerless PCM in this format, as encoding:rate:channels (e.g. s16le:44100:2)")
+		var output string
+		outputFlag(flags, &output)
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 1 {
+				return usagef("expected one audio file or -")
+			}
+			if err := checkOutput(output); err != nil {
+				return err
+			}
+			var format audio.PCMFormat
+			if *raw != "" {
+				var err error
+				if format, err = audio.ParsePCMFormat(*raw); err != nil {
+					return usagef("%v", err)
+				}
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			matches, err := recognize(ctx, app, args[0], *raw != "", format)
+			if err != nil {
+				return fmt.Errorf("error recognizing audio file: %v", err)
+			}
+			return writeMatches(output, matches)
+		}
+	},
+}
+
+var listenCommand = &command{
+	name:    "listen",
+	summary: "Recognize live audio until a match is found or 30 seconds pass",
+	setup: func(flags *flag.FlagSet) action {
+		source := flags.String("source", "microphone", "Audio source: microphone, stdin (raw PCM), synthetic or a path to a WAV file played in real time")
+		sourceFormat := flags.String("source-format", "s16le:44100:1", "Raw PCM format for -source stdin, as encoding:rate:channels")
+		device := flags.String("device", "", "Input device for -source microphone, by index or name (default: system default input)")
+		inputRate := flags.Int("input-rate", 0, "Sample rate to record at with -source microphone (default: the device's default rate), resampled to 44100 Hz for analysis")
+		inputChannels := flags.Int("input-channels", 1, "Number of channels to record with -source microphone, downmixed to mono for analysis")
+		var output string
+		outputFlag(flags, &output)
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if err := checkOutput(output); err != nil {
+				return err
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			src, err := openAudioSource(*source, *sourceFormat, *device, *inputRate, *inputChannels)
+			if err != nil {
+				return fmt.Errorf("error opening audio source: %v", err)
+			}
+
+			match, found, err := app.RecognizeFromMicrophone(ctx, src)
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			if err != nil {
+				return fmt.Errorf("error in microphone recognition: %v", err)
+			}
+			matches := []eureka.Match{}
+			if found {
+				matches = append(matches, match)
+			}
+			return writeMatches(output, matches)
+		}
+	},
+}
+
+var timelineCommand = &command{
+	name:    "timeline",
+	args:    "<file>",
+	summary: "Split a long recording (mix, radio show, podcast) into identified songs",
+	setup: func(flags *flag.FlagSet) action {
+		var output string
+		outputFlag(flags, &output, "cue")
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 1 {
+				return usagef("expected one audio file")
+			}
+			if err := checkOutput(output, "cue"); err != nil {
+				return err
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			segments, err := app.RecognizeTimeline(ctx, args[0], eureka.DefaultTimelineOptions())
+			if err != nil {
+				return fmt.Errorf("error building timeline: %v", err)
+			}
+			if err := eureka.WriteTimeline(os.Stdout, output, args[0], segments); err != nil {
+				return fmt.Errorf("error writing timeline: %v", err)
+			}
+			return nil
+		}
+	},
+}
+
+var devicesCommand = &command{
+	name:    "devices",
+	summary: "List the audio input devices with their supported sample rates and channels",
+	setup: func(flags *flag.FlagSet) action {
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if err := listDevices(); err != nil {
+				return fmt.Errorf("error listing audio devices: %v", err)
+			}
+			return nil
+		}
+	},
+}
+
+// writeMatches prints recognition results, and reports errNoMatch when there are none
+func writeMatches(output string, matches []eureka.Match) error {
+	if len(matches) == 0 {
+		logger.Info("No matches found")
+	}
+	if err := eureka.WriteMatches(os.Stdout, output, matches); err != nil {
+		return fmt.Errorf("error writing matches: %v", err)
+	}
+	if len(matches) == 0 {
+		return errNoMatch
+	}
+	return nil
+}
+
+// openAudioSource creates the live audio source selected with -source
+func openAudioSource(name, format, device string, rate, channels int) (audio.Source, error) {
+	switch name {
+	case "microphone":
+		return audio.NewPortAudioSource(device, rate, channels, 1024)
+	case "stdin":
+		pcmFormat, err := audio.ParsePCMFormat(format)
+		if err != nil {
+			return nil, err
+		}
+		return audio.NewPCMSource(os.Stdin, pcmFormat), nil
+	case "synthetic":
+		return audio.NewSyntheticSource(44100, 1, 30, true, audio.Tones(1, 440, 660, 880)), nil
+	default:
+		return audio.NewWAVSource(name)
+	}
+}
+
+// recognize runs recognition on a file or, when path is -, on stdin. With raw set
+// the input is headerless PCM in format, otherwise files are converted by extension
+// and stdin is sniffed for its format.
+func recognize(ctx context.Context, app *eureka.Eureka, path string, raw bool, format audio.PCMFormat) ([]eureka.Match, error) {
+	if !raw && path != "-" {
+		return app.Recognize(ctx, path)
+	}
+
+	input := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening input file: %v", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	if !raw {
+		return app.RecognizeReader(ctx, input)
+	}
+	return app.RecognizePCM(ctx, input, format)
+}
+
+// listDevices prints the input devices usable with -device
+func listDevices() error {
+	devices, err := audio.ListInputDevices()
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		logger.Info("No audio input devices found")
+		return nil
+	}
+
+	for _, device := range devices {
+		marker := ""
+		if device.Default {
+			marker = " (default)"
+		}
+		rates := make([]string, len(device.SampleRates))
+		for i, rate := range device.SampleRates {
+			rates[i] = strconv.Itoa(rate)
+		}
+		fmt.Printf("%d: %s%s | Host API: %s | Channels: %d | Default rate: %d Hz | Rates: %s\n",
+			device.Index, device.Name, marker, device.HostAPI, device.MaxInputChannels,
+			device.DefaultSampleRate, strings.Join(rates, ", "))
+	}
+	return nil
+}
+
+var historyCommand = &command{
+	name:    "history",
+	summary: "List the recorded recognitions, newest first",
+	setup: func(flags *flag.FlagSet) action {
+		var output string
+		outputFlag(flags, &output)
+		source := flags.String("source", "", "Only recognitions from this source: "+strings.Join(eureka.Sources, ", "))
+		client := flags.String("client", "", "Only recognitions requested by this API client")
+		song := flags.Int("song", 0, "Only recognitions whose best match is this song ID")
+		matched := flags.String("matched", "", "Only recognitions that matched a song (true) or not (false)")
+		since := flags.String("since", "", "Only recognitions at or after this date (YYYY-MM-DD or RFC 3339)")
+		until := flags.String("until", "", "Only recognitions up to this date included (YYYY-MM-DD or RFC 3339)")
+		limit := flags.Int("limit", 50, "Maximum number of recognitions, 0 for all")
+		offset := flags.Int("offset", 0, "Number of recognitions to skip")
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if err := checkOutput(output); err != nil {
+				return err
+			}
+			if *source != "" && !slices.Contains(eureka.Sources, *source) {
+				return usagef("invalid -source %q, expected one of %s", *source, strings.Join(eureka.Sources, ", "))
+			}
+			if *song < 0 {
+				return usagef("invalid -song %d", *song)
+			}
+
+			query := mysql.RecognitionQuery{
+				Source:   *source,
+				ClientID: *client,
+				SongID:   *song,
+				Limit:    *limit,
+				Offset:   *offset,
+			}
+			if *matched != "" {
+				value, err := strconv.ParseBool(*matched)
+				if err != nil {
+					return usagef("invalid -matched %q, expected true or false", *matched)
+				}
+				query.Matched = &value
+			}
+			var err error
+			if query.Since, err = parseDate(*since, false); err != nil {
+				return usagef("invalid -since: %v", err)
+			}
+			if query.Until, err = parseDate(*until, true); err != nil {
+				return usagef("invalid -until: %v", err)
+			}
+			if err := query.Check(); err != nil {
+				return usagef("%v", err)
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			page, err := app.History(ctx, query)
+			if err != nil {
+				return fmt.Errorf("error listing recognitions: %v", err)
+			}
+			if page.Total == 0 {
+				logger.Info("No recognitions recorded")
+			} else if len(page.Recognitions) < page.Total {
+				logger.Info("Listed a page of recognitions", "from", query.Offset+1, "to", query.Offset+len(page.Recognitions), "total", page.Total)
+			}
+			if err := eureka.WriteRecognitions(os.Stdout, output, page.Recognitions); err != nil {
+				return fmt.Errorf("error writing recognitions: %v", err)
+			}
+			return nil
+		}
+	},
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/media-luna/eureka/internal/grpcserver"
+	"github.com/media-luna/eureka/internal/monitor"
+	"github.com/media-luna/eureka/internal/server"
+	"github.com/media-luna/eureka/internal/tracing"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// traceFlushTimeout bounds exporting the pending spans when eureka serve stops
+const traceFlushTimeout = 5 * time.Second
+
+var serveCommand = &command{
+	name:    "serve",
+	summary: "Serve the HTTP API, and the gRPC API when it has an address, until interrupted",
+	setup: func(flags *flag.FlagSet) action {
+		addr := flags.String("addr", "", "Address to listen on (default: server.address in the configuration)")
+		grpcAddr := flags.String("grpc-addr", "", "Address of the gRPC API (default: server.grpc_address in the configuration, empty disables it)")
+		maxUpload := flags.Int("max-upload-mb", 0, "Largest accepted upload in MB (default: server.max_upload_mb in the configuration)")
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			cfg, err := g.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			// Flags are the last configuration layer, validated with the rest
+			if *addr != "" {
+				cfg.Server.Address = *addr
+			}
+			if *grpcAddr != "" {
+				cfg.Server.GRPCAddress = *grpcAddr
+			}
+			if *maxUpload > 0 {
+				cfg.Server.MaxUploadMB = *maxUpload
+			}
+
+			shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, cfg.Config.Version)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				// ctx is done once interrupted, the pending spans are flushed anyway
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), traceFlushTimeout)
+				defer cancel()
+				if err := shutdownTracing(ctx); err != nil {
+					logger.Warn("Error flushing traces", "error", err)
+				}
+			}()
+
+			app, err := connect(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			// Both APIs share app and its connection pool, the first failure stops both
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			errs := make(chan error, 2)
+			servers := 1
+			go func() {
+				errs <- server.NewServer(app, cfg.Server).ListenAndServe(ctx)
+			}()
+			if cfg.Server.GRPCAddress != "" {
+				servers++
+				go func() {
+					errs <- grpcserver.NewServer(app, cfg.Server).ListenAndServe(ctx)
+				}()
+			}
+
+			var first error
+			for i := 0; i < servers; i++ {
+				if err := <-errs; err != nil && first == nil {
+					first = err
+					cancel()
+				}
+			}
+			return first
+		}
+	},
+}
+
+var monitorCommand = &command{
+	name:    "monitor",
+	summary: "Run the broadcast monitor on the channels configured under monitor",
+	setup: func(flags *flag.FlagSet) action {
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 0 {
+				return usagef("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			cfg, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			mon, err := monitor.NewMonitor(app, cfg.Monitor)
+			if err != nil {
+				return fmt.Errorf("error initializing monitor: %v", err)
+			}
+			mon.Run(ctx)
+			return nil
+		}
+	},
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+var ingestCommand = &command{
+	name:    "ingest",
+	args:    "<file>",
+	summary: "Fingerprint an audio file (WAV, FLAC, MP3) and add it to the database",
+	setup: func(flags *flag.FlagSet) action {
+		title := flags.String("title", "", "Song title (default: taken from a file named Artist--Title.ext)")
+		artist := flags.String("artist", "", "Song artist (default: taken from a file named Artist--Title.ext)")
+		var output string
+		outputFlag(flags, &output)
+
+		return func(ctx context.Context, g *globals, args []string) error {
+			if len(args) != 1 {
+				return usagef("expected one audio file")
+			}
+			if err := checkOutput(output); err != nil {
+				return err
+			}
+
+			path := args[0]
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("error stating path: %v", err)
+			}
+			if info.IsDir() {
+				return usagef("%s is a directory, expected a file", path)
+			}
+
+			parsedArtist, parsedTitle := eureka.ParseSongFilename(path)
+			if *title == "" {
+				*title = parsedTitle
+			}
+			if *artist == "" {
+				*artist = parsedArtist
+			}
+
+			_, app, err := g.loadApp(ctx)
+			if err != nil {
+				return err
+			}
+			defer app.Close()
+
+			logger.Info("Processing audio file", "file", path)
+			result, err := app.Ingest(ctx, path, *title, *artist, true)
+			if err != nil {
+				return fmt.Errorf("failed to process audio file: %v", err)
+			}
+			if err := eureka.WriteSaveResult(os.Stdout, output, result); err != nil {
+				return fmt.Errorf("error writing summary: %v", err)
+			}
+			return nil
+		}
+	},
+}
+
+var songsCommand = &command{
+	name: "songs",
+	subcommands: []*command{
+		{
+			name:    "list",
+			summary: "List the songs in the database, a page at a time",
+			setup: func(flags *flag.FlagSet) action {
+				var output string
+				outputFlag(flags, &output)
+				name := flags.String("name", "", "Only songs whose name contains this text, ignoring case")
+				artist := flags.String("artist", "", "Only songs whose artist contains this text, ignoring case")
+				fingerprinted := flags.String("fingerprinted", "", "Only fingerprinted (true) or incomplete (false) songs")
+				since := flags.String("since", "", "Only songs added at or after this date (YYYY-MM-DD or RFC 3339)")
+				until := flags.String("until", "", "Only songs added up to this date included (YYYY-MM-DD or RFC 3339)")
+				sort := flags.String("sort", "id", "Sort by "+strings.Join(catalog.SongSortFields, ", "))
+				desc := flags.Bool("desc", false, "Sort in descending order")
+				limit := flags.Int("limit", 50, "Maximum number of songs, 0 for all")
+				offset := flags.Int("offset", 0, "Number of songs to skip")
+
+				return func(ctx context.Context, g *globals, args []string) error {
+					if len(args) != 0 {
+						return usagef("unexpected arguments: %s", strings.Join(args, " "))
+					}
+					if err := checkOutput(output); err != nil {
+						return err
+					}
+
+					query := catalog.SongQuery{
+						Name:       *name,
+						Artist:     *artist,
+						Sort:       *sort,
+						Descending: *desc,
+						Limit:      *limit,
+						Offset:     *offset,
+					}
+					if *fingerprinted != "" {
+						value, err := strconv.ParseBool(*fingerprinted)
+						if err != nil {
+							return usagef("invalid -fingerprinted %q, expected true or false", *fingerprinted)
+						}
+						query.Fingerprinted = &value
+					}
+					var err error
+					if query.CreatedAfter, err = parseDate(*since, false); err != nil {
+						return usagef("invalid -since: %v", err)
+					}
+					if query.CreatedBefore, err = parseDate(*until, true); err != nil {
+						return usagef("invalid -until: %v", err)
+					}
+					if err := query.Check(); err != nil {
+						return usagef("%v", err)
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					page, err := app.List(ctx, query)
+					if err != nil {
+						return fmt.Errorf("error listing songs: %v", err)
+					}
+					if page.Total == 0 {
+						logger.Info("No songs found in the database")
+					} else if len(page.Songs) < page.Total {
+						logger.Info("Listed a page of songs", "from", query.Offset+1, "to", query.Offset+len(page.Songs), "total", page.Total)
+					}
+					if err := eureka.WriteSongs(os.Stdout, output, page.Songs); err != nil {
+						return fmt.Errorf("error writing songs: %v", err)
+					}
+					return nil
+				}
+			},
+		},
+		{
+			name:    "show",
+			args:    "<id>",
+			summary: "Show a song",
+			setup: func(flags *flag.FlagSet) action {
+				var output string
+				outputFlag(flags, &output)
+
+				return func(ctx context.Context, g *globals, args []string) error {
+					id, err := songID(args)
+					if err != nil {
+						return err
+					}
+					if err := checkOutput(output); err != nil {
+						return err
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					song, err := app.GetSong(ctx, id)
+					if err != nil {
+						return fmt.Errorf("error getting song: %v", err)
+					}
+					if err := eureka.WriteSongInfo(os.Stdout, output, song); err != nil {
+						return fmt.Errorf("error writing song: %v", err)
+					}
+					return nil
+				}
+			},
+		},
+		{
+			name:    "edit",
+			args:    "<id>",
+			summary: "Change the title, artist or album of a song, its fingerprints are kept",
+			setup: func(flags *flag.FlagSet) action {
+				title := flags.String("title", "", "New song title")
+				artist := flags.String("artist", "", "New artist, an empty value clears it")
+				album := flags.String("album", "", "New album, an empty value clears it")
+				var output string
+				outputFlag(flags, &output)
+
+				return func(ctx context.Context, g *globals, args []string) error {
+					id, err := songID(args)
+					if err != nil {
+						return err
+					}
+					if err := checkOutput(output); err != nil {
+						return err
+					}
+
+					// Only the flags given on the command line are changed
+					var update mysql.SongUpdate
+					flags.Visit(func(f *flag.Flag) {
+						switch f.Name {
+						case "title":
+							update.Name = title
+						case "artist":
+							update.Artist = artist
+						case "album":
+							update.Album = album
+						}
+					})
+					if update == (mysql.SongUpdate{}) {
+						return usagef("nothing to change, set -title, -artist or -album")
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					song, err := app.UpdateSong(ctx, id, update)
+					if errors.Is(err, eureka.ErrInvalidMetadata) {
+						return usageError{message: err.Error()}
+					}
+					if err != nil {
+						return fmt.Errorf("error updating song: %v", err)
+					}
+					if err := eureka.WriteSongInfo(os.Stdout, output, song); err != nil {
+						return fmt.Errorf("error writing song: %v", err)
+					}
+					return nil
+				}
+			},
+		},
+		{
+			name:    "delete",
+			args:    "<id>",
+			summary: "Delete a song and its fingerprints",
+			setup: func(flags *flag.FlagSet) action {
+				return func(ctx context.Context, g *globals, args []string) error {
+					id, err := songID(args)
+					if err != nil {
+						return err
+					}
+
+					_, app, err := g.loadApp(ctx)
+					if err != nil {
+						return err
+					}
+					defer app.Close()
+
+					if err := app.Delete(ctx, id); err != nil {
+						return fmt.Errorf("error deleting song: %v", err)
+					}
+					return nil
+				}
+			},
+		},
+	},
+}
+
+// songID parses the single song ID argument of a command
+func songID(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, usagef("expected one song ID")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id < 0 {
+		return 0, usagef("invalid song ID %q", args[0])
+	}
+	return id, nil
+}
+
+// parseDate parses a YYYY-MM-DD date in the local time zone or an RFC 3339 time, the
+// empty string is the zero time. A date is its first instant, or the first instant of
+// the next day with endOfDay, so that it includes the whole day as an exclusive bound.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or an RFC 3339 time, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -1,11 +1,5 @@
 package config
 
-import (
-	"os"
-
-	"gopkg.in/yaml.v3"
-)
-
 // DBConfig represents database connection settings
 type DBConfig struct {
 	Type     string `yaml:"type"`
@@ -25,6 +19,7 @@ type Tables struct {
 			ID            string `yaml:"id"`
 			Name          string `yaml:"name"`
 			Artist        string `yaml:"artist"`
+			Album         string `yaml:"album"`
 			Fingerprinted string `yaml:"fingerprinted"`
 			FileSHA1      string `yaml:"file_sha1"`
 			TotalHashes   string `yaml:"total_hashes"`
@@ -38,6 +33,68 @@ type Tables struct {
 			Offset string `yaml:"offset"`
 		} `yaml:"fields"`
 	} `yaml:"fingerprints"`
+
+	Plays struct {
+		Name string `yaml:"name"`
+	} `yaml:"plays"`
+
+	StopList struct {
+		Name string `yaml:"name"`
+	} `yaml:"stoplist"`
+
+	History struct {
+		Name string `yaml:"name"`
+	} `yaml:"history"`
+}
+
+// MonitorChannel represents a single continuous audio feed watched by the monitor
+type MonitorChannel struct {
+	Name       string `yaml:"name"`
+	Source     string `yaml:"source"` // File path, named pipe or http(s) URL of 16-bit PCM audio
+	SampleRate int    `yaml:"sample_rate"`
+	Channels   int    `yaml:"channels"`
+}
+
+// Monitor represents broadcast monitoring settings
+type Monitor struct {
+	WindowSeconds      int              `yaml:"window_seconds"`
+	HopSeconds         int              `yaml:"hop_seconds"`
+	MinScore           float64          `yaml:"min_score"`
+	PlayTimeoutSeconds int              `yaml:"play_timeout_seconds"`
+	Channels           []MonitorChannel `yaml:"channels"`
+}
+
+// StopList represents the settings of db stoplist build, which stop-lists the hashes
+// found in too many songs to tell them apart
+type StopList struct {
+	MaxDocumentFrequency float64 `yaml:"max_document_frequency"` // Hashes in a larger share of the fingerprinted songs are stop-listed
+	MinSongs             int     `yaml:"min_songs"`              // Hashes in fewer songs are never stop-listed, for small catalogs
+}
+
+// History represents the recognition history settings
+type History struct {
+	Enabled       bool `yaml:"enabled"`        // Record every recognition and microphone session
+	RetentionDays int  `yaml:"retention_days"` // Older recognitions are deleted, 0 keeps them forever
+}
+
+// Server represents the HTTP API settings used by eureka serve
+type Server struct {
+	Address             string `yaml:"address"`
+	GRPCAddress         string `yaml:"grpc_address"`           // gRPC API, disabled when empty
+	MaxUploadMB         int    `yaml:"max_upload_mb"`          // Largest accepted request body
+	LiveTimeoutSeconds  int    `yaml:"live_timeout_seconds"`   // Longest live recognition session
+	LiveMaxAudioSeconds int    `yaml:"live_max_audio_seconds"` // Most audio analyzed per live session
+	// Origins of the web pages allowed to open live sessions besides the API's own, "*"
+	// allows any. Clients sending no Origin header, such as CLI tools, are always allowed.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// Tracing represents the OpenTelemetry settings of eureka serve
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`     // OTLP gRPC collector as host:port, $OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317 when empty
+	Insecure    bool    `yaml:"insecure"`     // Connect to the collector without TLS
+	SampleRatio float64 `yaml:"sample_ratio"` // Share of the traces started by eureka that are recorded
 }
 
 // Config represents the main application configuration
@@ -63,23 +120,22 @@ type Config struct {
 		TopResults int `yaml:"top_results"`
 	} `yaml:"recognition"`
 
+	StopList StopList `yaml:"stoplist"`
+
+	History History `yaml:"history"`
+
+	Monitor Monitor `yaml:"monitor"`
+
+	Server Server `yaml:"server"`
+
+	Tracing Tracing `yaml:"tracing"`
+
 	Database DBConfig `yaml:"database"`
 	Tables   Tables   `yaml:"tables"`
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML file layered over the defaults and
+// the EUREKA_* environment variables, see Load
 func LoadConfig(filePath string) (*Config, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	var cfg Config
-	decoder := yaml.NewDecoder(file)
-	if err := decoder.Decode(&cfg); err != nil {
-		return nil, err
-	}
-
-	return &cfg, nil
+	return Load(filePath, nil)
 }
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a configuration file in a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultMatchesConfigFile(t *testing.T) {
+	cfg, err := Load("config.yaml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The shipped file sets a placeholder password and empty lists
+	cfg.Database.Password = ""
+	cfg.Monitor.Channels = nil
+	cfg.Server.AllowedOrigins = nil
+
+	want := Default()
+	if !reflect.DeepEqual(*cfg, want) {
+		t.Fatalf("config.yaml = %+v\nDefault() = %+v", *cfg, want)
+	}
+	if err := want.Validate(); err != nil {
+		t.Fatalf("Default() is invalid: %v", err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  host: file-host
+  user: file-user
+  port: 3307
+server:
+  max_upload_mb: 10
+`)
+	t.Setenv("EUREKA_DATABASE_HOST", "env-host")
+	t.Setenv("EUREKA_DATABASE_USER", "env-user")
+	secret := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EUREKA_DATABASE_PASSWORD_FILE", secret)
+
+	cfg, err := Load(path, []string{"database.host=flag-host"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		setting string
+		got     interface{}
+		want    interface{}
+	}{
+		{"database.host (flag over env over file)", cfg.Database.Host, "flag-host"},
+		{"database.user (env over file)", cfg.Database.User, "env-user"},
+		{"database.port (file over default)", cfg.Database.Port, 3307},
+		{"server.max_upload_mb (file over default)", cfg.Server.MaxUploadMB, 10},
+		{"database.password (env file)", cfg.Database.Password, "s3cret"},
+		{"database.db_name (default)", cfg.Database.DBName, "eureka"},
+		{"recognition.top_results (default)", cfg.Recognition.TopResults, 5},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.setting, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []string
+		env       map[string]string
+		want      []string
+	}{
+		{"unknown setting", []string{"database.hots=x"}, nil, []string{`unknown setting "database.hots"`}},
+		{"missing value", []string{"database.host"}, nil, []string{"expected key=value"}},
+		{"invalid override", []string{"database.port=ten"}, nil, []string{"database.port", `"ten"`}},
+		{
+			"every invalid variable",
+			nil,
+			map[string]string{"EUREKA_DATABASE_PORT": "ten", "EUREKA_TRACING_ENABLED": "maybe"},
+			[]string{"EUREKA_DATABASE_PORT", "EUREKA_TRACING_ENABLED"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			_, err := Load("", tt.overrides)
+			if err == nil {
+				t.Fatal("Load succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryInvalidField(t *testing.T) {
+	cfg := Default()
+	cfg.Config.OverlapRatio = 1
+	cfg.Recognition.TopResults = 0
+	cfg.Monitor.Channels = []MonitorChannel{
+		{Name: "radio", Source: "http://radio", SampleRate: 44100, Channels: 2},
+		{Name: "radio", SampleRate: 0, Channels: 1},
+	}
+	cfg.Server.AllowedOrigins = []string{"https://app.example.com", "*", "app.example.com", "https://app.example.com/live"}
+	cfg.Database.Type = "sqlite"
+	cfg.Database.Port = 70000
+	cfg.Tables.Songs.Name = "songs; DROP TABLE songs"
+
+	err := cfg.Validate()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Validate() = %v, want a *ValidationError", err)
+	}
+
+	var fields []string
+	for _, f := range invalid.Fields {
+		fields = append(fields, f.Field)
+	}
+	want := []string{
+		"config.overlap_ratio",
+		"recognition.top_results",
+		"monitor.channels[1].name",
+		"monitor.channels[1].source",
+		"monitor.channels[1].sample_rate",
+		"server.allowed_origins[2]",
+		"server.allowed_origins[3]",
+		"database.type",
+		"database.port",
+		"tables.songs.name",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("invalid fields = %v, want %v", fields, want)
+	}
+}
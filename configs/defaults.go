@@ -0,0 +1,79 @@
+package config
+
+// Default returns the configuration shipped in config.yaml, for programs that
+// embed Eureka without a configuration file
+func Default() Config {
+	var cfg Config
+
+	cfg.Config.Name = "eureka"
+	cfg.Config.Version = "1.0.0"
+	cfg.Config.ConnectivityMask = 2
+	cfg.Config.SamplingRate = 44100
+	cfg.Config.FFTWindowSize = 4096
+	cfg.Config.OverlapRatio = 0.75
+	cfg.Config.FanValue = 15
+	cfg.Config.AmplitudeMin = 10
+	cfg.Config.PeakNeighborhoodSize = 3
+	cfg.Config.MinHashTimeDelta = 0
+	cfg.Config.MaxHashTimeDelta = 2000
+	cfg.Config.PeakSort = true
+	cfg.Config.FingerprintReduction = 20
+
+	cfg.Recognition.TopResults = 5
+
+	cfg.StopList = StopList{
+		MaxDocumentFrequency: 0.02,
+		MinSongs:             10,
+	}
+
+	cfg.History = History{
+		Enabled:       true,
+		RetentionDays: 90,
+	}
+
+	cfg.Monitor = Monitor{
+		WindowSeconds:      5,
+		HopSeconds:         2,
+		MinScore:           0.3,
+		PlayTimeoutSeconds: 10,
+	}
+
+	cfg.Server = Server{
+		Address:             ":8080",
+		GRPCAddress:         ":9090",
+		MaxUploadMB:         50,
+		LiveTimeoutSeconds:  30,
+		LiveMaxAudioSeconds: 30,
+	}
+
+	cfg.Tracing = Tracing{
+		Insecure:    true,
+		SampleRatio: 1,
+	}
+
+	cfg.Database = DBConfig{
+		Type:   "mysql",
+		User:   "mysql",
+		DBName: "eureka",
+		Host:   "localhost",
+		Port:   3306,
+		Params: "parseTime=true&charset=utf8mb4",
+	}
+
+	cfg.Tables.Songs.Name = "songs"
+	cfg.Tables.Songs.Fields.ID = "song_id"
+	cfg.Tables.Songs.Fields.Name = "song_name"
+	cfg.Tables.Songs.Fields.Artist = "artist"
+	cfg.Tables.Songs.Fields.Album = "album"
+	cfg.Tables.Songs.Fields.Fingerprinted = "fingerprinted"
+	cfg.Tables.Songs.Fields.FileSHA1 = "file_sha1"
+	cfg.Tables.Songs.Fields.TotalHashes = "total_hashes"
+	cfg.Tables.Fingerprints.Name = "fingerprints"
+	cfg.Tables.Fingerprints.Fields.Hash = "hash"
+	cfg.Tables.Fingerprints.Fields.Offset = "offset"
+	cfg.Tables.Plays.Name = "plays"
+	cfg.Tables.StopList.Name = "stoplist"
+	cfg.Tables.History.Name = "recognitions"
+
+	return cfg
+}
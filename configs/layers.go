@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of every environment variable overriding a setting
+const EnvPrefix = "EUREKA_"
+
+// Load builds the configuration in layers: the defaults, then the YAML file at path
+// (skipped when path is empty), then EUREKA_* environment variables, then overrides
+// given as key=value pairs with dotted keys such as database.host. Secret references
+// are resolved last. The result is not validated, call Validate before using it.
+func Load(path string, overrides []string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		// Settings missing from the file keep their default
+		if err := yaml.NewDecoder(file).Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid override %q, expected key=value", override)
+		}
+		if err := cfg.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.ResolveSecrets(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ApplyEnv overrides settings from environment variables named after their YAML path,
+// e.g. EUREKA_DATABASE_HOST or EUREKA_SERVER_MAX_UPLOAD_MB. A variable with a _FILE
+// suffix, e.g. EUREKA_DATABASE_PASSWORD_FILE, is read from the file it names instead.
+// Every invalid value is reported.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, s := range c.settings() {
+		name := EnvPrefix + strings.ToUpper(strings.Join(s.path, "_"))
+
+		value, ok := lookup(name)
+		if fileName, isFile := lookup(name + "_FILE"); isFile {
+			content, err := readSecretFile(fileName)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s_FILE: %v", name, err))
+				continue
+			}
+			value, ok = content, true
+		}
+		if !ok {
+			continue
+		}
+
+		if err := s.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Set overrides one setting by its dotted YAML path, e.g. database.port
+func (c *Config) Set(key, value string) error {
+	for _, s := range c.settings() {
+		if strings.Join(s.path, ".") == key {
+			if err := s.set(value); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown setting %q", key)
+}
+
+// ResolveSecrets replaces secret references with their value, so that passwords need
+// not be stored in the configuration file: "env:NAME" reads the environment variable
+// NAME and "file:PATH" reads the file at PATH without its trailing newline.
+func (c *Config) ResolveSecrets() error {
+	secrets := map[string]*string{
+		"database.password": &c.Database.Password,
+	}
+
+	var errs []error
+	for key, value := range secrets {
+		resolved, err := resolveSecret(*value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", key, err))
+			continue
+		}
+		*value = resolved
+	}
+	return errors.Join(errs...)
+}
+
+// resolveSecret returns the value of a secret reference, other values are returned as is
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, "file:"):
+		return readSecretFile(strings.TrimPrefix(value, "file:"))
+	default:
+		return value, nil
+	}
+}
+
+// readSecretFile reads a secret stored in a file, such as a Docker or Kubernetes secret
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file: %v", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// setting is a scalar field of the configuration addressed by its YAML path
+type setting struct {
+	path  []string
+	value reflect.Value
+}
+
+// settings lists the scalar fields of c, lists such as the monitor channels can only
+// be set in the file
+func (c *Config) settings() []setting {
+	var settings []setting
+	collectSettings(reflect.ValueOf(c).Elem(), nil, &settings)
+	return settings
+}
+
+// collectSettings appends the scalar fields of the struct v under path
+func collectSettings(v reflect.Value, path []string, settings *[]setting) {
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldPath := append(append([]string(nil), path...), name)
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			collectSettings(field, fieldPath, settings)
+		case reflect.String, reflect.Int, reflect.Float64, reflect.Bool:
+			*settings = append(*settings, setting{path: fieldPath, value: field})
+		}
+	}
+}
+
+// set parses value into the field
+func (s setting) set(value string) error {
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		s.value.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		s.value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		s.value.SetBool(b)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// identifierPattern matches the table and column names that can be used in SQL unquoted
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DatabaseTypes lists the supported values of database.type
+var DatabaseTypes = []string{"mysql", "postgres"}
+
+// FieldError reports an invalid setting by its dotted YAML path
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError lists every invalid setting found by Validate
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Error()
+	}
+	return "invalid configuration: " + strings.Join(messages, "; ")
+}
+
+// validator collects every invalid field instead of stopping at the first one
+type validator struct {
+	fields []*FieldError
+}
+
+// check records message for field unless ok
+func (v *validator) check(ok bool, field string, format string, args ...interface{}) {
+	if !ok {
+		v.fields = append(v.fields, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// positive checks that an integer setting is above zero
+func (v *validator) positive(field string, value int) {
+	v.check(value > 0, field, "must be positive, got %d", value)
+}
+
+// identifier checks that a table or column name is safe to use in queries
+func (v *validator) identifier(field string, value string) {
+	v.check(identifierPattern.MatchString(value), field, "must be a valid SQL identifier (letters, digits and underscores), got %q", value)
+}
+
+// Validate reports every invalid setting in a *ValidationError, or returns nil when
+// the configuration is usable
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.positive("config.sampling_rate", c.Config.SamplingRate)
+	v.positive("config.fft_window_size", c.Config.FFTWindowSize)
+	v.check(c.Config.OverlapRatio >= 0 && c.Config.OverlapRatio < 1, "config.overlap_ratio", "must be in [0, 1), got %g", c.Config.OverlapRatio)
+	v.positive("config.fan_value", c.Config.FanValue)
+	v.check(c.Config.MinHashTimeDelta >= 0, "config.min_hash_time_delta", "must not be negative, got %d", c.Config.MinHashTimeDelta)
+	v.check(c.Config.MaxHashTimeDelta > c.Config.MinHashTimeDelta, "config.max_hash_time_delta",
+		"must be greater than min_hash_time_delta (%d), got %d", c.Config.MinHashTimeDelta, c.Config.MaxHashTimeDelta)
+	v.positive("recognition.top_results", c.Recognition.TopResults)
+	v.check(c.StopList.MaxDocumentFrequency > 0 && c.StopList.MaxDocumentFrequency <= 1, "stoplist.max_document_frequency",
+		"must be in (0, 1], got %g", c.StopList.MaxDocumentFrequency)
+	v.positive("stoplist.min_songs", c.StopList.MinSongs)
+	v.check(c.History.RetentionDays >= 0, "history.retention_days", "must not be negative, got %d", c.History.RetentionDays)
+
+	v.positive("monitor.window_seconds", c.Monitor.WindowSeconds)
+	v.positive("monitor.hop_seconds", c.Monitor.HopSeconds)
+	v.check(c.Monitor.MinScore >= 0 && c.Monitor.MinScore <= 1, "monitor.min_score", "must be in [0, 1], got %g", c.Monitor.MinScore)
+	v.check(c.Monitor.PlayTimeoutSeconds >= 0, "monitor.play_timeout_seconds", "must not be negative, got %d", c.Monitor.PlayTimeoutSeconds)
+	names := make(map[string]bool)
+	for i, ch := range c.Monitor.Channels {
+		field := fmt.Sprintf("monitor.channels[%d]", i)
+		v.check(ch.Name != "", field+".name", "must not be empty")
+		v.check(!names[ch.Name], field+".name", "duplicate channel name %q", ch.Name)
+		names[ch.Name] = true
+		v.check(ch.Source != "", field+".source", "must not be empty")
+		v.positive(field+".sample_rate", ch.SampleRate)
+		v.positive(field+".channels", ch.Channels)
+	}
+
+	v.check(c.Server.Address != "", "server.address", "must not be empty")
+	v.positive("server.max_upload_mb", c.Server.MaxUploadMB)
+	v.positive("server.live_timeout_seconds", c.Server.LiveTimeoutSeconds)
+	v.positive("server.live_max_audio_seconds", c.Server.LiveMaxAudioSeconds)
+	for i, origin := range c.Server.AllowedOrigins {
+		u, err := url.Parse(origin)
+		valid := origin == "*" || (err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == "" && u.RawQuery == "")
+		v.check(valid, fmt.Sprintf("server.allowed_origins[%d]", i), "must be \"*\" or scheme://host[:port], got %q", origin)
+	}
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be in [0, 1], got %g", c.Tracing.SampleRatio)
+
+	supported := false
+	for _, t := range DatabaseTypes {
+		supported = supported || c.Database.Type == t
+	}
+	v.check(supported, "database.type", "unsupported database type %q, expected one of %v", c.Database.Type, DatabaseTypes)
+	v.check(c.Database.Host != "", "database.host", "must not be empty")
+	v.check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port", "must be between 1 and 65535, got %d", c.Database.Port)
+	v.check(c.Database.User != "", "database.user", "must not be empty")
+	v.check(c.Database.DBName != "", "database.db_name", "must not be empty")
+
+	v.identifier("tables.songs.name", c.Tables.Songs.Name)
+	v.identifier("tables.songs.fields.id", c.Tables.Songs.Fields.ID)
+	v.identifier("tables.songs.fields.name", c.Tables.Songs.Fields.Name)
+	v.identifier("tables.songs.fields.artist", c.Tables.Songs.Fields.Artist)
+	v.identifier("tables.songs.fields.album", c.Tables.Songs.Fields.Album)
+	v.identifier("tables.songs.fields.fingerprinted", c.Tables.Songs.Fields.Fingerprinted)
+	v.identifier("tables.songs.fields.file_sha1", c.Tables.Songs.Fields.FileSHA1)
+	v.identifier("tables.songs.fields.total_hashes", c.Tables.Songs.Fields.TotalHashes)
+	v.identifier("tables.fingerprints.name", c.Tables.Fingerprints.Name)
+	v.identifier("tables.fingerprints.fields.hash", c.Tables.Fingerprints.Fields.Hash)
+	v.identifier("tables.fingerprints.fields.offset", c.Tables.Fingerprints.Fields.Offset)
+	v.identifier("tables.plays.name", c.Tables.Plays.Name)
+	v.identifier("tables.stoplist.name", c.Tables.StopList.Name)
+	v.identifier("tables.history.name", c.Tables.History.Name)
+
+	if len(v.fields) > 0 {
+		return &ValidationError{Fields: v.fields}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package audio
+
+// StandardSampleRates are the rates probed when listing input devices
+var StandardSampleRates = []int{8000, 11025, 16000, 22050, 32000, 44100, 48000, 88200, 96000}
+
+// DeviceInfo describes an audio input device
+type DeviceInfo struct {
+	Index             int
+	Name              string
+	HostAPI           string
+	MaxInputChannels  int
+	DefaultSampleRate int
+	SampleRates       []int // Standard rates the device accepts in mono
+	Default           bool  // Whether this is the system default input device
+}
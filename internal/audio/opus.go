@@ -0,0 +1,43 @@
+//go:build opus
+
+package audio
+
+import (
+	"fmt"
+
+	"gopkg.in/hraban/opus.v2"
+)
+
+// maxOpusFrameMs is the longest duration an Opus packet can hold
+const maxOpusFrameMs = 120
+
+// OpusDecoder decodes a stream of Opus packets into interleaved float32 samples
+type OpusDecoder struct {
+	decoder  *opus.Decoder
+	channels int
+	pcm      []float32
+}
+
+// NewOpusDecoder creates a decoder for packets encoded at sampleRate (8000, 12000,
+// 16000, 24000 or 48000) with the given number of channels
+func NewOpusDecoder(sampleRate, channels int) (*OpusDecoder, error) {
+	decoder, err := opus.NewDecoder(sampleRate, channels)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Opus decoder: %v", err)
+	}
+
+	return &OpusDecoder{
+		decoder:  decoder,
+		channels: channels,
+		pcm:      make([]float32, sampleRate*maxOpusFrameMs/1000*channels),
+	}, nil
+}
+
+// Decode decodes one packet. The returned slice is reused by the next call.
+func (d *OpusDecoder) Decode(packet []byte) ([]float32, error) {
+	n, err := d.decoder.DecodeFloat32(packet, d.pcm)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding Opus packet: %v", err)
+	}
+	return d.pcm[:n*d.channels], nil
+}
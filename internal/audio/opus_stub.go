@@ -0,0 +1,18 @@
+//go:build !opus
+
+package audio
+
+import "fmt"
+
+// OpusDecoder is unavailable in builds without the opus tag
+type OpusDecoder struct{}
+
+// NewOpusDecoder reports that Opus support was not compiled in
+func NewOpusDecoder(sampleRate, channels int) (*OpusDecoder, error) {
+	return nil, fmt.Errorf("decoding Opus requires libopus, rebuild with -tags opus")
+}
+
+// Decode is never reached, NewOpusDecoder always fails
+func (d *OpusDecoder) Decode(packet []byte) ([]float32, error) {
+	return nil, fmt.Errorf("decoding Opus requires libopus, rebuild with -tags opus")
+}
@@ -0,0 +1,165 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// PCMSource reads headerless PCM audio from a reader such as stdin
+type PCMSource struct {
+	reader io.Reader
+	format PCMFormat
+
+	chunks  chan []byte
+	stop    chan struct{}
+	err     error
+	pending []byte
+	once    sync.Once
+}
+
+// NewPCMSource creates a source decoding raw PCM in the given format from reader
+func NewPCMSource(reader io.Reader, format PCMFormat) *PCMSource {
+	return &PCMSource{
+		reader: reader,
+		format: format,
+		chunks: make(chan []byte, 16),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start begins reading in the background, so that Stop can interrupt a blocked Read
+func (s *PCMSource) Start() error {
+	go func() {
+		defer close(s.chunks)
+		for {
+			buf := make([]byte, 4096)
+			n, err := s.reader.Read(buf)
+			if n > 0 {
+				select {
+				case s.chunks <- buf[:n]:
+				case <-s.stop:
+					return
+				}
+			}
+			if err != nil {
+				// Written before close(s.chunks), so Read sees it after draining
+				s.err = err
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Read decodes the next samples from the stream
+func (s *PCMSource) Read(buf []float32) (int, error) {
+	size := bytesPerSample(s.format.Encoding)
+	for len(s.pending) < size {
+		select {
+		case chunk, ok := <-s.chunks:
+			if !ok {
+				if s.err != nil && !errors.Is(s.err, io.EOF) {
+					return 0, fmt.Errorf("error reading PCM stream: %v", s.err)
+				}
+				return 0, io.EOF
+			}
+			s.pending = append(s.pending, chunk...)
+		case <-s.stop:
+			return 0, io.EOF
+		}
+	}
+
+	n := len(s.pending) / size
+	if n > len(buf) {
+		n = len(buf)
+	}
+	DecodePCM(buf[:n], s.pending[:n*size], s.format.Encoding)
+	s.pending = s.pending[n*size:]
+	return n, nil
+}
+
+// Stop interrupts reading
+func (s *PCMSource) Stop() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
+
+// SampleRate returns the configured sample rate
+func (s *PCMSource) SampleRate() int {
+	return s.format.SampleRate
+}
+
+// Channels returns the configured channel count
+func (s *PCMSource) Channels() int {
+	return s.format.Channels
+}
+
+// bytesPerSample returns the sample size of a PCM encoding, or 0 if it is unsupported
+func bytesPerSample(encoding string) int {
+	switch encoding {
+	case "u8":
+		return 1
+	case "s16le":
+		return 2
+	case "s32le", "f32le":
+		return 4
+	default:
+		return 0
+	}
+}
+
+// DecodePCM converts len(dst) raw samples of the given encoding from src into floats in [-1, 1]
+func DecodePCM(dst []float32, src []byte, encoding string) {
+	switch encoding {
+	case "u8":
+		for i := range dst {
+			dst[i] = (float32(src[i]) - 128) / 128
+		}
+	case "s16le":
+		for i := range dst {
+			dst[i] = float32(int16(binary.LittleEndian.Uint16(src[i*2:]))) / 32768
+		}
+	case "s32le":
+		for i := range dst {
+			dst[i] = float32(float64(int32(binary.LittleEndian.Uint32(src[i*4:]))) / 2147483648)
+		}
+	case "f32le":
+		for i := range dst {
+			dst[i] = math.Float32frombits(binary.LittleEndian.Uint32(src[i*4:]))
+		}
+	}
+}
+
+// PCMFrameDecoder decodes raw PCM arriving in arbitrary chunks, such as network
+// messages. A sample split across chunks is completed by the next one.
+type PCMFrameDecoder struct {
+	encoding string
+	size     int
+	pending  []byte
+	samples  []float32
+}
+
+// NewPCMFrameDecoder creates a decoder for chunks of PCM in the given format
+func NewPCMFrameDecoder(format PCMFormat) *PCMFrameDecoder {
+	return &PCMFrameDecoder{encoding: format.Encoding, size: format.SampleSize()}
+}
+
+// Decode returns the complete samples available after appending chunk. The
+// returned slice is reused by the next call.
+func (d *PCMFrameDecoder) Decode(chunk []byte) ([]float32, error) {
+	d.pending = append(d.pending, chunk...)
+	n := len(d.pending) / d.size
+	if cap(d.samples) < n {
+		d.samples = make([]float32, n)
+	}
+	samples := d.samples[:n]
+	DecodePCM(samples, d.pending[:n*d.size], d.encoding)
+	d.pending = append(d.pending[:0], d.pending[n*d.size:]...)
+	return samples, nil
+}
@@ -0,0 +1,275 @@
+//go:build portaudio
+
+package audio
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// PortAudioSource captures audio from an input device through PortAudio
+type PortAudioSource struct {
+	device          string
+	sampleRate      int
+	channels        int
+	framesPerBuffer int
+
+	stream  *portaudio.Stream
+	buffer  []float32
+	pending []float32
+	mu      sync.Mutex
+	stopped bool
+	reads   sync.WaitGroup // Reads in progress, Stop waits for them before closing the stream
+}
+
+// NewPortAudioSource creates a source recording from the input device matching
+// device, a device index or (part of) its name, or from the default input device
+// if device is empty. A sampleRate of 0 selects the device's default rate.
+func NewPortAudioSource(device string, sampleRate, channels, framesPerBuffer int) (*PortAudioSource, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize PortAudio: %v", err)
+	}
+	defer portaudio.Terminate()
+
+	info, err := findInputDevice(device)
+	if err != nil {
+		return nil, err
+	}
+
+	if sampleRate == 0 {
+		sampleRate = int(info.DefaultSampleRate)
+	}
+	if channels < 1 || channels > info.MaxInputChannels {
+		return nil, fmt.Errorf("device %q supports 1 to %d input channels, got %d", info.Name, info.MaxInputChannels, channels)
+	}
+	if !supportsFormat(info, sampleRate, channels) {
+		return nil, fmt.Errorf("device %q does not support %d Hz with %d channels", info.Name, sampleRate, channels)
+	}
+
+	return &PortAudioSource{
+		device:          device,
+		sampleRate:      sampleRate,
+		channels:        channels,
+		framesPerBuffer: framesPerBuffer,
+	}, nil
+}
+
+// ListInputDevices returns the devices that can record audio
+func ListInputDevices() ([]DeviceInfo, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize PortAudio: %v", err)
+	}
+	defer portaudio.Terminate()
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audio devices: %v", err)
+	}
+	defaultDevice, _ := portaudio.DefaultInputDevice()
+
+	var inputs []DeviceInfo
+	for _, device := range devices {
+		if device.MaxInputChannels == 0 {
+			continue
+		}
+
+		info := DeviceInfo{
+			Index:             device.Index,
+			Name:              device.Name,
+			MaxInputChannels:  device.MaxInputChannels,
+			DefaultSampleRate: int(device.DefaultSampleRate),
+			Default:           defaultDevice != nil && device.Index == defaultDevice.Index,
+		}
+		if device.HostApi != nil {
+			info.HostAPI = device.HostApi.Name
+		}
+		for _, rate := range StandardSampleRates {
+			if supportsFormat(device, rate, 1) {
+				info.SampleRates = append(info.SampleRates, rate)
+			}
+		}
+		inputs = append(inputs, info)
+	}
+
+	return inputs, nil
+}
+
+// findInputDevice resolves a device index or name, an empty spec selects the default
+// input device. Names match exactly (ignoring case) first, then by unique substring.
+func findInputDevice(spec string) (*portaudio.DeviceInfo, error) {
+	if spec == "" {
+		device, err := portaudio.DefaultInputDevice()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default input device: %v", err)
+		}
+		return device, nil
+	}
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audio devices: %v", err)
+	}
+
+	if index, err := strconv.Atoi(spec); err == nil {
+		for _, device := range devices {
+			if device.Index == index {
+				if device.MaxInputChannels == 0 {
+					return nil, fmt.Errorf("device %d (%s) has no inputs", index, device.Name)
+				}
+				return device, nil
+			}
+		}
+		return nil, fmt.Errorf("no audio device with index %d", index)
+	}
+
+	var partial []*portaudio.DeviceInfo
+	for _, device := range devices {
+		if device.MaxInputChannels == 0 {
+			continue
+		}
+		if strings.EqualFold(device.Name, spec) {
+			return device, nil
+		}
+		if strings.Contains(strings.ToLower(device.Name), strings.ToLower(spec)) {
+			partial = append(partial, device)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return nil, fmt.Errorf("no input device matching %q, run with -devices to list them", spec)
+	case 1:
+		return partial[0], nil
+	default:
+		return nil, fmt.Errorf("%d input devices match %q, use the device index instead", len(partial), spec)
+	}
+}
+
+// supportsFormat reports whether the device can record at rate with channels
+func supportsFormat(device *portaudio.DeviceInfo, rate, channels int) bool {
+	params := portaudio.StreamParameters{
+		Input: portaudio.StreamDeviceParameters{
+			Device:   device,
+			Channels: channels,
+			Latency:  device.DefaultLowInputLatency,
+		},
+		SampleRate: float64(rate),
+	}
+	return portaudio.IsFormatSupported(params, make([]float32, channels)) == nil
+}
+
+// Start initializes PortAudio and opens a blocking input stream
+func (s *PortAudioSource) Start() error {
+	if err := portaudio.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize PortAudio: %v", err)
+	}
+
+	device, err := findInputDevice(s.device)
+	if err != nil {
+		portaudio.Terminate()
+		return err
+	}
+
+	params := portaudio.StreamParameters{
+		Input: portaudio.StreamDeviceParameters{
+			Device:   device,
+			Channels: s.channels,
+			Latency:  device.DefaultLowInputLatency,
+		},
+		SampleRate:      float64(s.sampleRate),
+		FramesPerBuffer: s.framesPerBuffer,
+	}
+
+	// With a buffer instead of a callback the stream is read with Stream.Read
+	s.buffer = make([]float32, s.framesPerBuffer*s.channels)
+	stream, err := portaudio.OpenStream(params, s.buffer)
+	if err != nil {
+		portaudio.Terminate()
+		return fmt.Errorf("failed to open audio stream: %v", err)
+	}
+
+	if err := stream.Start(); err != nil {
+		stream.Close()
+		portaudio.Terminate()
+		return fmt.Errorf("failed to start audio stream: %v", err)
+	}
+
+	s.stream = stream
+	return nil
+}
+
+// Read blocks until the device delivers the next buffer
+func (s *PortAudioSource) Read(buf []float32) (int, error) {
+	if len(s.pending) == 0 {
+		s.mu.Lock()
+		if s.stopped || s.stream == nil {
+			s.mu.Unlock()
+			return 0, io.EOF
+		}
+		stream := s.stream
+		s.reads.Add(1)
+		s.mu.Unlock()
+
+		err := stream.Read()
+		s.reads.Done()
+		if err != nil {
+			s.mu.Lock()
+			stopped := s.stopped
+			s.mu.Unlock()
+			if stopped {
+				return 0, io.EOF
+			}
+			// Overflows only mean that some input was lost, keep recording
+			if err == portaudio.InputOverflowed {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to read audio stream: %v", err)
+		}
+		s.pending = s.buffer
+	}
+
+	n := copy(buf, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Stop closes the stream and releases PortAudio. A Read in progress returns first,
+// since PortAudio streams must not be stopped or closed while they are read.
+func (s *PortAudioSource) Stop() error {
+	s.mu.Lock()
+	if s.stopped || s.stream == nil {
+		s.stopped = true
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	stream := s.stream
+	s.mu.Unlock()
+
+	// The stream is still running, so a pending read gets its buffer within one buffer
+	// duration, and later reads return io.EOF without touching the stream
+	s.reads.Wait()
+
+	if err := stream.Stop(); err != nil {
+		return fmt.Errorf("failed to stop stream: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		return fmt.Errorf("failed to close stream: %v", err)
+	}
+	return portaudio.Terminate()
+}
+
+// SampleRate returns the capture sample rate
+func (s *PortAudioSource) SampleRate() int {
+	return s.sampleRate
+}
+
+// Channels returns the captured channel count
+func (s *PortAudioSource) Channels() int {
+	return s.channels
+}
@@ -0,0 +1,22 @@
+//go:build !portaudio
+
+package audio
+
+import "fmt"
+
+// PortAudioSource is unavailable in builds without the portaudio tag
+type PortAudioSource struct {
+	Source
+}
+
+// NewPortAudioSource reports that microphone capture was not compiled in
+func NewPortAudioSource(device string, sampleRate, channels, framesPerBuffer int) (*PortAudioSource, error) {
+	return nil, errNoPortAudio
+}
+
+// ListInputDevices reports that device listing was not compiled in
+func ListInputDevices() ([]DeviceInfo, error) {
+	return nil, errNoPortAudio
+}
+
+var errNoPortAudio = fmt.Errorf("microphone capture requires PortAudio, rebuild with -tags portaudio")
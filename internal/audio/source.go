@@ -0,0 +1,96 @@
+// Package audio provides live audio sources that can feed real-time recognition.
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source is a stream of interleaved float32 PCM samples in the range [-1, 1]
+type Source interface {
+	// Start opens the underlying device or stream
+	Start() error
+	// Read fills buf with interleaved samples and returns how many were read.
+	// It blocks until audio is available and returns io.EOF when the source ends.
+	Read(buf []float32) (int, error)
+	// Stop closes the source, a blocked Read returns
+	Stop() error
+	// SampleRate returns the number of frames per second
+	SampleRate() int
+	// Channels returns the number of interleaved channels per frame
+	Channels() int
+}
+
+// PCMFormat describes headerless PCM audio
+type PCMFormat struct {
+	Encoding   string // s16le, s32le, f32le or u8
+	SampleRate int
+	Channels   int
+}
+
+// ParsePCMFormat parses a format written as encoding:rate:channels, e.g. s16le:44100:2
+func ParsePCMFormat(spec string) (PCMFormat, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return PCMFormat{}, fmt.Errorf("invalid PCM format %q, expected encoding:rate:channels", spec)
+	}
+
+	format := PCMFormat{Encoding: strings.ToLower(parts[0])}
+	if bytesPerSample(format.Encoding) == 0 {
+		return PCMFormat{}, fmt.Errorf("unsupported PCM encoding: %s", parts[0])
+	}
+
+	var err error
+	if format.SampleRate, err = strconv.Atoi(parts[1]); err != nil || format.SampleRate <= 0 {
+		return PCMFormat{}, fmt.Errorf("invalid PCM sample rate: %s", parts[1])
+	}
+	if format.Channels, err = strconv.Atoi(parts[2]); err != nil || format.Channels <= 0 {
+		return PCMFormat{}, fmt.Errorf("invalid PCM channel count: %s", parts[2])
+	}
+
+	return format, nil
+}
+
+// SampleSize returns the number of bytes of one sample of one channel
+func (f PCMFormat) SampleSize() int {
+	return bytesPerSample(f.Encoding)
+}
+
+// String formats f the way ParsePCMFormat expects it
+func (f PCMFormat) String() string {
+	return fmt.Sprintf("%s:%d:%d", f.Encoding, f.SampleRate, f.Channels)
+}
+
+// pacer delays reads so that a source which could be read faster than real time
+// (files, generators) delivers audio at the rate a live device would
+type pacer struct {
+	sampleRate int
+	start      time.Time
+	frames     int64
+}
+
+// wait blocks until the given number of additional frames is due, or stop is closed.
+// It reports false if stop was closed.
+func (p *pacer) wait(frames int, stop <-chan struct{}) bool {
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+	p.frames += int64(frames)
+
+	due := p.start.Add(time.Duration(float64(p.frames) / float64(p.sampleRate) * float64(time.Second)))
+	delay := time.Until(due)
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
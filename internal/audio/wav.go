@@ -0,0 +1,116 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// WAVSource plays a WAV file in real time, as if it was captured live
+type WAVSource struct {
+	streamer beep.StreamSeekCloser
+	format   beep.Format
+	frames   [][2]float64
+	pacer    *pacer
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// NewWAVSource creates a source for the WAV file at path. The header is read
+// immediately so that SampleRate and Channels are known before Start.
+func NewWAVSource(path string) (*WAVSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening WAV file: %v", err)
+	}
+
+	streamer, format, err := wav.Decode(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error decoding WAV file: %v", err)
+	}
+
+	return &WAVSource{
+		streamer: streamer,
+		format:   format,
+		pacer:    &pacer{sampleRate: int(format.SampleRate)},
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// Start begins playback
+func (s *WAVSource) Start() error {
+	return nil
+}
+
+// Read returns the next frames once they are due in real time
+func (s *WAVSource) Read(buf []float32) (int, error) {
+	if s.streamer == nil {
+		return 0, io.EOF
+	}
+
+	select {
+	case <-s.stop:
+		return 0, s.close()
+	default:
+	}
+
+	channels := s.format.NumChannels
+	frames := len(buf) / channels
+	if frames == 0 {
+		return 0, nil
+	}
+
+	if cap(s.frames) < frames {
+		s.frames = make([][2]float64, frames)
+	}
+	n, ok := s.streamer.Stream(s.frames[:frames])
+	if !ok && n == 0 {
+		if err := s.streamer.Err(); err != nil {
+			return 0, fmt.Errorf("error reading WAV file: %v", err)
+		}
+		return 0, s.close()
+	}
+
+	if !s.pacer.wait(n, s.stop) {
+		return 0, s.close()
+	}
+
+	for i := 0; i < n; i++ {
+		for c := 0; c < channels; c++ {
+			buf[i*channels+c] = float32(s.frames[i][c%2])
+		}
+	}
+	return n * channels, nil
+}
+
+// Stop ends playback, the file is closed by the interrupted Read
+func (s *WAVSource) Stop() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
+
+// close releases the file once playback ended or was stopped and reports the end of the stream
+func (s *WAVSource) close() error {
+	if s.streamer != nil {
+		s.streamer.Close()
+		s.streamer = nil
+	}
+	return io.EOF
+}
+
+// SampleRate returns the sample rate of the file
+func (s *WAVSource) SampleRate() int {
+	return int(s.format.SampleRate)
+}
+
+// Channels returns the channel count of the file
+func (s *WAVSource) Channels() int {
+	return s.format.NumChannels
+}
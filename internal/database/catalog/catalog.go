@@ -0,0 +1,30 @@
+package catalog
+
+import (
+	"database/sql"
+
+	config "github.com/media-luna/eureka/configs"
+)
+
+// Dialect is the syntax of a backend for the catalog queries
+type Dialect struct {
+	// Placeholder returns the bind parameter of the n-th argument, starting at 1
+	Placeholder func(n int) string
+	// HexHash returns the expression selecting a hash column as hexadecimal text
+	HexHash func(column string) string
+	// TableSizes selects the name, estimated rows and bytes of the tables of the
+	// current database named in the list it is formatted with, ordered by name
+	TableSizes string
+}
+
+// Queries runs the statistics and stop-list queries of a SQL database
+type Queries struct {
+	conn    *sql.DB
+	cfg     config.Config
+	dialect Dialect
+}
+
+// New returns the catalog queries of the database connected by conn
+func New(conn *sql.DB, cfg config.Config, dialect Dialect) *Queries {
+	return &Queries{conn: conn, cfg: cfg, dialect: dialect}
+}
@@ -0,0 +1,121 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	config "github.com/media-luna/eureka/configs"
+)
+
+// ErrInvalidQuery is wrapped by errors reporting an invalid SongQuery or RecognitionQuery
+var ErrInvalidQuery = errors.New("invalid query")
+
+// SongSortFields lists the values of SongQuery.Sort
+var SongSortFields = []string{"id", "name", "artist", "date_created"}
+
+// SongQuery filters, sorts and paginates the songs listed by a database. The zero
+// value lists every song by ID.
+type SongQuery struct {
+	Name          string    // Case-insensitive substring of the name
+	Artist        string    // Case-insensitive substring of the artist
+	Fingerprinted *bool     // Fingerprinting status, nil for any
+	CreatedAfter  time.Time // Songs created at or after this time, unless zero
+	CreatedBefore time.Time // Songs created before this time, unless zero
+	Sort          string    // One of SongSortFields, id when empty
+	Descending    bool
+	Limit         int // Maximum number of songs, 0 for no limit
+	Offset        int // Number of matching songs skipped
+}
+
+// Check returns an error wrapping ErrInvalidQuery when the query cannot be run
+func (q SongQuery) Check() error {
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", ErrInvalidQuery, q.Limit)
+	}
+	if q.Offset < 0 {
+		return fmt.Errorf("%w: negative offset %d", ErrInvalidQuery, q.Offset)
+	}
+	if q.Sort != "" {
+		known := false
+		for _, field := range SongSortFields {
+			known = known || q.Sort == field
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown sort field %q, expected one of %s", ErrInvalidQuery, q.Sort, strings.Join(SongSortFields, ", "))
+		}
+	}
+	if !q.CreatedAfter.IsZero() && !q.CreatedBefore.IsZero() && !q.CreatedAfter.Before(q.CreatedBefore) {
+		return fmt.Errorf("%w: empty creation date range", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// Where returns the WHERE clause selecting the songs of the query, or an empty string,
+// and its arguments. like is the case-insensitive LIKE operator of the backend and
+// placeholder returns its bind parameter for the n-th argument, starting at 1.
+func (q SongQuery) Where(cfg config.Config, like string, placeholder func(n int) string) (string, []interface{}) {
+	fields := cfg.Tables.Songs.Fields
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, placeholder(len(args))))
+	}
+
+	if q.Name != "" {
+		add(fields.Name+" "+like+" %s", likePattern(q.Name))
+	}
+	if q.Artist != "" {
+		add(fields.Artist+" "+like+" %s", likePattern(q.Artist))
+	}
+	if q.Fingerprinted != nil {
+		fingerprinted := 0
+		if *q.Fingerprinted {
+			fingerprinted = 1
+		}
+		add(fields.Fingerprinted+" = %s", fingerprinted)
+	}
+	if !q.CreatedAfter.IsZero() {
+		add("date_created >= %s", q.CreatedAfter.UTC())
+	}
+	if !q.CreatedBefore.IsZero() {
+		add("date_created < %s", q.CreatedBefore.UTC())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// OrderBy returns the ORDER BY clause of the query, ties are broken by ID so that
+// pages do not overlap
+func (q SongQuery) OrderBy(cfg config.Config) string {
+	fields := cfg.Tables.Songs.Fields
+	column := fields.ID
+	switch q.Sort {
+	case "name":
+		column = fields.Name
+	case "artist":
+		column = fields.Artist
+	case "date_created":
+		column = "date_created"
+	}
+
+	direction := "ASC"
+	if q.Descending {
+		direction = "DESC"
+	}
+	if column == fields.ID {
+		return fmt.Sprintf(" ORDER BY %s %s", column, direction)
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", column, direction, fields.ID, direction)
+}
+
+// likePattern matches s anywhere, escaping the LIKE wildcards it contains
+func likePattern(s string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + escaper.Replace(s) + "%"
+}
@@ -0,0 +1,205 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Stats describes the catalog stored in the songs and fingerprints tables, and the
+// size of the tables of the database
+type Stats struct {
+	Songs               int
+	FingerprintedSongs  int
+	Fingerprints        int64
+	FingerprintsPerSong CountSummary // Over the fingerprinted songs
+	DistinctHashes      int64
+	TopHashes           []HashFrequency   // Hashes found in the most songs first
+	Collisions          []CollisionBucket // Distinct hashes by number of songs sharing them
+	Tables              []TableSize
+}
+
+// CountSummary is the minimum, median and maximum of a set of counts
+type CountSummary struct {
+	Min    int
+	Median int
+	Max    int
+}
+
+// HashFrequency counts the songs and rows of one hash
+type HashFrequency struct {
+	Hash        string
+	Songs       int
+	Occurrences int64
+}
+
+// CollisionBucket counts the distinct hashes found in MinSongs to MaxSongs songs
+type CollisionBucket struct {
+	MinSongs int
+	MaxSongs int
+	Hashes   int64
+}
+
+// TableSize is the storage used by a table with its indexes. Rows is the estimate
+// kept by the database, which can lag behind the exact count.
+type TableSize struct {
+	Name  string
+	Rows  int64
+	Bytes int64
+}
+
+// SummarizeCounts returns the minimum, median and maximum of counts sorted in
+// increasing order, or zeros when there are none
+func SummarizeCounts(sorted []int) CountSummary {
+	n := len(sorted)
+	if n == 0 {
+		return CountSummary{}
+	}
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return CountSummary{Min: sorted[0], Median: median, Max: sorted[n-1]}
+}
+
+// CollisionBuckets groups the numbers of distinct hashes by the number of songs
+// sharing them into power of two ranges: 1, 2, 3-4, 5-8 and so on
+func CollisionBuckets(hashesBySongs map[int]int64) []CollisionBucket {
+	buckets := make(map[int]*CollisionBucket)
+	for songs, hashes := range hashesBySongs {
+		if songs < 1 {
+			continue
+		}
+		low, high := 1, 1
+		for high < songs {
+			low, high = high+1, high*2
+		}
+		b, ok := buckets[low]
+		if !ok {
+			b = &CollisionBucket{MinSongs: low, MaxSongs: high}
+			buckets[low] = b
+		}
+		b.Hashes += hashes
+	}
+
+	result := make([]CollisionBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].MinSongs < result[j].MinSongs })
+	return result
+}
+
+// Stats reads the catalog statistics with the topHashes most shared hashes. It scans
+// the whole fingerprints table, expect it to take a while on large catalogs.
+func (q *Queries) Stats(ctx context.Context, topHashes int) (Stats, error) {
+	songs := q.cfg.Tables.Songs
+	fingerprints := q.cfg.Tables.Fingerprints
+	var stats Stats
+
+	query := fmt.Sprintf("SELECT COUNT(*), COUNT(CASE WHEN %s = 1 THEN 1 END) FROM %s", songs.Fields.Fingerprinted, songs.Name)
+	if err := q.conn.QueryRowContext(ctx, query).Scan(&stats.Songs, &stats.FingerprintedSongs); err != nil {
+		return Stats{}, fmt.Errorf("error counting songs: %w", err)
+	}
+
+	query = fmt.Sprintf("SELECT COUNT(*) FROM %s", fingerprints.Name)
+	if err := q.conn.QueryRowContext(ctx, query).Scan(&stats.Fingerprints); err != nil {
+		return Stats{}, fmt.Errorf("error counting fingerprints: %w", err)
+	}
+
+	// Fingerprints per fingerprinted song, a song without any counts as 0
+	query = fmt.Sprintf(`SELECT COUNT(f.%s) AS n FROM %s s LEFT JOIN %s f ON f.%s = s.%s
+		WHERE s.%s = 1 GROUP BY s.%s ORDER BY n`,
+		fingerprints.Fields.Hash, songs.Name, fingerprints.Name, songs.Fields.ID, songs.Fields.ID,
+		songs.Fields.Fingerprinted, songs.Fields.ID)
+	rows, err := q.conn.QueryContext(ctx, query)
+	if err != nil {
+		return Stats{}, fmt.Errorf("error counting fingerprints per song: %w", err)
+	}
+	defer rows.Close()
+	var perSong []int
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			return Stats{}, fmt.Errorf("error scanning fingerprint count: %w", err)
+		}
+		perSong = append(perSong, n)
+	}
+	if err := rows.Err(); err != nil {
+		return Stats{}, fmt.Errorf("error counting fingerprints per song: %w", err)
+	}
+	stats.FingerprintsPerSong = SummarizeCounts(perSong)
+
+	query = fmt.Sprintf(`SELECT %s, COUNT(DISTINCT %s) AS songs, COUNT(*) AS occurrences FROM %s
+		GROUP BY %s ORDER BY songs DESC, occurrences DESC LIMIT %s`,
+		q.dialect.HexHash(fingerprints.Fields.Hash), songs.Fields.ID, fingerprints.Name, fingerprints.Fields.Hash,
+		q.dialect.Placeholder(1))
+	rows, err = q.conn.QueryContext(ctx, query, topHashes)
+	if err != nil {
+		return Stats{}, fmt.Errorf("error querying common hashes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h HashFrequency
+		if err := rows.Scan(&h.Hash, &h.Songs, &h.Occurrences); err != nil {
+			return Stats{}, fmt.Errorf("error scanning hash: %w", err)
+		}
+		stats.TopHashes = append(stats.TopHashes, h)
+	}
+	if err := rows.Err(); err != nil {
+		return Stats{}, fmt.Errorf("error querying common hashes: %w", err)
+	}
+
+	query = fmt.Sprintf(`SELECT songs, COUNT(*) FROM
+		(SELECT COUNT(DISTINCT %s) AS songs FROM %s GROUP BY %s) AS hashes GROUP BY songs`,
+		songs.Fields.ID, fingerprints.Name, fingerprints.Fields.Hash)
+	rows, err = q.conn.QueryContext(ctx, query)
+	if err != nil {
+		return Stats{}, fmt.Errorf("error querying hash collisions: %w", err)
+	}
+	defer rows.Close()
+	hashesBySongs := make(map[int]int64)
+	for rows.Next() {
+		var sharing int
+		var hashes int64
+		if err := rows.Scan(&sharing, &hashes); err != nil {
+			return Stats{}, fmt.Errorf("error scanning hash collisions: %w", err)
+		}
+		hashesBySongs[sharing] = hashes
+		stats.DistinctHashes += hashes
+	}
+	if err := rows.Err(); err != nil {
+		return Stats{}, fmt.Errorf("error querying hash collisions: %w", err)
+	}
+	stats.Collisions = CollisionBuckets(hashesBySongs)
+
+	tables := []interface{}{
+		songs.Name,
+		fingerprints.Name,
+		q.cfg.Tables.Plays.Name,
+		q.cfg.Tables.StopList.Name,
+		q.cfg.Tables.History.Name,
+	}
+	placeholders := make([]string, len(tables))
+	for i := range tables {
+		placeholders[i] = q.dialect.Placeholder(i + 1)
+	}
+	rows, err = q.conn.QueryContext(ctx, fmt.Sprintf(q.dialect.TableSizes, strings.Join(placeholders, ", ")), tables...)
+	if err != nil {
+		return Stats{}, fmt.Errorf("error querying table sizes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t TableSize
+		if err := rows.Scan(&t.Name, &t.Rows, &t.Bytes); err != nil {
+			return Stats{}, fmt.Errorf("error scanning table size: %w", err)
+		}
+		stats.Tables = append(stats.Tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return Stats{}, fmt.Errorf("error querying table sizes: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,84 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// StopList returns the stop-listed hashes, found in the most songs first
+func (q *Queries) StopList(ctx context.Context) ([]HashFrequency, error) {
+	query := fmt.Sprintf("SELECT %s, songs, occurrences FROM %s ORDER BY songs DESC, occurrences DESC",
+		q.dialect.HexHash(q.cfg.Tables.Fingerprints.Fields.Hash),
+		q.cfg.Tables.StopList.Name)
+
+	rows, err := q.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying stop-list: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []HashFrequency
+	for rows.Next() {
+		var h HashFrequency
+		if err := rows.Scan(&h.Hash, &h.Songs, &h.Occurrences); err != nil {
+			return nil, fmt.Errorf("error scanning stop-listed hash: %w", err)
+		}
+		hashes = append(hashes, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying stop-list: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// BuildStopList replaces the stop-list with the hashes found in at least minSongs
+// songs and returns their number. It scans the whole fingerprints table.
+func (q *Queries) BuildStopList(ctx context.Context, minSongs int) (int, error) {
+	tx, err := q.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", q.cfg.Tables.StopList.Name)); err != nil {
+		return 0, fmt.Errorf("error clearing stop-list: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s, songs, occurrences)
+		SELECT %s, COUNT(DISTINCT %s), COUNT(*) FROM %s
+		GROUP BY %s HAVING COUNT(DISTINCT %s) >= %s`,
+		q.cfg.Tables.StopList.Name,
+		q.cfg.Tables.Fingerprints.Fields.Hash,
+		q.cfg.Tables.Fingerprints.Fields.Hash,
+		q.cfg.Tables.Songs.Fields.ID,
+		q.cfg.Tables.Fingerprints.Name,
+		q.cfg.Tables.Fingerprints.Fields.Hash,
+		q.cfg.Tables.Songs.Fields.ID,
+		q.dialect.Placeholder(1))
+	result, err := tx.ExecContext(ctx, query, minSongs)
+	if err != nil {
+		return 0, fmt.Errorf("error building stop-list: %w", err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error building stop-list: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing stop-list: %w", err)
+	}
+	logger.Info("Built stop-list", "hashes", inserted, "min_songs", minSongs)
+	return int(inserted), nil
+}
+
+// ClearStopList removes every stop-listed hash
+func (q *Queries) ClearStopList(ctx context.Context) error {
+	if _, err := q.conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", q.cfg.Tables.StopList.Name)); err != nil {
+		return fmt.Errorf("error clearing stop-list: %w", err)
+	}
+	return nil
+}
@@ -1,30 +1,55 @@
 package database
 
 import (
+	"context"
 	"fmt"
 
 	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/migrate"
 	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/database/postgres"
 )
 
 // Database defines the interface that all database implementations must satisfy
 type Database interface {
-	Setup() error
+	Setup(ctx context.Context) error
 	Close() error
-	InsertFingerprints(fingerprint string, songID int, offset int) error
-	InsertSong(songName string, artistName string, fileHash string, totalHashes int) (int, error)
-	DeleteSong(songID int) error
-	UpdateSongFingerprinted(songID int) error
-	Cleanup() error
-	QueryFingerprints(hashes []string) ([]mysql.FingerprintMatch, error)
-	GetSongByID(songID int) (mysql.SongInfo, error)
+	InsertFingerprints(ctx context.Context, fingerprint string, songID int, offset int) error
+	InsertSong(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int, error)
+	DeleteSong(ctx context.Context, songID int) error
+	UpdateSongFingerprinted(ctx context.Context, songID int) error
+	Cleanup(ctx context.Context) error
+	QueryFingerprints(ctx context.Context, hashes []string) ([]mysql.FingerprintMatch, error)
+	GetSongByID(ctx context.Context, songID int) (mysql.SongInfo, error)
+	InsertPlay(ctx context.Context, play mysql.Play) error
+	UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) error
+	ListSongs(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error)
 }
 
-// NewDatabase creates a new database instance based on the configuration
-func NewDatabase(cfg config.Config) (Database, error) {
+// Migratable is implemented by databases with versioned schema migrations, Setup
+// applies the pending ones
+type Migratable interface {
+	Migrator() *migrate.Migrator
+}
+
+// CheckSchema returns an error wrapping migrate.ErrNewerSchema when db was migrated by
+// a newer release. Databases without migrations always pass.
+func CheckSchema(ctx context.Context, db Database) error {
+	if m, ok := db.(Migratable); ok {
+		return m.Migrator().Check(ctx)
+	}
+	return nil
+}
+
+// NewDatabase creates a new database instance based on the configuration.
+// The context bounds connecting to the database.
+func NewDatabase(ctx context.Context, cfg config.Config) (Database, error) {
 	switch cfg.Database.Type {
 	case "mysql":
-		return mysql.NewDB(cfg)
+		return mysql.NewDB(ctx, cfg)
+	case "postgres":
+		return postgres.NewDB(ctx, cfg)
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", cfg.Database.Type)
 	}
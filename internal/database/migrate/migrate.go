@@ -0,0 +1,222 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// VersionTable records the migrations applied to a database, one row per version
+const VersionTable = "schema_version"
+
+// ErrNewerSchema is wrapped by errors reporting a database migrated by a newer release
+var ErrNewerSchema = errors.New("database schema is newer than this release supports")
+
+// Migration is one reversible schema change. Versions start at 1 and increase by one.
+type Migration struct {
+	Version     int
+	Description string
+	Up          []string // Statements applying the change
+	Down        []string // Statements reverting it
+}
+
+// Dialect holds the SQL that differs between backends
+type Dialect struct {
+	// CreateVersionTable creates VersionTable with the columns version, description and applied_at
+	CreateVersionTable string
+	// Placeholder returns the bind parameter for the n-th argument, starting at 1
+	Placeholder func(n int) string
+}
+
+// Applied is a migration recorded in VersionTable
+type Applied struct {
+	Version     int
+	Description string
+	AppliedAt   time.Time
+}
+
+// Status describes the schema of a database against the known migrations
+type Status struct {
+	Current int         // Highest applied version, 0 for an empty database
+	Latest  int         // Highest version known to this release
+	Applied []Applied   // Applied migrations in version order
+	Pending []Migration // Known migrations not applied yet in version order
+}
+
+// Migrator applies and reverts migrations on a database
+type Migrator struct {
+	conn       *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New creates a Migrator for the migrations of one backend, which must be sorted by version
+func New(conn *sql.DB, dialect Dialect, migrations []Migration) *Migrator {
+	return &Migrator{conn: conn, dialect: dialect, migrations: migrations}
+}
+
+// Latest returns the highest known version
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status reads the applied migrations
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	if _, err := m.conn.ExecContext(ctx, m.dialect.CreateVersionTable); err != nil {
+		return Status{}, fmt.Errorf("error creating %s table: %w", VersionTable, err)
+	}
+
+	query := fmt.Sprintf("SELECT version, description, applied_at FROM %s ORDER BY version", VersionTable)
+	rows, err := m.conn.QueryContext(ctx, query)
+	if err != nil {
+		return Status{}, fmt.Errorf("error querying schema version: %w", err)
+	}
+	defer rows.Close()
+
+	status := Status{Latest: m.Latest()}
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Description, &a.AppliedAt); err != nil {
+			return Status{}, fmt.Errorf("error scanning schema version: %w", err)
+		}
+		status.Applied = append(status.Applied, a)
+		applied[a.Version] = true
+		status.Current = a.Version
+	}
+	if err := rows.Err(); err != nil {
+		return Status{}, fmt.Errorf("error querying schema version: %w", err)
+	}
+
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// Check returns an error wrapping ErrNewerSchema when the database has migrations this
+// release does not know about
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	return checkNewer(status)
+}
+
+// Up applies the pending migrations up to version target, or all of them when target
+// is 0, and returns the applied migrations
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNewer(status); err != nil {
+		return nil, err
+	}
+	if target == 0 {
+		target = status.Latest
+	}
+	if target > status.Latest {
+		return nil, fmt.Errorf("unknown schema version %d, the latest is %d", target, status.Latest)
+	}
+
+	var done []Migration
+	for _, migration := range status.Pending {
+		if migration.Version > target {
+			break
+		}
+		if err := m.apply(ctx, migration, migration.Up, true); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the applied migrations above version target, newest first, and returns
+// the reverted migrations. Target 0 reverts every migration.
+func (m *Migrator) Down(ctx context.Context, target int) ([]Migration, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNewer(status); err != nil {
+		return nil, err
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("invalid schema version %d", target)
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= target || migration.Version > status.Current {
+			continue
+		}
+		if err := m.apply(ctx, migration, migration.Down, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// apply runs the statements of a migration and records or removes its version in one
+// transaction. Backends committing DDL implicitly, such as MySQL, can be left with a
+// partly applied migration when a statement fails.
+func (m *Migrator) apply(ctx context.Context, migration Migration, statements []string, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting migration %d: %w", migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("error migrating %s to version %d (%s): %w", direction, migration.Version, migration.Description, err)
+		}
+	}
+
+	var query string
+	var args []interface{}
+	if up {
+		query = fmt.Sprintf("INSERT INTO %s (version, description, applied_at) VALUES (%s, %s, %s)",
+			VersionTable, m.dialect.Placeholder(1), m.dialect.Placeholder(2), m.dialect.Placeholder(3))
+		args = []interface{}{migration.Version, migration.Description, time.Now().UTC()}
+	} else {
+		query = fmt.Sprintf("DELETE FROM %s WHERE version = %s", VersionTable, m.dialect.Placeholder(1))
+		args = []interface{}{migration.Version}
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error recording schema version %d: %w", migration.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing migration %d: %w", migration.Version, err)
+	}
+	logger.Info("Migrated schema", "direction", direction, "version", migration.Version, "description", migration.Description)
+	return nil
+}
+
+// checkNewer reports a database migrated past the latest known version
+func checkNewer(status Status) error {
+	if status.Current > status.Latest {
+		return fmt.Errorf("%w: version %d, expected at most %d", ErrNewerSchema, status.Current, status.Latest)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+)
+
+// catalogDialect is the MySQL syntax of the catalog queries, hashes are stored as hex
+var catalogDialect = catalog.Dialect{
+	Placeholder: func(int) string { return "?" },
+	HexHash:     func(column string) string { return column },
+	TableSizes: `SELECT TABLE_NAME, COALESCE(TABLE_ROWS, 0), COALESCE(DATA_LENGTH + INDEX_LENGTH, 0)
+		FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME IN (%s)
+		ORDER BY TABLE_NAME`,
+}
+
+func (m *DB) catalog() *catalog.Queries {
+	return catalog.New(m.conn, m.cfg, catalogDialect)
+}
+
+// Stats reads the catalog statistics with the topHashes most shared hashes. It scans
+// the whole fingerprints table, expect it to take a while on large catalogs.
+func (m *DB) Stats(ctx context.Context, topHashes int) (catalog.Stats, error) {
+	return m.catalog().Stats(ctx, topHashes)
+}
+
+// StopList returns the stop-listed hashes, found in the most songs first
+func (m *DB) StopList(ctx context.Context) ([]catalog.HashFrequency, error) {
+	return m.catalog().StopList(ctx)
+}
+
+// BuildStopList replaces the stop-list with the hashes found in at least minSongs
+// songs and returns their number. It scans the whole fingerprints table.
+func (m *DB) BuildStopList(ctx context.Context, minSongs int) (int, error) {
+	return m.catalog().BuildStopList(ctx, minSongs)
+}
+
+// ClearStopList removes every stop-listed hash
+func (m *DB) ClearStopList(ctx context.Context) error {
+	return m.catalog().ClearStopList(ctx)
+}
@@ -1,12 +1,16 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/catalog"
 	"github.com/media-luna/eureka/utils/logger"
 )
 
@@ -16,11 +20,15 @@ type DB struct {
 	cfg  config.Config
 }
 
+// ErrNotFound is wrapped by errors reporting a missing record, check it with errors.Is
+var ErrNotFound = errors.New("not found")
+
 // Song represents a song record from the database
 type Song struct {
 	ID            int
 	Name          string
 	Artist        string
+	Album         string
 	Fingerprinted bool
 	FileSHA1      string
 	TotalHashes   int
@@ -39,50 +47,45 @@ type SongInfo struct {
 	ID     int
 	Name   string
 	Artist string
+	Album  string
 }
 
-const (
-	createSongsTableSQL = `
-		CREATE TABLE IF NOT EXISTS %s (
-			%s MEDIUMINT UNSIGNED NOT NULL AUTO_INCREMENT,
-			%s VARCHAR(250) NOT NULL,
-			%s VARCHAR(250) DEFAULT '',
-			%s TINYINT DEFAULT 0,
-			%s BINARY(20) NOT NULL,
-			%s INT NOT NULL DEFAULT 0,
-			date_created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			date_modified DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-			PRIMARY KEY (%s),
-			UNIQUE KEY file_sha1_idx (%s)
-		) ENGINE=INNODB;`
-
-	createFingerprintsTableSQL = `
-		CREATE TABLE IF NOT EXISTS %s (
-			%s CHAR(40) NOT NULL,
-			%s MEDIUMINT UNSIGNED NOT NULL,
-			%s INT UNSIGNED NOT NULL,
-			date_created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			date_modified DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-			INDEX ix_%s_%s (%s),
-			CONSTRAINT uq_%s_%s_%s_%s UNIQUE KEY (%s, %s, %s),
-			CONSTRAINT fk_%s_%s FOREIGN KEY (%s)
-				REFERENCES %s(%s) ON DELETE CASCADE
-		) ENGINE=INNODB;`
-
-	deleteUnfingerprintedSQL = `DELETE FROM %s WHERE %s = 0;`
-)
+// SongUpdate holds the metadata to change in UpdateSong, nil fields are left unchanged
+type SongUpdate struct {
+	Name   *string
+	Artist *string
+	Album  *string
+}
+
+// Play represents a detected airing of a song on a monitored channel
+type Play struct {
+	ID        int
+	Channel   string
+	SongID    int
+	StartedAt time.Time
+	EndedAt   time.Time
+	Score     float64
+}
+
+// SongPage is the result of ListSongs
+type SongPage struct {
+	Songs []Song
+	Total int // Songs matching the filters, ignoring Limit and Offset
+}
+
+const deleteUnfingerprintedSQL = `DELETE FROM %s WHERE %s = 0;`
 
 // NewDB creates a new DB instance with the given configuration.
-func NewDB(cfg config.Config) (*DB, error) {
+func NewDB(ctx context.Context, cfg config.Config) (*DB, error) {
 	db := &DB{cfg: cfg}
-	if err := db.connect(); err != nil {
+	if err := db.connect(ctx); err != nil {
 		return nil, err
 	}
 	return db, nil
 }
 
 // Connect to the MySQL database.
-func (m *DB) connect() error {
+func (m *DB) connect(ctx context.Context) error {
 	var err error
 	dsnString := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
 		m.cfg.Database.User,
@@ -98,58 +101,17 @@ func (m *DB) connect() error {
 	}
 
 	// Test the connection
-	if err := m.conn.Ping(); err != nil {
+	if err := m.conn.PingContext(ctx); err != nil {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
-	logger.Info("Connected to MySQL database")
+	logger.Info("Connected to MySQL database", "host", m.cfg.Database.Host, "database", m.cfg.Database.DBName)
 	return nil
 }
 
-// Setup initializes the database tables.
-func (m *DB) Setup() error {
-	// Create songs table
-	songsSQL := fmt.Sprintf(createSongsTableSQL,
-		m.cfg.Tables.Songs.Name,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Songs.Fields.Name,
-		m.cfg.Tables.Songs.Fields.Artist,
-		m.cfg.Tables.Songs.Fields.Fingerprinted,
-		m.cfg.Tables.Songs.Fields.FileSHA1,
-		m.cfg.Tables.Songs.Fields.TotalHashes,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Songs.Fields.FileSHA1)
-
-	if _, err := m.conn.Exec(songsSQL); err != nil {
-		return fmt.Errorf("error creating songs table: %w", err)
-	}
-
-	// Create fingerprints table
-	fpSQL := fmt.Sprintf(createFingerprintsTableSQL,
-		m.cfg.Tables.Fingerprints.Name,
-		m.cfg.Tables.Fingerprints.Fields.Hash,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Fingerprints.Fields.Offset,
-		m.cfg.Tables.Fingerprints.Name,
-		m.cfg.Tables.Fingerprints.Fields.Hash,
-		m.cfg.Tables.Fingerprints.Fields.Hash,
-		m.cfg.Tables.Fingerprints.Name,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Fingerprints.Fields.Offset,
-		m.cfg.Tables.Fingerprints.Fields.Hash,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Fingerprints.Fields.Offset,
-		m.cfg.Tables.Fingerprints.Fields.Hash,
-		m.cfg.Tables.Fingerprints.Name,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Songs.Fields.ID,
-		m.cfg.Tables.Songs.Name,
-		m.cfg.Tables.Songs.Fields.ID)
-
-	if _, err := m.conn.Exec(fpSQL); err != nil {
-		return fmt.Errorf("error creating fingerprints table: %w", err)
-	}
-
-	return nil
+// Setup applies the pending schema migrations, creating the tables on a new database.
+func (m *DB) Setup(ctx context.Context) error {
+	_, err := m.Migrator().Up(ctx, 0)
+	return err
 }
 
 // Close the MySQL database connection.
@@ -158,7 +120,7 @@ func (m *DB) Close() error {
 }
 
 // Insert song metadata into songs table
-func (m *DB) insertSongWithID(songName string, artistName string, fileHash string, totalHashes int) (int64, error) {
+func (m *DB) insertSongWithID(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int64, error) {
 	// Check if song with same hash already exists
 	var existingID int64
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE HEX(%s) = ?",
@@ -166,7 +128,7 @@ func (m *DB) insertSongWithID(songName string, artistName string, fileHash strin
 		m.cfg.Tables.Songs.Name,
 		m.cfg.Tables.Songs.Fields.FileSHA1)
 
-	err := m.conn.QueryRow(query, fileHash).Scan(&existingID)
+	err := m.conn.QueryRowContext(ctx, query, fileHash).Scan(&existingID)
 	if err != sql.ErrNoRows {
 		if err == nil {
 			// Verify that the song still exists by ID
@@ -175,17 +137,17 @@ func (m *DB) insertSongWithID(songName string, artistName string, fileHash strin
 				m.cfg.Tables.Songs.Fields.ID)
 
 			var count int
-			verifyErr := m.conn.QueryRow(verifyQuery, existingID).Scan(&count)
+			verifyErr := m.conn.QueryRowContext(ctx, verifyQuery, existingID).Scan(&count)
 			if verifyErr != nil {
 				return 0, fmt.Errorf("error verifying existing song: %w", verifyErr)
 			}
 
 			if count > 0 {
-				logger.Info(fmt.Sprintf("Found existing song: %s", songName))
+				logger.Info("Found existing song", "song", songName, "song_id", existingID)
 				return existingID, nil
 			}
 			// The song entry no longer exists despite the hash match
-			logger.Info(fmt.Sprintf("Found hash for song %s, but the record doesn't exist - will create new entry", songName))
+			logger.Warn("Found hash for song, but the record doesn't exist - will create new entry", "song", songName, "song_id", existingID)
 		} else {
 			return 0, fmt.Errorf("error checking for existing song: %w", err)
 		}
@@ -200,51 +162,51 @@ func (m *DB) insertSongWithID(songName string, artistName string, fileHash strin
 		m.cfg.Tables.Songs.Fields.TotalHashes,
 		m.cfg.Tables.Songs.Fields.Fingerprinted)
 
-	result, err := m.conn.Exec(insertQuery, songName, artistName, fileHash, totalHashes, 0)
+	result, err := m.conn.ExecContext(ctx, insertQuery, songName, artistName, fileHash, totalHashes, 0)
 	if err != nil {
 		return 0, fmt.Errorf("error inserting song: %w", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err == nil {
-		logger.Info(fmt.Sprintf("Added new song: %s", songName))
+		logger.Info("Added new song", "song", songName, "song_id", id)
 	}
 	return id, err
 }
 
 // InsertSong implements the Database interface
-func (m *DB) InsertSong(songName string, artistName string, fileHash string, totalHashes int) (int, error) {
-	id, err := m.insertSongWithID(songName, artistName, fileHash, totalHashes)
+func (m *DB) InsertSong(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int, error) {
+	id, err := m.insertSongWithID(ctx, songName, artistName, fileHash, totalHashes)
 	return int(id), err
 }
 
 // Insert fingerprints into fingerprints table
-func (m *DB) InsertFingerprints(fingerprint string, songID int, offset int) error {
+func (m *DB) InsertFingerprints(ctx context.Context, fingerprint string, songID int, offset int) error {
 	query := fmt.Sprintf("INSERT IGNORE INTO %s (%s, %s, %s) VALUES (?, ?, ?)",
 		m.cfg.Tables.Fingerprints.Name,
 		m.cfg.Tables.Songs.Fields.ID,
 		m.cfg.Tables.Fingerprints.Fields.Hash,
 		m.cfg.Tables.Fingerprints.Fields.Offset)
 
-	_, err := m.conn.Exec(query, songID, fingerprint, offset)
+	_, err := m.conn.ExecContext(ctx, query, songID, fingerprint, offset)
 	return err
 }
 
 // UpdateSongFingerprinted marks a song as fingerprinted in the database
-func (m *DB) UpdateSongFingerprinted(songID int) error {
+func (m *DB) UpdateSongFingerprinted(ctx context.Context, songID int) error {
 	// First check if the song exists
 	checkQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?",
 		m.cfg.Tables.Songs.Name,
 		m.cfg.Tables.Songs.Fields.ID)
 
 	var count int
-	err := m.conn.QueryRow(checkQuery, songID).Scan(&count)
+	err := m.conn.QueryRowContext(ctx, checkQuery, songID).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("error checking if song exists: %w", err)
 	}
 
 	if count == 0 {
-		return fmt.Errorf("song with ID %d not found", songID)
+		return fmt.Errorf("song with ID %d %w", songID, ErrNotFound)
 	}
 
 	// Song exists, update it
@@ -253,7 +215,7 @@ func (m *DB) UpdateSongFingerprinted(songID int) error {
 		m.cfg.Tables.Songs.Fields.Fingerprinted,
 		m.cfg.Tables.Songs.Fields.ID)
 
-	_, err = m.conn.Exec(updateQuery, songID)
+	_, err = m.conn.ExecContext(ctx, updateQuery, songID)
 	if err != nil {
 		return fmt.Errorf("error updating song fingerprinted status: %w", err)
 	}
@@ -261,39 +223,65 @@ func (m *DB) UpdateSongFingerprinted(songID int) error {
 	return nil
 }
 
-// ListSongs returns all songs from the database
-func (m *DB) ListSongs() ([]Song, error) {
-	query := fmt.Sprintf("SELECT %s, %s, artist, %s, HEX(%s), %s, date_created FROM %s",
+// ListSongs returns the songs selected by query and the number of songs matching its filters
+func (m *DB) ListSongs(ctx context.Context, query catalog.SongQuery) (SongPage, error) {
+	if err := query.Check(); err != nil {
+		return SongPage{}, err
+	}
+	where, args := query.Where(m.cfg, "LIKE", func(int) string { return "?" })
+
+	var page SongPage
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", m.cfg.Tables.Songs.Name, where)
+	if err := m.conn.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return SongPage{}, fmt.Errorf("error counting songs: %w", err)
+	}
+
+	selectQuery := fmt.Sprintf("SELECT %s, %s, %s, %s, %s, HEX(%s), %s, date_created FROM %s%s%s",
 		m.cfg.Tables.Songs.Fields.ID,
 		m.cfg.Tables.Songs.Fields.Name,
+		m.cfg.Tables.Songs.Fields.Artist,
+		m.cfg.Tables.Songs.Fields.Album,
 		m.cfg.Tables.Songs.Fields.Fingerprinted,
 		m.cfg.Tables.Songs.Fields.FileSHA1,
 		m.cfg.Tables.Songs.Fields.TotalHashes,
-		m.cfg.Tables.Songs.Name)
+		m.cfg.Tables.Songs.Name,
+		where,
+		query.OrderBy(m.cfg))
+	switch {
+	case query.Limit > 0:
+		selectQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	case query.Offset > 0:
+		// MySQL has no OFFSET without LIMIT, this is its documented largest limit
+		selectQuery += " LIMIT 18446744073709551615 OFFSET ?"
+		args = append(args, query.Offset)
+	}
 
-	rows, err := m.conn.Query(query)
+	rows, err := m.conn.QueryContext(ctx, selectQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error querying songs: %w", err)
+		return SongPage{}, fmt.Errorf("error querying songs: %w", err)
 	}
 	defer rows.Close()
 
-	var songs []Song
 	for rows.Next() {
 		var s Song
-		if err := rows.Scan(&s.ID, &s.Name, &s.Artist, &s.Fingerprinted, &s.FileSHA1, &s.TotalHashes, &s.DateCreated); err != nil {
-			return nil, fmt.Errorf("error scanning song row: %w", err)
+		if err := rows.Scan(&s.ID, &s.Name, &s.Artist, &s.Album, &s.Fingerprinted, &s.FileSHA1, &s.TotalHashes, &s.DateCreated); err != nil {
+			return SongPage{}, fmt.Errorf("error scanning song row: %w", err)
 		}
-		songs = append(songs, s)
+		page.Songs = append(page.Songs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return SongPage{}, fmt.Errorf("error querying songs: %w", err)
 	}
 
-	return songs, nil
+	return page, nil
 }
 
 // Cleanup performs general database cleanup:
 // 1. Removes duplicate songs keeping only the fingerprinted ones
 // 2. Removes unfingerprinted songs
 // 3. Removes orphaned fingerprints (those without corresponding songs)
-func (m *DB) Cleanup() error {
+func (m *DB) Cleanup(ctx context.Context) error {
 	// Keep only fingerprinted songs if duplicates exist
 	duplicatesQuery := fmt.Sprintf(`
 		DELETE s1 FROM %s s1
@@ -308,13 +296,13 @@ func (m *DB) Cleanup() error {
 		m.cfg.Tables.Songs.Fields.Fingerprinted,
 		m.cfg.Tables.Songs.Fields.Fingerprinted)
 
-	result, err := m.conn.Exec(duplicatesQuery)
+	result, err := m.conn.ExecContext(ctx, duplicatesQuery)
 	if err != nil {
 		return fmt.Errorf("error cleaning up duplicates: %w", err)
 	}
 
 	if rows, _ := result.RowsAffected(); rows > 0 {
-		logger.Info(fmt.Sprintf("Cleaned up %d duplicate songs", rows))
+		logger.Info("Cleaned up duplicate songs", "rows", rows)
 	}
 
 	// Delete unfingerprinted songs
@@ -322,13 +310,13 @@ func (m *DB) Cleanup() error {
 		m.cfg.Tables.Songs.Name,
 		m.cfg.Tables.Songs.Fields.Fingerprinted)
 
-	result, err = m.conn.Exec(unfingerSQL)
+	result, err = m.conn.ExecContext(ctx, unfingerSQL)
 	if err != nil {
 		return fmt.Errorf("error cleaning up unfingerprinted songs: %w", err)
 	}
 
 	if rows, _ := result.RowsAffected(); rows > 0 {
-		logger.Info(fmt.Sprintf("Cleaned up %d unfingerprinted songs", rows))
+		logger.Info("Cleaned up unfingerprinted songs", "rows", rows)
 	}
 
 	// Delete orphaned fingerprints (those without corresponding songs)
@@ -342,27 +330,27 @@ func (m *DB) Cleanup() error {
 		m.cfg.Tables.Songs.Fields.ID,
 		m.cfg.Tables.Songs.Fields.ID)
 
-	result, err = m.conn.Exec(orphanedFPQuery)
+	result, err = m.conn.ExecContext(ctx, orphanedFPQuery)
 	if err != nil {
 		return fmt.Errorf("error cleaning up orphaned fingerprints: %w", err)
 	}
 
 	if rows, _ := result.RowsAffected(); rows > 0 {
-		logger.Info(fmt.Sprintf("Cleaned up %d orphaned fingerprints", rows))
+		logger.Info("Cleaned up orphaned fingerprints", "rows", rows)
 	}
 
 	return nil
 }
 
 // DeleteSong deletes a song and its fingerprints from the database
-func (m *DB) DeleteSong(songID int) error {
+func (m *DB) DeleteSong(ctx context.Context, songID int) error {
 	// Since we have ON DELETE CASCADE, we only need to delete the song
 	// and the fingerprints will be automatically deleted
 	query := fmt.Sprintf("DELETE FROM %s WHERE %s = ?",
 		m.cfg.Tables.Songs.Name,
 		m.cfg.Tables.Songs.Fields.ID)
 
-	result, err := m.conn.Exec(query, songID)
+	result, err := m.conn.ExecContext(ctx, query, songID)
 	if err != nil {
 		return fmt.Errorf("error deleting song: %w", err)
 	}
@@ -373,15 +361,15 @@ func (m *DB) DeleteSong(songID int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("song with ID %d not found", songID)
+		return fmt.Errorf("song with ID %d %w", songID, ErrNotFound)
 	}
 
-	logger.Info(fmt.Sprintf("Successfully deleted song with ID %d", songID))
+	logger.Info("Deleted song", "song_id", songID)
 	return nil
 }
 
 // QueryFingerprints queries the database for matching fingerprints
-func (m *DB) QueryFingerprints(hashes []string) ([]FingerprintMatch, error) {
+func (m *DB) QueryFingerprints(ctx context.Context, hashes []string) ([]FingerprintMatch, error) {
 	if len(hashes) == 0 {
 		return []FingerprintMatch{}, nil
 	}
@@ -407,7 +395,7 @@ func (m *DB) QueryFingerprints(hashes []string) ([]FingerprintMatch, error) {
 		args[i] = hash
 	}
 
-	rows, err := m.conn.Query(query, args...)
+	rows, err := m.conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying fingerprints: %w", err)
 	}
@@ -421,27 +409,121 @@ func (m *DB) QueryFingerprints(hashes []string) ([]FingerprintMatch, error) {
 		}
 		matches = append(matches, match)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying fingerprints: %w", err)
+	}
 
 	return matches, nil
 }
 
 // GetSongByID retrieves song information by ID
-func (m *DB) GetSongByID(songID int) (SongInfo, error) {
-	query := fmt.Sprintf("SELECT %s, %s, %s FROM %s WHERE %s = ?",
+func (m *DB) GetSongByID(ctx context.Context, songID int) (SongInfo, error) {
+	query := fmt.Sprintf("SELECT %s, %s, %s, %s FROM %s WHERE %s = ?",
 		m.cfg.Tables.Songs.Fields.ID,
 		m.cfg.Tables.Songs.Fields.Name,
 		m.cfg.Tables.Songs.Fields.Artist,
+		m.cfg.Tables.Songs.Fields.Album,
 		m.cfg.Tables.Songs.Name,
 		m.cfg.Tables.Songs.Fields.ID)
 
 	var song SongInfo
-	err := m.conn.QueryRow(query, songID).Scan(&song.ID, &song.Name, &song.Artist)
+	err := m.conn.QueryRowContext(ctx, query, songID).Scan(&song.ID, &song.Name, &song.Artist, &song.Album)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return SongInfo{}, fmt.Errorf("song with ID %d not found", songID)
+			return SongInfo{}, fmt.Errorf("song with ID %d %w", songID, ErrNotFound)
 		}
 		return SongInfo{}, fmt.Errorf("error querying song: %w", err)
 	}
 
 	return song, nil
 }
+
+// UpdateSong changes the metadata set in update and leaves the fingerprints untouched
+func (m *DB) UpdateSong(ctx context.Context, songID int, update SongUpdate) error {
+	// Check first, MySQL reports no affected rows when the values do not change
+	checkQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?",
+		m.cfg.Tables.Songs.Name,
+		m.cfg.Tables.Songs.Fields.ID)
+
+	var count int
+	if err := m.conn.QueryRowContext(ctx, checkQuery, songID).Scan(&count); err != nil {
+		return fmt.Errorf("error checking if song exists: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("song with ID %d %w", songID, ErrNotFound)
+	}
+
+	var assignments []string
+	var args []interface{}
+	for _, field := range []struct {
+		column string
+		value  *string
+	}{
+		{m.cfg.Tables.Songs.Fields.Name, update.Name},
+		{m.cfg.Tables.Songs.Fields.Artist, update.Artist},
+		{m.cfg.Tables.Songs.Fields.Album, update.Album},
+	} {
+		if field.value != nil {
+			assignments = append(assignments, field.column+" = ?")
+			args = append(args, *field.value)
+		}
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?",
+		m.cfg.Tables.Songs.Name,
+		strings.Join(assignments, ", "),
+		m.cfg.Tables.Songs.Fields.ID)
+
+	if _, err := m.conn.ExecContext(ctx, query, append(args, songID)...); err != nil {
+		return fmt.Errorf("error updating song: %w", err)
+	}
+
+	logger.Info("Updated song", "song_id", songID)
+	return nil
+}
+
+// InsertPlay stores a detected airing of a song on a monitored channel
+func (m *DB) InsertPlay(ctx context.Context, play Play) error {
+	query := fmt.Sprintf("INSERT INTO %s (channel, %s, started_at, ended_at, score) VALUES (?, ?, ?, ?, ?)",
+		m.cfg.Tables.Plays.Name,
+		m.cfg.Tables.Songs.Fields.ID)
+
+	_, err := m.conn.ExecContext(ctx, query, play.Channel, play.SongID, play.StartedAt.UTC(), play.EndedAt.UTC(), play.Score)
+	if err != nil {
+		return fmt.Errorf("error inserting play: %w", err)
+	}
+
+	return nil
+}
+
+// ScanFingerprints calls fn with each fingerprint of a song in offset order
+func (m *DB) ScanFingerprints(ctx context.Context, songID int, fn func(hash string, offset int) error) error {
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s = ? ORDER BY %s",
+		m.cfg.Tables.Fingerprints.Fields.Hash,
+		m.cfg.Tables.Fingerprints.Fields.Offset,
+		m.cfg.Tables.Fingerprints.Name,
+		m.cfg.Tables.Songs.Fields.ID,
+		m.cfg.Tables.Fingerprints.Fields.Offset)
+
+	rows, err := m.conn.QueryContext(ctx, query, songID)
+	if err != nil {
+		return fmt.Errorf("error querying fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		var offset int
+		if err := rows.Scan(&hash, &offset); err != nil {
+			return fmt.Errorf("error scanning fingerprint: %w", err)
+		}
+		if err := fn(hash, offset); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,215 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/catalog"
+)
+
+// Recognition is a recognition recorded in the history
+type Recognition struct {
+	ID           int64
+	RecognizedAt time.Time
+	Source       string // file, mic or api
+	ClientID     string // API client, empty for the CLI
+	AudioMs      int64  // Duration of the audio queried
+	LatencyMs    int64
+	Matches      []RecognitionMatch // Best first, empty when nothing matched
+}
+
+// RecognitionMatch is a song matched by a recognition
+type RecognitionMatch struct {
+	SongID int     `json:"song_id"`
+	Song   string  `json:"song"`
+	Artist string  `json:"artist"`
+	Score  float64 `json:"score"`
+	Offset int     `json:"offset_ms"`
+}
+
+// RecognitionQuery filters and paginates ListRecognitions, newest first. The zero value
+// lists the whole history.
+type RecognitionQuery struct {
+	Source   string
+	ClientID string
+	SongID   int       // Recognitions whose best match is this song, unless 0
+	Matched  *bool     // Recognitions that matched a song or not, nil for any
+	Since    time.Time // Recognitions at or after this time, unless zero
+	Until    time.Time // Recognitions before this time, unless zero
+	Limit    int       // Maximum number of recognitions, 0 for no limit
+	Offset   int       // Number of matching recognitions skipped
+}
+
+// RecognitionPage is the result of ListRecognitions
+type RecognitionPage struct {
+	Recognitions []Recognition
+	Total        int // Recognitions matching the filters, ignoring Limit and Offset
+}
+
+// Check returns an error wrapping catalog.ErrInvalidQuery when the query cannot be run
+func (q RecognitionQuery) Check() error {
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", catalog.ErrInvalidQuery, q.Limit)
+	}
+	if q.Offset < 0 {
+		return fmt.Errorf("%w: negative offset %d", catalog.ErrInvalidQuery, q.Offset)
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return fmt.Errorf("%w: empty time range", catalog.ErrInvalidQuery)
+	}
+	return nil
+}
+
+// Where returns the WHERE clause selecting the recognitions of the query, or an empty
+// string, and its arguments. placeholder returns the bind parameter of the backend for
+// the n-th argument, starting at 1.
+func (q RecognitionQuery) Where(cfg config.Config, placeholder func(n int) string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, placeholder(len(args))))
+	}
+
+	if q.Source != "" {
+		add("source = %s", q.Source)
+	}
+	if q.ClientID != "" {
+		add("client_id = %s", q.ClientID)
+	}
+	if q.SongID != 0 {
+		add(cfg.Tables.Songs.Fields.ID+" = %s", q.SongID)
+	}
+	if q.Matched != nil {
+		if *q.Matched {
+			conditions = append(conditions, cfg.Tables.Songs.Fields.ID+" IS NOT NULL")
+		} else {
+			conditions = append(conditions, cfg.Tables.Songs.Fields.ID+" IS NULL")
+		}
+	}
+	if !q.Since.IsZero() {
+		add("recognized_at >= %s", q.Since.UTC())
+	}
+	if !q.Until.IsZero() {
+		add("recognized_at < %s", q.Until.UTC())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// HistoryRow returns the columns stored for a recognition after its ID and time:
+// source, client_id, audio_ms, latency_ms, the song ID and score of the best match
+// (nil without matches) and the matches as JSON
+func (r Recognition) HistoryRow() ([]interface{}, error) {
+	matches := r.Matches
+	if matches == nil {
+		matches = []RecognitionMatch{}
+	}
+	encoded, err := json.Marshal(matches)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding matches: %w", err)
+	}
+
+	var songID, score interface{}
+	if len(r.Matches) > 0 {
+		songID, score = r.Matches[0].SongID, r.Matches[0].Score
+	}
+	return []interface{}{r.Source, r.ClientID, r.AudioMs, r.LatencyMs, songID, score, string(encoded)}, nil
+}
+
+// DecodeMatches parses the matches column of the history
+func DecodeMatches(encoded string) ([]RecognitionMatch, error) {
+	var matches []RecognitionMatch
+	if err := json.Unmarshal([]byte(encoded), &matches); err != nil {
+		return nil, fmt.Errorf("error decoding matches: %w", err)
+	}
+	return matches, nil
+}
+
+// InsertRecognition records a recognition in the history
+func (m *DB) InsertRecognition(ctx context.Context, recognition Recognition) error {
+	row, err := recognition.HistoryRow()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (recognized_at, source, client_id, audio_ms, latency_ms, %s, score, matches)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.cfg.Tables.History.Name,
+		m.cfg.Tables.Songs.Fields.ID)
+
+	args := append([]interface{}{recognition.RecognizedAt.UTC()}, row...)
+	if _, err := m.conn.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error inserting recognition: %w", err)
+	}
+	return nil
+}
+
+// ListRecognitions returns the recognitions selected by query, newest first, and the
+// number of recognitions matching its filters
+func (m *DB) ListRecognitions(ctx context.Context, query RecognitionQuery) (RecognitionPage, error) {
+	if err := query.Check(); err != nil {
+		return RecognitionPage{}, err
+	}
+	where, args := query.Where(m.cfg, func(int) string { return "?" })
+
+	var page RecognitionPage
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", m.cfg.Tables.History.Name, where)
+	if err := m.conn.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return RecognitionPage{}, fmt.Errorf("error counting recognitions: %w", err)
+	}
+
+	selectQuery := fmt.Sprintf(`SELECT recognition_id, recognized_at, source, client_id, audio_ms, latency_ms, matches
+		FROM %s%s ORDER BY recognized_at DESC, recognition_id DESC`,
+		m.cfg.Tables.History.Name,
+		where)
+	switch {
+	case query.Limit > 0:
+		selectQuery += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit, query.Offset)
+	case query.Offset > 0:
+		// MySQL has no OFFSET without LIMIT, this is its documented largest limit
+		selectQuery += " LIMIT 18446744073709551615 OFFSET ?"
+		args = append(args, query.Offset)
+	}
+
+	rows, err := m.conn.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return RecognitionPage{}, fmt.Errorf("error querying recognitions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r Recognition
+		var matches string
+		if err := rows.Scan(&r.ID, &r.RecognizedAt, &r.Source, &r.ClientID, &r.AudioMs, &r.LatencyMs, &matches); err != nil {
+			return RecognitionPage{}, fmt.Errorf("error scanning recognition: %w", err)
+		}
+		if r.Matches, err = DecodeMatches(matches); err != nil {
+			return RecognitionPage{}, err
+		}
+		page.Recognitions = append(page.Recognitions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return RecognitionPage{}, fmt.Errorf("error querying recognitions: %w", err)
+	}
+
+	return page, nil
+}
+
+// DeleteRecognitionsBefore removes the recognitions older than t and returns their number
+func (m *DB) DeleteRecognitionsBefore(ctx context.Context, t time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE recognized_at < ?", m.cfg.Tables.History.Name)
+	result, err := m.conn.ExecContext(ctx, query, t.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("error deleting recognitions: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,183 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/media-luna/eureka/internal/database/migrate"
+)
+
+const createSchemaVersionTableSQL = `
+	CREATE TABLE IF NOT EXISTS ` + migrate.VersionTable + ` (
+		version INT UNSIGNED NOT NULL,
+		description VARCHAR(250) NOT NULL,
+		applied_at DATETIME NOT NULL,
+		PRIMARY KEY (version)
+	) ENGINE=INNODB;`
+
+// dialect is the MySQL syntax of the migration runner
+var dialect = migrate.Dialect{
+	CreateVersionTable: createSchemaVersionTableSQL,
+	Placeholder:        func(int) string { return "?" },
+}
+
+// Migrator returns the schema migrations of the MySQL backend
+func (m *DB) Migrator() *migrate.Migrator {
+	return migrate.New(m.conn, dialect, m.migrations())
+}
+
+// migrations lists the schema changes in version order, never edit a released one,
+// append a new version instead. Their DDL is written out here rather than shared with
+// the rest of the package, so that no later change alters what a version creates.
+func (m *DB) migrations() []migrate.Migration {
+	songs := m.cfg.Tables.Songs
+	fingerprints := m.cfg.Tables.Fingerprints
+
+	return []migrate.Migration{
+		{
+			// Version 1 is the schema created by releases without migrations, its
+			// statements are no-ops on such databases
+			Version:     1,
+			Description: "create songs, fingerprints and plays tables",
+			Up: []string{
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						%s MEDIUMINT UNSIGNED NOT NULL AUTO_INCREMENT,
+						%s VARCHAR(250) NOT NULL,
+						%s VARCHAR(250) DEFAULT '',
+						%s TINYINT DEFAULT 0,
+						%s BINARY(20) NOT NULL,
+						%s INT NOT NULL DEFAULT 0,
+						date_created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						date_modified DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+						PRIMARY KEY (%s),
+						UNIQUE KEY file_sha1_idx (%s)
+					) ENGINE=INNODB;`,
+					songs.Name,
+					songs.Fields.ID,
+					songs.Fields.Name,
+					songs.Fields.Artist,
+					songs.Fields.Fingerprinted,
+					songs.Fields.FileSHA1,
+					songs.Fields.TotalHashes,
+					songs.Fields.ID,
+					songs.Fields.FileSHA1),
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						%s CHAR(40) NOT NULL,
+						%s MEDIUMINT UNSIGNED NOT NULL,
+						%s INT UNSIGNED NOT NULL,
+						date_created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						date_modified DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+						INDEX ix_%s_%s (%s),
+						CONSTRAINT uq_%s_%s_%s_%s UNIQUE KEY (%s, %s, %s),
+						CONSTRAINT fk_%s_%s FOREIGN KEY (%s)
+							REFERENCES %s(%s) ON DELETE CASCADE
+					) ENGINE=INNODB;`,
+					fingerprints.Name,
+					fingerprints.Fields.Hash,
+					songs.Fields.ID,
+					fingerprints.Fields.Offset,
+					fingerprints.Name,
+					fingerprints.Fields.Hash,
+					fingerprints.Fields.Hash,
+					fingerprints.Name,
+					songs.Fields.ID,
+					fingerprints.Fields.Offset,
+					fingerprints.Fields.Hash,
+					songs.Fields.ID,
+					fingerprints.Fields.Offset,
+					fingerprints.Fields.Hash,
+					fingerprints.Name,
+					songs.Fields.ID,
+					songs.Fields.ID,
+					songs.Name,
+					songs.Fields.ID),
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						play_id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+						channel VARCHAR(250) NOT NULL,
+						%s MEDIUMINT UNSIGNED NOT NULL,
+						started_at DATETIME(3) NOT NULL,
+						ended_at DATETIME(3) NOT NULL,
+						score DOUBLE NOT NULL DEFAULT 0,
+						date_created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (play_id),
+						INDEX ix_%s_channel_started (channel, started_at),
+						CONSTRAINT fk_%s_%s FOREIGN KEY (%s)
+							REFERENCES %s(%s) ON DELETE CASCADE
+					) ENGINE=INNODB;`,
+					m.cfg.Tables.Plays.Name,
+					songs.Fields.ID,
+					m.cfg.Tables.Plays.Name,
+					m.cfg.Tables.Plays.Name,
+					songs.Fields.ID,
+					songs.Fields.ID,
+					songs.Name,
+					songs.Fields.ID),
+			},
+			Down: []string{
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", m.cfg.Tables.Plays.Name),
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", fingerprints.Name),
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", songs.Name),
+			},
+		},
+		{
+			Version:     2,
+			Description: "add album to songs",
+			Up: []string{
+				fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s VARCHAR(250) DEFAULT '' AFTER %s",
+					songs.Name, songs.Fields.Album, songs.Fields.Artist),
+			},
+			Down: []string{
+				fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", songs.Name, songs.Fields.Album),
+			},
+		},
+		{
+			Version:     3,
+			Description: "create stoplist table",
+			Up: []string{
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						%s CHAR(40) NOT NULL,
+						songs INT UNSIGNED NOT NULL,
+						occurrences BIGINT UNSIGNED NOT NULL,
+						date_created DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (%s)
+					) ENGINE=INNODB;`,
+					m.cfg.Tables.StopList.Name, fingerprints.Fields.Hash, fingerprints.Fields.Hash),
+			},
+			Down: []string{
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", m.cfg.Tables.StopList.Name),
+			},
+		},
+		{
+			Version:     4,
+			Description: "create recognitions table",
+			Up: []string{
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						recognition_id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+						recognized_at DATETIME(3) NOT NULL,
+						source VARCHAR(16) NOT NULL,
+						client_id VARCHAR(250) NOT NULL DEFAULT '',
+						audio_ms INT UNSIGNED NOT NULL,
+						latency_ms INT UNSIGNED NOT NULL,
+						%s MEDIUMINT UNSIGNED NULL,
+						score DOUBLE NULL,
+						matches TEXT NOT NULL,
+						PRIMARY KEY (recognition_id),
+						INDEX ix_%s_recognized_at (recognized_at),
+						INDEX ix_%s_song_recognized (%s, recognized_at)
+					) ENGINE=INNODB;`,
+					m.cfg.Tables.History.Name,
+					songs.Fields.ID,
+					m.cfg.Tables.History.Name,
+					m.cfg.Tables.History.Name,
+					songs.Fields.ID),
+			},
+			Down: []string{
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", m.cfg.Tables.History.Name),
+			},
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+)
+
+// catalogDialect is the PostgreSQL syntax of the catalog queries, hashes are stored
+// as bytes
+var catalogDialect = catalog.Dialect{
+	Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	HexHash:     func(column string) string { return fmt.Sprintf("encode(%s, 'hex')", column) },
+	// reltuples is -1 until the table is first analyzed
+	TableSizes: `SELECT c.relname, GREATEST(c.reltuples, 0)::BIGINT, pg_total_relation_size(c.oid)
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind = 'r' AND c.relname IN (%s)
+		ORDER BY c.relname`,
+}
+
+func (p *DB) catalog() *catalog.Queries {
+	return catalog.New(p.conn, p.cfg, catalogDialect)
+}
+
+// Stats reads the catalog statistics with the topHashes most shared hashes. It scans
+// the whole fingerprints table, expect it to take a while on large catalogs.
+func (p *DB) Stats(ctx context.Context, topHashes int) (catalog.Stats, error) {
+	return p.catalog().Stats(ctx, topHashes)
+}
+
+// StopList returns the stop-listed hashes, found in the most songs first
+func (p *DB) StopList(ctx context.Context) ([]catalog.HashFrequency, error) {
+	return p.catalog().StopList(ctx)
+}
+
+// BuildStopList replaces the stop-list with the hashes found in at least minSongs
+// songs and returns their number. It scans the whole fingerprints table.
+func (p *DB) BuildStopList(ctx context.Context, minSongs int) (int, error) {
+	return p.catalog().BuildStopList(ctx, minSongs)
+}
+
+// ClearStopList removes every stop-listed hash
+func (p *DB) ClearStopList(ctx context.Context) error {
+	return p.catalog().ClearStopList(ctx)
+}
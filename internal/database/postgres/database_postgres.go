@@ -1,11 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
 	"github.com/media-luna/eureka/utils/logger"
 )
 
@@ -15,43 +19,25 @@ type DB struct {
 	cfg  config.Config
 }
 
-const (
-	createSongsTableSQL = `
-		CREATE TABLE IF NOT EXISTS %s (
-			%s SERIAL PRIMARY KEY,
-			%s VARCHAR(250) NOT NULL,
-			%s SMALLINT DEFAULT 0,
-			%s BYTEA NOT NULL,
-			%s INTEGER NOT NULL DEFAULT 0,
-			date_created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			date_modified TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		);`
-
-	createFingerprintsTableSQL = `
-		CREATE TABLE IF NOT EXISTS %s (
-			%s BYTEA NOT NULL,
-			%s INTEGER NOT NULL REFERENCES %s(%s) ON DELETE CASCADE,
-			%s INTEGER NOT NULL,
-			date_created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			date_modified TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			UNIQUE (%s, %s, %s)
-		);
-		CREATE INDEX IF NOT EXISTS ix_%s_%s ON %s (%s);`
-
-	deleteUnfingerprintedSQL = `DELETE FROM %s WHERE %s = 0;`
-)
+const deleteUnfingerprintedSQL = `DELETE FROM %s WHERE %s = 0;`
 
 // NewDB creates a new DB instance with the given configuration.
-func NewDB(cfg config.Config) (*DB, error) {
+func NewDB(ctx context.Context, cfg config.Config) (*DB, error) {
 	db := &DB{cfg: cfg}
-	if err := db.connect(); err != nil {
+	if err := db.connect(ctx); err != nil {
 		return nil, err
 	}
 	return db, nil
 }
 
+// offsetColumn returns the quoted name of the offset column, OFFSET is reserved in
+// PostgreSQL and is the default name of the column
+func (p *DB) offsetColumn() string {
+	return pq.QuoteIdentifier(p.cfg.Tables.Fingerprints.Fields.Offset)
+}
+
 // Connect to the PostgreSQL database.
-func (p *DB) connect() error {
+func (p *DB) connect(ctx context.Context) error {
 	var err error
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s %s",
 		p.cfg.Database.Host,
@@ -61,42 +47,23 @@ func (p *DB) connect() error {
 		p.cfg.Database.DBName,
 		p.cfg.Database.Params)
 	p.conn, err = sql.Open("postgres", connStr)
-	return err
-}
-
-// Setup initializes the database tables.
-func (p *DB) Setup() error {
-	// Create songs table
-	songsSQL := fmt.Sprintf(createSongsTableSQL,
-		p.cfg.Tables.Songs.Name,
-		p.cfg.Tables.Songs.Fields.ID,
-		p.cfg.Tables.Songs.Fields.Name,
-		p.cfg.Tables.Songs.Fields.Fingerprinted,
-		p.cfg.Tables.Songs.Fields.FileSHA1,
-		p.cfg.Tables.Songs.Fields.TotalHashes)
-
-	if _, err := p.conn.Exec(songsSQL); err != nil {
-		return fmt.Errorf("error creating songs table: %w", err)
+	if err != nil {
+		return fmt.Errorf("failed to open connection: %w", err)
 	}
 
-	// Create fingerprints table
-	fpSQL := fmt.Sprintf(createFingerprintsTableSQL,
-		p.cfg.Tables.Fingerprints.Name,
-		p.cfg.Tables.Fingerprints.Fields.Hash,
-		p.cfg.Tables.Songs.Fields.ID,
-		p.cfg.Tables.Songs.Name,
-		p.cfg.Tables.Songs.Fields.ID,
-		p.cfg.Tables.Fingerprints.Fields.Offset,
-		p.cfg.Tables.Songs.Fields.ID,
-		p.cfg.Tables.Fingerprints.Fields.Hash,
-		p.cfg.Tables.Fingerprints.Fields.Offset,
-		p.cfg.Tables.Fingerprints.Name,
-		p.cfg.Tables.Fingerprints.Fields.Hash,
-		p.cfg.Tables.Fingerprints.Name,
-		p.cfg.Tables.Fingerprints.Fields.Hash)
+	// Test the connection
+	if err := p.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	logger.Info("Connected to PostgreSQL database", "host", p.cfg.Database.Host, "database", p.cfg.Database.DBName)
+	return nil
+}
 
-	if _, err := p.conn.Exec(fpSQL); err != nil {
-		return fmt.Errorf("error creating fingerprints table: %w", err)
+// Setup applies the pending schema migrations, creating the tables on a new database,
+// and removes songs whose fingerprinting did not complete.
+func (p *DB) Setup(ctx context.Context) error {
+	if _, err := p.Migrator().Up(ctx, 0); err != nil {
+		return err
 	}
 
 	// Delete unfingerprinted songs
@@ -104,7 +71,7 @@ func (p *DB) Setup() error {
 		p.cfg.Tables.Songs.Name,
 		p.cfg.Tables.Songs.Fields.Fingerprinted)
 
-	if _, err := p.conn.Exec(cleanupSQL); err != nil {
+	if _, err := p.conn.ExecContext(ctx, cleanupSQL); err != nil {
 		return fmt.Errorf("error cleaning up unfingerprinted songs: %w", err)
 	}
 
@@ -117,7 +84,7 @@ func (p *DB) Close() error {
 }
 
 // Insert song metadata into songs table
-func (p *DB) InsertSong(songName string, artistName string, fileHash string, totalHashes int) (int, error) {
+func (p *DB) InsertSong(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int, error) {
 	// Check if song with same hash already exists
 	var existingID int
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE encode(%s, 'hex') = $1",
@@ -125,7 +92,7 @@ func (p *DB) InsertSong(songName string, artistName string, fileHash string, tot
 		p.cfg.Tables.Songs.Name,
 		p.cfg.Tables.Songs.Fields.FileSHA1)
 
-	err := p.conn.QueryRow(query, fileHash).Scan(&existingID)
+	err := p.conn.QueryRowContext(ctx, query, fileHash).Scan(&existingID)
 	if err != sql.ErrNoRows {
 		if err == nil {
 			// Verify that the song still exists by ID
@@ -134,17 +101,17 @@ func (p *DB) InsertSong(songName string, artistName string, fileHash string, tot
 				p.cfg.Tables.Songs.Fields.ID)
 
 			var count int
-			verifyErr := p.conn.QueryRow(verifyQuery, existingID).Scan(&count)
+			verifyErr := p.conn.QueryRowContext(ctx, verifyQuery, existingID).Scan(&count)
 			if verifyErr != nil {
 				return 0, fmt.Errorf("error verifying existing song: %w", verifyErr)
 			}
 
 			if count > 0 {
-				logger.Info(fmt.Sprintf("Found existing song: %s", songName))
+				logger.Info("Found existing song", "song", songName, "song_id", existingID)
 				return existingID, nil
 			}
 			// The song entry no longer exists despite the hash match
-			logger.Info(fmt.Sprintf("Found hash for song %s, but the record doesn't exist - will create new entry", songName))
+			logger.Warn("Found hash for song, but the record doesn't exist - will create new entry", "song", songName, "song_id", existingID)
 		} else {
 			return 0, fmt.Errorf("error checking for existing song: %w", err)
 		}
@@ -161,40 +128,40 @@ func (p *DB) InsertSong(songName string, artistName string, fileHash string, tot
 		p.cfg.Tables.Songs.Fields.ID)
 
 	var id int
-	err = p.conn.QueryRow(insertQuery, songName, artistName, fileHash, totalHashes, 0).Scan(&id)
+	err = p.conn.QueryRowContext(ctx, insertQuery, songName, artistName, fileHash, totalHashes, 0).Scan(&id)
 	if err == nil {
-		logger.Info(fmt.Sprintf("Added new song: %s", songName))
+		logger.Info("Added new song", "song", songName, "song_id", id)
 	}
 	return id, err
 }
 
 // Insert fingerprints into fingerprints table
-func (p *DB) InsertFingerprints(fingerprint string, songID int, offset int) error {
+func (p *DB) InsertFingerprints(ctx context.Context, fingerprint string, songID int, offset int) error {
 	query := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES ($1, decode($2, 'hex'), $3) ON CONFLICT DO NOTHING",
 		p.cfg.Tables.Fingerprints.Name,
 		p.cfg.Tables.Songs.Fields.ID,
 		p.cfg.Tables.Fingerprints.Fields.Hash,
-		p.cfg.Tables.Fingerprints.Fields.Offset)
+		p.offsetColumn())
 
-	_, err := p.conn.Exec(query, songID, fingerprint, offset)
+	_, err := p.conn.ExecContext(ctx, query, songID, fingerprint, offset)
 	return err
 }
 
 // UpdateSongFingerprinted marks a song as fingerprinted in the database
-func (p *DB) UpdateSongFingerprinted(songID int) error {
+func (p *DB) UpdateSongFingerprinted(ctx context.Context, songID int) error {
 	// First check if the song exists
 	checkQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = $1",
 		p.cfg.Tables.Songs.Name,
 		p.cfg.Tables.Songs.Fields.ID)
 
 	var count int
-	err := p.conn.QueryRow(checkQuery, songID).Scan(&count)
+	err := p.conn.QueryRowContext(ctx, checkQuery, songID).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("error checking if song exists: %w", err)
 	}
 
 	if count == 0 {
-		return fmt.Errorf("song with ID %d not found", songID)
+		return fmt.Errorf("song with ID %d %w", songID, mysql.ErrNotFound)
 	}
 
 	// Song exists, update it
@@ -203,10 +170,304 @@ func (p *DB) UpdateSongFingerprinted(songID int) error {
 		p.cfg.Tables.Songs.Fields.Fingerprinted,
 		p.cfg.Tables.Songs.Fields.ID)
 
-	_, err = p.conn.Exec(updateQuery, songID)
+	_, err = p.conn.ExecContext(ctx, updateQuery, songID)
 	if err != nil {
 		return fmt.Errorf("error updating song fingerprinted status: %w", err)
 	}
 
 	return nil
 }
+
+// UpdateSong changes the metadata set in update and leaves the fingerprints untouched
+func (p *DB) UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) error {
+	assignments := []string{"date_modified = CURRENT_TIMESTAMP"}
+	var args []interface{}
+	for _, field := range []struct {
+		column string
+		value  *string
+	}{
+		{p.cfg.Tables.Songs.Fields.Name, update.Name},
+		{p.cfg.Tables.Songs.Fields.Artist, update.Artist},
+		{p.cfg.Tables.Songs.Fields.Album, update.Album},
+	} {
+		if field.value != nil {
+			args = append(args, *field.value)
+			assignments = append(assignments, fmt.Sprintf("%s = $%d", field.column, len(args)))
+		}
+	}
+
+	// Without changes the update still reports whether the song exists
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d",
+		p.cfg.Tables.Songs.Name,
+		strings.Join(assignments, ", "),
+		p.cfg.Tables.Songs.Fields.ID,
+		len(args)+1)
+
+	result, err := p.conn.ExecContext(ctx, query, append(args, songID)...)
+	if err != nil {
+		return fmt.Errorf("error updating song: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("song with ID %d %w", songID, mysql.ErrNotFound)
+	}
+
+	logger.Info("Updated song", "song_id", songID)
+	return nil
+}
+
+// InsertPlay stores a detected airing of a song on a monitored channel
+func (p *DB) InsertPlay(ctx context.Context, play mysql.Play) error {
+	query := fmt.Sprintf("INSERT INTO %s (channel, %s, started_at, ended_at, score) VALUES ($1, $2, $3, $4, $5)",
+		p.cfg.Tables.Plays.Name,
+		p.cfg.Tables.Songs.Fields.ID)
+
+	_, err := p.conn.ExecContext(ctx, query, play.Channel, play.SongID, play.StartedAt.UTC(), play.EndedAt.UTC(), play.Score)
+	if err != nil {
+		return fmt.Errorf("error inserting play: %w", err)
+	}
+
+	return nil
+}
+
+// ListSongs returns the songs selected by query and the number of songs matching its filters
+func (p *DB) ListSongs(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error) {
+	if err := query.Check(); err != nil {
+		return mysql.SongPage{}, err
+	}
+	where, args := query.Where(p.cfg, "ILIKE", dialect.Placeholder)
+
+	var page mysql.SongPage
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", p.cfg.Tables.Songs.Name, where)
+	if err := p.conn.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return mysql.SongPage{}, fmt.Errorf("error counting songs: %w", err)
+	}
+
+	selectQuery := fmt.Sprintf("SELECT %s, %s, %s, %s, %s = 1, upper(encode(%s, 'hex')), %s, to_char(date_created, 'YYYY-MM-DD HH24:MI:SS') FROM %s%s%s",
+		p.cfg.Tables.Songs.Fields.ID,
+		p.cfg.Tables.Songs.Fields.Name,
+		p.cfg.Tables.Songs.Fields.Artist,
+		p.cfg.Tables.Songs.Fields.Album,
+		p.cfg.Tables.Songs.Fields.Fingerprinted,
+		p.cfg.Tables.Songs.Fields.FileSHA1,
+		p.cfg.Tables.Songs.Fields.TotalHashes,
+		p.cfg.Tables.Songs.Name,
+		where,
+		query.OrderBy(p.cfg))
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		selectQuery += " LIMIT " + dialect.Placeholder(len(args))
+	}
+	if query.Offset > 0 {
+		args = append(args, query.Offset)
+		selectQuery += " OFFSET " + dialect.Placeholder(len(args))
+	}
+
+	rows, err := p.conn.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return mysql.SongPage{}, fmt.Errorf("error querying songs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s mysql.Song
+		if err := rows.Scan(&s.ID, &s.Name, &s.Artist, &s.Album, &s.Fingerprinted, &s.FileSHA1, &s.TotalHashes, &s.DateCreated); err != nil {
+			return mysql.SongPage{}, fmt.Errorf("error scanning song row: %w", err)
+		}
+		page.Songs = append(page.Songs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return mysql.SongPage{}, fmt.Errorf("error querying songs: %w", err)
+	}
+
+	return page, nil
+}
+
+// Cleanup performs general database cleanup:
+// 1. Removes duplicate songs keeping only the fingerprinted ones
+// 2. Removes unfingerprinted songs
+// 3. Removes orphaned fingerprints (those without corresponding songs)
+func (p *DB) Cleanup(ctx context.Context) error {
+	// Keep only fingerprinted songs if duplicates exist
+	duplicatesQuery := fmt.Sprintf(`
+		DELETE FROM %s s1
+		USING %s s2
+		WHERE s1.%s = s2.%s
+		AND s1.%s = 0
+		AND s2.%s = 1`,
+		p.cfg.Tables.Songs.Name,
+		p.cfg.Tables.Songs.Name,
+		p.cfg.Tables.Songs.Fields.FileSHA1,
+		p.cfg.Tables.Songs.Fields.FileSHA1,
+		p.cfg.Tables.Songs.Fields.Fingerprinted,
+		p.cfg.Tables.Songs.Fields.Fingerprinted)
+
+	result, err := p.conn.ExecContext(ctx, duplicatesQuery)
+	if err != nil {
+		return fmt.Errorf("error cleaning up duplicates: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		logger.Info("Cleaned up duplicate songs", "rows", rows)
+	}
+
+	// Delete unfingerprinted songs
+	unfingerSQL := fmt.Sprintf(deleteUnfingerprintedSQL,
+		p.cfg.Tables.Songs.Name,
+		p.cfg.Tables.Songs.Fields.Fingerprinted)
+
+	result, err = p.conn.ExecContext(ctx, unfingerSQL)
+	if err != nil {
+		return fmt.Errorf("error cleaning up unfingerprinted songs: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		logger.Info("Cleaned up unfingerprinted songs", "rows", rows)
+	}
+
+	// Delete orphaned fingerprints (those without corresponding songs)
+	orphanedFPQuery := fmt.Sprintf(`
+		DELETE FROM %s fp
+		WHERE NOT EXISTS (SELECT 1 FROM %s s WHERE s.%s = fp.%s)`,
+		p.cfg.Tables.Fingerprints.Name,
+		p.cfg.Tables.Songs.Name,
+		p.cfg.Tables.Songs.Fields.ID,
+		p.cfg.Tables.Songs.Fields.ID)
+
+	result, err = p.conn.ExecContext(ctx, orphanedFPQuery)
+	if err != nil {
+		return fmt.Errorf("error cleaning up orphaned fingerprints: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		logger.Info("Cleaned up orphaned fingerprints", "rows", rows)
+	}
+
+	return nil
+}
+
+// DeleteSong deletes a song and its fingerprints from the database
+func (p *DB) DeleteSong(ctx context.Context, songID int) error {
+	// Fingerprints and plays are deleted by ON DELETE CASCADE
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1",
+		p.cfg.Tables.Songs.Name,
+		p.cfg.Tables.Songs.Fields.ID)
+
+	result, err := p.conn.ExecContext(ctx, query, songID)
+	if err != nil {
+		return fmt.Errorf("error deleting song: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("song with ID %d %w", songID, mysql.ErrNotFound)
+	}
+
+	logger.Info("Deleted song", "song_id", songID)
+	return nil
+}
+
+// QueryFingerprints queries the database for matching fingerprints
+func (p *DB) QueryFingerprints(ctx context.Context, hashes []string) ([]mysql.FingerprintMatch, error) {
+	if len(hashes) == 0 {
+		return []mysql.FingerprintMatch{}, nil
+	}
+
+	// Decode the arguments rather than encoding the column so that the hash index is used
+	placeholders := make([]string, len(hashes))
+	args := make([]interface{}, len(hashes))
+	for i, hash := range hashes {
+		placeholders[i] = fmt.Sprintf("decode($%d, 'hex')", i+1)
+		args[i] = hash
+	}
+
+	query := fmt.Sprintf(`
+		SELECT encode(%s, 'hex'), %s, %s
+		FROM %s
+		WHERE %s IN (%s)`,
+		p.cfg.Tables.Fingerprints.Fields.Hash,
+		p.cfg.Tables.Songs.Fields.ID,
+		p.offsetColumn(),
+		p.cfg.Tables.Fingerprints.Name,
+		p.cfg.Tables.Fingerprints.Fields.Hash,
+		strings.Join(placeholders, ","))
+
+	rows, err := p.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []mysql.FingerprintMatch
+	for rows.Next() {
+		var match mysql.FingerprintMatch
+		if err := rows.Scan(&match.Hash, &match.SongID, &match.Offset); err != nil {
+			return nil, fmt.Errorf("error scanning fingerprint match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying fingerprints: %w", err)
+	}
+
+	return matches, nil
+}
+
+// GetSongByID retrieves song information by ID
+func (p *DB) GetSongByID(ctx context.Context, songID int) (mysql.SongInfo, error) {
+	query := fmt.Sprintf("SELECT %s, %s, %s, %s FROM %s WHERE %s = $1",
+		p.cfg.Tables.Songs.Fields.ID,
+		p.cfg.Tables.Songs.Fields.Name,
+		p.cfg.Tables.Songs.Fields.Artist,
+		p.cfg.Tables.Songs.Fields.Album,
+		p.cfg.Tables.Songs.Name,
+		p.cfg.Tables.Songs.Fields.ID)
+
+	var song mysql.SongInfo
+	err := p.conn.QueryRowContext(ctx, query, songID).Scan(&song.ID, &song.Name, &song.Artist, &song.Album)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return mysql.SongInfo{}, fmt.Errorf("song with ID %d %w", songID, mysql.ErrNotFound)
+		}
+		return mysql.SongInfo{}, fmt.Errorf("error querying song: %w", err)
+	}
+
+	return song, nil
+}
+
+// ScanFingerprints calls fn with each fingerprint of a song in offset order
+func (p *DB) ScanFingerprints(ctx context.Context, songID int, fn func(hash string, offset int) error) error {
+	query := fmt.Sprintf("SELECT encode(%s, 'hex'), %s FROM %s WHERE %s = $1 ORDER BY %s",
+		p.cfg.Tables.Fingerprints.Fields.Hash,
+		p.offsetColumn(),
+		p.cfg.Tables.Fingerprints.Name,
+		p.cfg.Tables.Songs.Fields.ID,
+		p.offsetColumn())
+
+	rows, err := p.conn.QueryContext(ctx, query, songID)
+	if err != nil {
+		return fmt.Errorf("error querying fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		var offset int
+		if err := rows.Scan(&hash, &offset); err != nil {
+			return fmt.Errorf("error scanning fingerprint: %w", err)
+		}
+		if err := fn(hash, offset); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/mysql"
+)
+
+// InsertRecognition records a recognition in the history
+func (p *DB) InsertRecognition(ctx context.Context, recognition mysql.Recognition) error {
+	row, err := recognition.HistoryRow()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (recognized_at, source, client_id, audio_ms, latency_ms, %s, score, matches)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		p.cfg.Tables.History.Name,
+		p.cfg.Tables.Songs.Fields.ID)
+
+	args := append([]interface{}{recognition.RecognizedAt.UTC()}, row...)
+	if _, err := p.conn.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error inserting recognition: %w", err)
+	}
+	return nil
+}
+
+// ListRecognitions returns the recognitions selected by query, newest first, and the
+// number of recognitions matching its filters
+func (p *DB) ListRecognitions(ctx context.Context, query mysql.RecognitionQuery) (mysql.RecognitionPage, error) {
+	if err := query.Check(); err != nil {
+		return mysql.RecognitionPage{}, err
+	}
+	where, args := query.Where(p.cfg, dialect.Placeholder)
+
+	var page mysql.RecognitionPage
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", p.cfg.Tables.History.Name, where)
+	if err := p.conn.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return mysql.RecognitionPage{}, fmt.Errorf("error counting recognitions: %w", err)
+	}
+
+	selectQuery := fmt.Sprintf(`SELECT recognition_id, recognized_at, source, client_id, audio_ms, latency_ms, matches
+		FROM %s%s ORDER BY recognized_at DESC, recognition_id DESC`,
+		p.cfg.Tables.History.Name,
+		where)
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		selectQuery += " LIMIT " + dialect.Placeholder(len(args))
+	}
+	if query.Offset > 0 {
+		args = append(args, query.Offset)
+		selectQuery += " OFFSET " + dialect.Placeholder(len(args))
+	}
+
+	rows, err := p.conn.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return mysql.RecognitionPage{}, fmt.Errorf("error querying recognitions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r mysql.Recognition
+		var matches string
+		if err := rows.Scan(&r.ID, &r.RecognizedAt, &r.Source, &r.ClientID, &r.AudioMs, &r.LatencyMs, &matches); err != nil {
+			return mysql.RecognitionPage{}, fmt.Errorf("error scanning recognition: %w", err)
+		}
+		if r.Matches, err = mysql.DecodeMatches(matches); err != nil {
+			return mysql.RecognitionPage{}, err
+		}
+		page.Recognitions = append(page.Recognitions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return mysql.RecognitionPage{}, fmt.Errorf("error querying recognitions: %w", err)
+	}
+
+	return page, nil
+}
+
+// DeleteRecognitionsBefore removes the recognitions older than t and returns their number
+func (p *DB) DeleteRecognitionsBefore(ctx context.Context, t time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE recognized_at < $1", p.cfg.Tables.History.Name)
+	result, err := p.conn.ExecContext(ctx, query, t.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("error deleting recognitions: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/media-luna/eureka/internal/database/migrate"
+)
+
+const createSchemaVersionTableSQL = `
+	CREATE TABLE IF NOT EXISTS ` + migrate.VersionTable + ` (
+		version INTEGER PRIMARY KEY,
+		description VARCHAR(250) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	);`
+
+// dialect is the PostgreSQL syntax of the migration runner
+var dialect = migrate.Dialect{
+	CreateVersionTable: createSchemaVersionTableSQL,
+	Placeholder:        func(n int) string { return fmt.Sprintf("$%d", n) },
+}
+
+// Migrator returns the schema migrations of the PostgreSQL backend
+func (p *DB) Migrator() *migrate.Migrator {
+	return migrate.New(p.conn, dialect, p.migrations())
+}
+
+// migrations lists the schema changes in version order, never edit a released one,
+// append a new version instead. Their DDL is written out here rather than shared with
+// the rest of the package, so that no later change alters what a version creates.
+func (p *DB) migrations() []migrate.Migration {
+	songs := p.cfg.Tables.Songs
+	fingerprints := p.cfg.Tables.Fingerprints
+	offset := p.offsetColumn()
+
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "create songs, fingerprints and plays tables",
+			Up: []string{
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						%s SERIAL PRIMARY KEY,
+						%s VARCHAR(250) NOT NULL,
+						%s VARCHAR(250) DEFAULT '',
+						%s SMALLINT DEFAULT 0,
+						%s BYTEA NOT NULL UNIQUE,
+						%s INTEGER NOT NULL DEFAULT 0,
+						date_created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						date_modified TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);`,
+					songs.Name,
+					songs.Fields.ID,
+					songs.Fields.Name,
+					songs.Fields.Artist,
+					songs.Fields.Fingerprinted,
+					songs.Fields.FileSHA1,
+					songs.Fields.TotalHashes),
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						%s BYTEA NOT NULL,
+						%s INTEGER NOT NULL REFERENCES %s(%s) ON DELETE CASCADE,
+						%s INTEGER NOT NULL,
+						date_created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						date_modified TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						UNIQUE (%s, %s, %s)
+					);
+					CREATE INDEX IF NOT EXISTS ix_%s_%s ON %s (%s);`,
+					fingerprints.Name,
+					fingerprints.Fields.Hash,
+					songs.Fields.ID,
+					songs.Name,
+					songs.Fields.ID,
+					offset,
+					songs.Fields.ID,
+					fingerprints.Fields.Hash,
+					offset,
+					fingerprints.Name,
+					fingerprints.Fields.Hash,
+					fingerprints.Name,
+					fingerprints.Fields.Hash),
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						play_id SERIAL PRIMARY KEY,
+						channel VARCHAR(250) NOT NULL,
+						%s INTEGER NOT NULL REFERENCES %s(%s) ON DELETE CASCADE,
+						started_at TIMESTAMP NOT NULL,
+						ended_at TIMESTAMP NOT NULL,
+						score DOUBLE PRECISION NOT NULL DEFAULT 0,
+						date_created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS ix_%s_channel_started ON %s (channel, started_at);`,
+					p.cfg.Tables.Plays.Name,
+					songs.Fields.ID,
+					songs.Name,
+					songs.Fields.ID,
+					p.cfg.Tables.Plays.Name,
+					p.cfg.Tables.Plays.Name),
+			},
+			Down: []string{
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", p.cfg.Tables.Plays.Name),
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", fingerprints.Name),
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", songs.Name),
+			},
+		},
+		{
+			Version:     2,
+			Description: "add album to songs",
+			Up: []string{
+				fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s VARCHAR(250) DEFAULT ''",
+					songs.Name, songs.Fields.Album),
+			},
+			Down: []string{
+				fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s", songs.Name, songs.Fields.Album),
+			},
+		},
+		{
+			Version:     3,
+			Description: "create stoplist table",
+			Up: []string{
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						%s BYTEA PRIMARY KEY,
+						songs INTEGER NOT NULL,
+						occurrences BIGINT NOT NULL,
+						date_created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);`,
+					p.cfg.Tables.StopList.Name, fingerprints.Fields.Hash),
+			},
+			Down: []string{
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", p.cfg.Tables.StopList.Name),
+			},
+		},
+		{
+			Version:     4,
+			Description: "create recognitions table",
+			Up: []string{
+				fmt.Sprintf(`
+					CREATE TABLE IF NOT EXISTS %s (
+						recognition_id BIGSERIAL PRIMARY KEY,
+						recognized_at TIMESTAMP(3) NOT NULL,
+						source VARCHAR(16) NOT NULL,
+						client_id VARCHAR(250) NOT NULL DEFAULT '',
+						audio_ms INTEGER NOT NULL,
+						latency_ms INTEGER NOT NULL,
+						%s INTEGER,
+						score DOUBLE PRECISION,
+						matches TEXT NOT NULL
+					);
+					CREATE INDEX IF NOT EXISTS ix_%s_recognized_at ON %s (recognized_at);
+					CREATE INDEX IF NOT EXISTS ix_%s_song_recognized ON %s (%s, recognized_at);`,
+					p.cfg.Tables.History.Name,
+					songs.Fields.ID,
+					p.cfg.Tables.History.Name,
+					p.cfg.Tables.History.Name,
+					p.cfg.Tables.History.Name,
+					p.cfg.Tables.History.Name,
+					songs.Fields.ID),
+			},
+			Down: []string{
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", p.cfg.Tables.History.Name),
+			},
+		},
+	}
+}
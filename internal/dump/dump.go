@@ -0,0 +1,229 @@
+package dump
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A dump is a gzip-compressed stream of JSON records, one per line: a header, then each
+// song followed by its fingerprints in chunks, then a trailer counting the records so
+// that a truncated file is detected. It does not depend on the database backend.
+
+// Format identifies Eureka dumps in their header
+const Format = "eureka-dump"
+
+// Version is the version of the dump format written by Writer, Reader reads it and
+// every older one
+const Version = 1
+
+// chunkSize is the maximum number of fingerprints in one record
+const chunkSize = 10000
+
+// ErrInvalid is wrapped by errors reporting a file that is not a complete dump
+var ErrInvalid = errors.New("invalid dump")
+
+// Header is the first record of a dump
+type Header struct {
+	Format           string    `json:"format"`
+	Version          int       `json:"version"`
+	AlgorithmVersion int       `json:"algorithm_version"` // fingerprint.ALGORITHM_VERSION of the exported hashes
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Song is the metadata of an exported song, identified across databases by the SHA1
+// of its audio file
+type Song struct {
+	Name        string `json:"name"`
+	Artist      string `json:"artist"`
+	Album       string `json:"album,omitempty"`
+	FileSHA1    string `json:"file_sha1"`
+	TotalHashes int    `json:"total_hashes"`
+}
+
+// Fingerprint is a hash at an offset in milliseconds, encoded as a [hash, offset] pair
+type Fingerprint struct {
+	Hash   string
+	Offset int
+}
+
+func (f Fingerprint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{f.Hash, f.Offset})
+}
+
+func (f *Fingerprint) UnmarshalJSON(data []byte) error {
+	pair := []interface{}{&f.Hash, &f.Offset}
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a [hash, offset] pair")
+	}
+	return nil
+}
+
+// Summary counts the records of a dump
+type Summary struct {
+	Songs        int `json:"songs"`
+	Fingerprints int `json:"fingerprints"`
+}
+
+// record is one line of a dump, exactly one field is set
+type record struct {
+	Header       *Header       `json:"header,omitempty"`
+	Song         *Song         `json:"song,omitempty"`
+	Fingerprints []Fingerprint `json:"fingerprints,omitempty"`
+	End          *Summary      `json:"end,omitempty"`
+}
+
+// Writer writes a dump
+type Writer struct {
+	gz      *gzip.Writer
+	encoder *json.Encoder
+	summary Summary
+	song    bool
+}
+
+// NewWriter writes the header of a dump of hashes generated by the given version of
+// the fingerprinting algorithm
+func NewWriter(w io.Writer, algorithmVersion int) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	dw := &Writer{gz: gz, encoder: json.NewEncoder(gz)}
+
+	header := &Header{
+		Format:           Format,
+		Version:          Version,
+		AlgorithmVersion: algorithmVersion,
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := dw.encoder.Encode(record{Header: header}); err != nil {
+		return nil, fmt.Errorf("error writing dump header: %w", err)
+	}
+	return dw, nil
+}
+
+// WriteSong starts a song, the fingerprints written next belong to it
+func (w *Writer) WriteSong(song Song) error {
+	if err := w.encoder.Encode(record{Song: &song}); err != nil {
+		return fmt.Errorf("error writing song: %w", err)
+	}
+	w.summary.Songs++
+	w.song = true
+	return nil
+}
+
+// WriteFingerprints writes fingerprints of the current song, split in chunks
+func (w *Writer) WriteFingerprints(fingerprints []Fingerprint) error {
+	if !w.song {
+		return fmt.Errorf("fingerprints written before any song")
+	}
+	for len(fingerprints) > 0 {
+		n := min(len(fingerprints), chunkSize)
+		if err := w.encoder.Encode(record{Fingerprints: fingerprints[:n]}); err != nil {
+			return fmt.Errorf("error writing fingerprints: %w", err)
+		}
+		w.summary.Fingerprints += n
+		fingerprints = fingerprints[n:]
+	}
+	return nil
+}
+
+// Close writes the trailer and flushes the compressed stream, it does not close the
+// underlying writer
+func (w *Writer) Close() (Summary, error) {
+	if err := w.encoder.Encode(record{End: &w.summary}); err != nil {
+		return w.summary, fmt.Errorf("error writing dump trailer: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		return w.summary, fmt.Errorf("error writing dump: %w", err)
+	}
+	return w.summary, nil
+}
+
+// Reader reads a dump record by record
+type Reader struct {
+	header  Header
+	decoder *json.Decoder
+	summary Summary
+	song    bool
+	done    bool
+}
+
+// NewReader reads and checks the header of a dump
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	dr := &Reader{decoder: json.NewDecoder(gz)}
+	var first record
+	if err := dr.decoder.Decode(&first); err != nil || first.Header == nil {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalid)
+	}
+	if first.Header.Format != Format {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalid, first.Header.Format)
+	}
+	if first.Header.Version < 1 || first.Header.Version > Version {
+		return nil, fmt.Errorf("%w: unsupported version %d, this release reads versions up to %d", ErrInvalid, first.Header.Version, Version)
+	}
+	dr.header = *first.Header
+	return dr, nil
+}
+
+// Header returns the header of the dump
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next returns the next song or the next fingerprints of the current song, exactly
+// one of them is set. It returns io.EOF after the trailer once the record counts have
+// been checked.
+func (r *Reader) Next() (*Song, []Fingerprint, error) {
+	if r.done {
+		return nil, nil, io.EOF
+	}
+
+	var rec record
+	if err := r.decoder.Decode(&rec); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, nil, fmt.Errorf("%w: truncated after %d songs", ErrInvalid, r.summary.Songs)
+		}
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	switch {
+	case rec.Song != nil:
+		if _, err := hex.DecodeString(rec.Song.FileSHA1); err != nil || len(rec.Song.FileSHA1) != 40 {
+			return nil, nil, fmt.Errorf("%w: song %q has an invalid file_sha1 %q", ErrInvalid, rec.Song.Name, rec.Song.FileSHA1)
+		}
+		r.summary.Songs++
+		r.song = true
+		return rec.Song, nil, nil
+	case len(rec.Fingerprints) > 0:
+		if !r.song {
+			return nil, nil, fmt.Errorf("%w: fingerprints before any song", ErrInvalid)
+		}
+		r.summary.Fingerprints += len(rec.Fingerprints)
+		return nil, rec.Fingerprints, nil
+	case rec.End != nil:
+		if *rec.End != r.summary {
+			return nil, nil, fmt.Errorf("%w: trailer counts %d songs and %d fingerprints, read %d and %d",
+				ErrInvalid, rec.End.Songs, rec.End.Fingerprints, r.summary.Songs, r.summary.Fingerprints)
+		}
+		r.done = true
+		return nil, nil, io.EOF
+	default:
+		return nil, nil, fmt.Errorf("%w: empty record", ErrInvalid)
+	}
+}
+
+// Summary counts the records read so far
+func (r *Reader) Summary() Summary {
+	return r.summary
+}
@@ -0,0 +1,164 @@
+package dump
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readAll reads every record of a dump, grouping the fingerprints under their song
+func readAll(r io.Reader) (Header, []Song, [][]Fingerprint, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return Header{}, nil, nil, err
+	}
+	var songs []Song
+	var fingerprints [][]Fingerprint
+	for {
+		song, fps, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return reader.Header(), songs, fingerprints, nil
+		}
+		if err != nil {
+			return Header{}, nil, nil, err
+		}
+		if song != nil {
+			songs = append(songs, *song)
+			fingerprints = append(fingerprints, nil)
+			continue
+		}
+		fingerprints[len(fingerprints)-1] = append(fingerprints[len(fingerprints)-1], fps...)
+	}
+}
+
+// encodeRecords writes records as a dump without the checks of Writer
+func encodeRecords(t *testing.T, records ...record) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, rec := range records {
+		if err := encoder.Encode(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	songs := []Song{
+		{Name: "First", Artist: "Artist", Album: "Album", FileSHA1: strings.Repeat("a", 40), TotalHashes: chunkSize + 5},
+		{Name: "Second", FileSHA1: strings.Repeat("b", 40), TotalHashes: 2},
+	}
+	fingerprints := make([][]Fingerprint, len(songs))
+	for i, song := range songs {
+		for j := 0; j < song.TotalHashes; j++ {
+			fingerprints[i] = append(fingerprints[i], Fingerprint{Hash: strings.Repeat("0", 19) + string(rune('a'+i)), Offset: j * 10})
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, song := range songs {
+		if err := w.WriteSong(song); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteFingerprints(fingerprints[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	summary, err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Summary{Songs: 2, Fingerprints: chunkSize + 7}); summary != want {
+		t.Fatalf("summary = %+v, want %+v", summary, want)
+	}
+
+	header, gotSongs, gotFingerprints, err := readAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Format != Format || header.Version != Version || header.AlgorithmVersion != 3 {
+		t.Fatalf("header = %+v", header)
+	}
+	if !reflect.DeepEqual(gotSongs, songs) {
+		t.Fatalf("songs = %+v, want %+v", gotSongs, songs)
+	}
+	if !reflect.DeepEqual(gotFingerprints, fingerprints) {
+		t.Fatal("fingerprints differ from the written ones")
+	}
+}
+
+func TestWriteFingerprintsBeforeSong(t *testing.T) {
+	w, err := NewWriter(io.Discard, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFingerprints([]Fingerprint{{Hash: "a", Offset: 1}}); err == nil {
+		t.Fatal("WriteFingerprints succeeded before any song")
+	}
+}
+
+func TestInvalidDumps(t *testing.T) {
+	header := &Header{Format: Format, Version: Version, AlgorithmVersion: 1}
+	song := &Song{Name: "Song", FileSHA1: strings.Repeat("c", 40), TotalHashes: 1}
+	fingerprints := []Fingerprint{{Hash: "0123456789abcdef0123", Offset: 100}}
+
+	var complete bytes.Buffer
+	w, err := NewWriter(&complete, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteSong(*song); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFingerprints(fingerprints); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"not gzip", []byte("song,artist\n"), "invalid dump"},
+		{"truncated compressed stream", complete.Bytes()[:complete.Len()/2], "invalid dump"},
+		{"missing trailer", encodeRecords(t, record{Header: header}, record{Song: song}, record{Fingerprints: fingerprints}), "truncated after 1 songs"},
+		{"missing header", encodeRecords(t, record{Song: song}), "missing header"},
+		{"unknown format", encodeRecords(t, record{Header: &Header{Format: "other", Version: 1}}), `unknown format "other"`},
+		{"newer version", encodeRecords(t, record{Header: &Header{Format: Format, Version: Version + 1}}), "unsupported version"},
+		{
+			"tampered trailer",
+			encodeRecords(t, record{Header: header}, record{Song: song}, record{Fingerprints: fingerprints}, record{End: &Summary{Songs: 1, Fingerprints: 2}}),
+			"trailer counts 1 songs and 2 fingerprints, read 1 and 1",
+		},
+		{"fingerprints before any song", encodeRecords(t, record{Header: header}, record{Fingerprints: fingerprints}), "fingerprints before any song"},
+		{"invalid file hash", encodeRecords(t, record{Header: header}, record{Song: &Song{Name: "Song", FileSHA1: "xyz"}}), "invalid file_sha1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := readAll(bytes.NewReader(tt.data))
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("error = %v, want ErrInvalid", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package eureka
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cueFramesPerSecond is the number of frames per second used by INDEX entries in .cue files
+const cueFramesPerSecond = 75
+
+// TimelineFormats lists the formats accepted by WriteTimeline, the OutputFormats and cue
+var TimelineFormats = []string{"table", "json", "csv", "cue"}
+
+// WriteTimeline writes segments to w in one of the TimelineFormats. audioPath is only
+// used by the cue format to reference the analyzed recording.
+func WriteTimeline(w io.Writer, format string, audioPath string, segments []Segment) error {
+	switch strings.ToLower(format) {
+	case "table":
+		rows := make([][]string, len(segments))
+		for i, s := range segments {
+			rows[i] = []string{strconv.FormatFloat(s.Start, 'f', 1, 64), strconv.FormatFloat(s.End, 'f', 1, 64),
+				strconv.Itoa(s.SongID), s.SongName, s.Artist, strconv.Itoa(s.ReferenceOffset), strconv.FormatFloat(s.Confidence, 'f', 3, 64)}
+		}
+		return writeTable(w, []string{"START", "END", "SONG_ID", "SONG", "ARTIST", "REFERENCE_OFFSET_MS", "CONFIDENCE"}, rows)
+	case "json":
+		return WriteTimelineJSON(w, segments)
+	case "csv":
+		return WriteTimelineCSV(w, segments)
+	case "cue":
+		return WriteCueSheet(w, audioPath, segments)
+	default:
+		return fmt.Errorf("unsupported output format: %s, expected %s", format, strings.Join(TimelineFormats, ", "))
+	}
+}
+
+// WriteTimelineJSON writes segments as a JSON array
+func WriteTimelineJSON(w io.Writer, segments []Segment) error {
+	if segments == nil {
+		segments = []Segment{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(segments)
+}
+
+// WriteTimelineCSV writes segments as CSV with a header row
+func WriteTimelineCSV(w io.Writer, segments []Segment) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"start", "end", "song_id", "song", "artist", "reference_offset_ms", "confidence"}); err != nil {
+		return err
+	}
+
+	for _, s := range segments {
+		record := []string{
+			strconv.FormatFloat(s.Start, 'f', 3, 64),
+			strconv.FormatFloat(s.End, 'f', 3, 64),
+			strconv.Itoa(s.SongID),
+			s.SongName,
+			s.Artist,
+			strconv.Itoa(s.ReferenceOffset),
+			strconv.FormatFloat(s.Confidence, 'f', 3, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCueSheet writes segments as a .cue sheet referencing audioPath.
+// Fields that have no cue equivalent are stored as REM comments.
+func WriteCueSheet(w io.Writer, audioPath string, segments []Segment) error {
+	fileName := filepath.Base(audioPath)
+	fileType := "WAVE"
+	if strings.EqualFold(filepath.Ext(fileName), ".mp3") {
+		fileType = "MP3"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "TITLE %s\n", cueQuote(strings.TrimSuffix(fileName, filepath.Ext(fileName))))
+	fmt.Fprintf(&b, "FILE %s %s\n", cueQuote(fileName), fileType)
+
+	for i, s := range segments {
+		fmt.Fprintf(&b, "  TRACK %02d AUDIO\n", i+1)
+		fmt.Fprintf(&b, "    TITLE %s\n", cueQuote(s.SongName))
+		if s.Artist != "" {
+			fmt.Fprintf(&b, "    PERFORMER %s\n", cueQuote(s.Artist))
+		}
+		fmt.Fprintf(&b, "    REM EUREKA_SONG_ID %d\n", s.SongID)
+		fmt.Fprintf(&b, "    REM EUREKA_END %s\n", cueTimestamp(s.End))
+		fmt.Fprintf(&b, "    REM EUREKA_REFERENCE_OFFSET_MS %d\n", s.ReferenceOffset)
+		fmt.Fprintf(&b, "    REM EUREKA_CONFIDENCE %.3f\n", s.Confidence)
+		fmt.Fprintf(&b, "    INDEX 01 %s\n", cueTimestamp(s.Start))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cueTimestamp formats seconds as the mm:ss:ff form used by .cue sheets
+func cueTimestamp(seconds float64) string {
+	totalFrames := int(seconds*cueFramesPerSecond + 0.5)
+	minutes := totalFrames / (60 * cueFramesPerSecond)
+	secs := (totalFrames / cueFramesPerSecond) % 60
+	frames := totalFrames % cueFramesPerSecond
+	return fmt.Sprintf("%02d:%02d:%02d", minutes, secs, frames)
+}
+
+// cueQuote quotes a string for a .cue sheet, which has no escape sequences
+func cueQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
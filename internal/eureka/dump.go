@@ -0,0 +1,175 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/dump"
+	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// fingerprintScanner is implemented by the databases able to export fingerprints
+type fingerprintScanner interface {
+	ScanFingerprints(ctx context.Context, songID int, fn func(hash string, offset int) error) error
+}
+
+// exportBatchSize is the number of fingerprints buffered before they are written
+const exportBatchSize = 10000
+
+// ImportResult summarizes an imported dump
+type ImportResult struct {
+	Songs        int   `json:"songs"`        // Songs stored
+	Skipped      int   `json:"skipped"`      // Songs already fingerprinted in the database
+	Fingerprints int   `json:"fingerprints"` // Fingerprints stored
+	DurationMs   int64 `json:"duration_ms"`
+}
+
+// Export writes the fingerprinted songs and their fingerprints to w as a dump, see the
+// dump package for the format. Songs whose fingerprinting did not complete are left out.
+func (e *Eureka) Export(ctx context.Context, w io.Writer) (dump.Summary, error) {
+	scanner, ok := e.database.(fingerprintScanner)
+	if !ok {
+		return dump.Summary{}, fmt.Errorf("database type does not support exporting fingerprints: %w", errors.ErrUnsupported)
+	}
+	fingerprinted := true
+	page, err := e.List(ctx, catalog.SongQuery{Fingerprinted: &fingerprinted})
+	if err != nil {
+		return dump.Summary{}, err
+	}
+
+	writer, err := dump.NewWriter(w, fingerprint.ALGORITHM_VERSION)
+	if err != nil {
+		return dump.Summary{}, err
+	}
+
+	batch := make([]dump.Fingerprint, 0, exportBatchSize)
+	for _, song := range page.Songs {
+		err := writer.WriteSong(dump.Song{
+			Name:        song.Name,
+			Artist:      song.Artist,
+			Album:       song.Album,
+			FileSHA1:    strings.ToLower(song.FileSHA1),
+			TotalHashes: song.TotalHashes,
+		})
+		if err != nil {
+			return dump.Summary{}, err
+		}
+
+		batch = batch[:0]
+		err = scanner.ScanFingerprints(ctx, song.ID, func(hash string, offset int) error {
+			batch = append(batch, dump.Fingerprint{Hash: hash, Offset: offset})
+			if len(batch) < exportBatchSize {
+				return nil
+			}
+			err := writer.WriteFingerprints(batch)
+			batch = batch[:0]
+			return err
+		})
+		if err != nil {
+			return dump.Summary{}, fmt.Errorf("error exporting song %d: %v", song.ID, err)
+		}
+		if err := writer.WriteFingerprints(batch); err != nil {
+			return dump.Summary{}, err
+		}
+		logger.Debug("Exported song", "song_id", song.ID, "song", song.Name)
+	}
+
+	return writer.Close()
+}
+
+// Import stores the songs of a dump read from r. Songs are matched by the SHA1 of their
+// audio file: songs already fingerprinted are skipped, and a song left incomplete by an
+// interrupted import is completed, so importing the same dump again is harmless. Dumps
+// of hashes from another fingerprint algorithm version are refused.
+func (e *Eureka) Import(ctx context.Context, r io.Reader) (ImportResult, error) {
+	start := time.Now()
+	reader, err := dump.NewReader(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if v := reader.Header().AlgorithmVersion; v != fingerprint.ALGORITHM_VERSION {
+		return ImportResult{}, fmt.Errorf("dump hashes were generated by fingerprint algorithm version %d, this release uses version %d", v, fingerprint.ALGORITHM_VERSION)
+	}
+
+	// Without a song list every song is inserted, InsertSong and InsertFingerprints
+	// ignore duplicates
+	fingerprinted := make(map[string]bool)
+	page, err := e.List(ctx, catalog.SongQuery{})
+	if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+		return ImportResult{}, err
+	}
+	for _, song := range page.Songs {
+		fingerprinted[strings.ToLower(song.FileSHA1)] = song.Fingerprinted
+	}
+
+	var result ImportResult
+	songID, skip := 0, true
+
+	// finish marks the current song as fingerprinted once all its fingerprints are stored
+	finish := func() error {
+		if skip {
+			return nil
+		}
+		if err := e.database.UpdateSongFingerprinted(ctx, songID); err != nil {
+			return fmt.Errorf("error marking song as fingerprinted: %v", err)
+		}
+		return nil
+	}
+
+	for {
+		song, fingerprints, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		if song != nil {
+			if err := finish(); err != nil {
+				return result, err
+			}
+			skip = fingerprinted[strings.ToLower(song.FileSHA1)]
+			if skip {
+				result.Skipped++
+				logger.Debug("Skipped song already in the database", "song", song.Name, "file_sha1", song.FileSHA1)
+				continue
+			}
+			songID, err = e.database.InsertSong(ctx, song.Name, song.Artist, song.FileSHA1, song.TotalHashes)
+			if err != nil {
+				return result, fmt.Errorf("error inserting song: %v", err)
+			}
+			if song.Album != "" {
+				err := e.database.UpdateSong(ctx, songID, mysql.SongUpdate{Album: &song.Album})
+				if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+					return result, fmt.Errorf("error storing album: %v", err)
+				}
+			}
+			result.Songs++
+			continue
+		}
+
+		if skip {
+			continue
+		}
+		for _, fp := range fingerprints {
+			if err := e.database.InsertFingerprints(ctx, fp.Hash, songID, fp.Offset); err != nil {
+				return result, fmt.Errorf("error inserting fingerprint: %v", err)
+			}
+			result.Fingerprints++
+		}
+	}
+
+	if err := finish(); err != nil {
+		return result, err
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package eureka
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/dump"
+	"github.com/media-luna/eureka/internal/fingerprint"
+)
+
+// importDatabase stores the songs and fingerprints written by Import in memory.
+// Methods not needed by Import panic through the nil embedded Database.
+type importDatabase struct {
+	database.Database
+	songs        []mysql.Song
+	fingerprints map[mysql.FingerprintMatch]bool
+}
+
+func (d *importDatabase) ListSongs(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error) {
+	return mysql.SongPage{Songs: d.songs, Total: len(d.songs)}, nil
+}
+
+func (d *importDatabase) InsertSong(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int, error) {
+	d.songs = append(d.songs, mysql.Song{ID: len(d.songs) + 1, Name: songName, Artist: artistName, FileSHA1: fileHash, TotalHashes: totalHashes})
+	return len(d.songs), nil
+}
+
+func (d *importDatabase) UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) error {
+	d.songs[songID-1].Album = *update.Album
+	return nil
+}
+
+func (d *importDatabase) InsertFingerprints(ctx context.Context, hash string, songID int, offset int) error {
+	if d.fingerprints == nil {
+		d.fingerprints = make(map[mysql.FingerprintMatch]bool)
+	}
+	d.fingerprints[mysql.FingerprintMatch{Hash: hash, SongID: songID, Offset: offset}] = true
+	return nil
+}
+
+func (d *importDatabase) UpdateSongFingerprinted(ctx context.Context, songID int) error {
+	d.songs[songID-1].Fingerprinted = true
+	return nil
+}
+
+func TestImportTwice(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := dump.NewWriter(&buf, fingerprint.ALGORITHM_VERSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, song := range []dump.Song{
+		{Name: "First", Artist: "Artist", Album: "Album", FileSHA1: strings.Repeat("A", 40), TotalHashes: 2},
+		{Name: "Second", FileSHA1: strings.Repeat("b", 40), TotalHashes: 1},
+	} {
+		if err := w.WriteSong(song); err != nil {
+			t.Fatal(err)
+		}
+		fingerprints := []dump.Fingerprint{{Hash: "0123456789abcdef0123", Offset: 100 * i}, {Hash: "3210fedcba9876543210", Offset: 50}}
+		if err := w.WriteFingerprints(fingerprints[:song.TotalHashes]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := &importDatabase{}
+	e := NewEurekaWithDatabase(config.Default(), db)
+	first, err := e.Import(context.Background(), bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Songs != 2 || first.Skipped != 0 || first.Fingerprints != 3 {
+		t.Fatalf("first import = %+v, want 2 songs and 3 fingerprints", first)
+	}
+	if song := db.songs[0]; !song.Fingerprinted || song.Album != "Album" || song.FileSHA1 != strings.Repeat("A", 40) {
+		t.Fatalf("first song = %+v", song)
+	}
+
+	// Songs are matched by file hash whatever its case, nothing is stored twice
+	second, err := e.Import(context.Background(), bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Songs != 0 || second.Skipped != 2 || second.Fingerprints != 0 {
+		t.Fatalf("second import = %+v, want 2 skipped songs", second)
+	}
+	if len(db.songs) != 2 || len(db.fingerprints) != 3 {
+		t.Fatalf("%d songs and %d fingerprints stored, want 2 and 3", len(db.songs), len(db.fingerprints))
+	}
+}
@@ -1,172 +1,362 @@
 package eureka
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	config "github.com/media-luna/eureka/configs"
 	"github.com/media-luna/eureka/internal/database"
+	"github.com/media-luna/eureka/internal/database/catalog"
 	"github.com/media-luna/eureka/internal/database/mysql"
 	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+	"github.com/media-luna/eureka/internal/metrics"
 	"github.com/media-luna/eureka/utils/logger"
 	"github.com/schollz/progressbar/v3"
 )
 
+// ErrInvalidAudio is wrapped by errors caused by input audio that cannot be decoded
+var ErrInvalidAudio = errors.New("invalid audio")
+
+// ErrInvalidMetadata is wrapped by errors caused by song metadata that cannot be stored
+var ErrInvalidMetadata = errors.New("invalid song metadata")
+
+// maxMetadataLength is the size of the name, artist and album columns in characters
+const maxMetadataLength = 250
+
 // Eureka represents the main structure for the Eureka service,
 // containing the configuration settings required for its operation.
 type Eureka struct {
 	Config   config.Config
 	database database.Database
+
+	stopMu   sync.RWMutex
+	stopList map[string]struct{} // Stop-listed hashes, see BuildStopList
+
+	history historyState
 }
 
 // NewEureka initializes a new Eureka instance with the provided configuration.
 // It performs the following steps:
-// 1. Initializes the database object using the provided configuration.
-// 2. Connects to the database.
-// 3. Sets up the database.
+// 1. Validates the configuration, reporting every invalid setting.
+// 2. Initializes the database object using the provided configuration.
+// 3. Connects to the database.
+// 4. Refuses a database schema migrated by a newer release (migrate.ErrNewerSchema).
+// 5. Sets up the database, applying pending schema migrations.
+// 6. Loads the hash stop-list.
 //
-// If any of these steps fail, it logs the error and returns nil.
+// If any of these steps fail, it returns the error.
 //
 // Parameters:
+//   - ctx: Bounds connecting to and setting up the database.
 //   - config: The configuration object used to initialize the database.
 //
 // Returns:
 //   - A pointer to the initialized Eureka instance, or nil if an error occurred.
-func NewEureka(config config.Config) (*Eureka, error) {
+func NewEureka(ctx context.Context, config config.Config) (*Eureka, error) {
 	// audioDownloader , err := downloader.NewAudioDownloader("https://www.youtube.com/watch?v=s8QYxmpuyxg")
 	// println(audioDownloader.GetTrack())
 
 	// TODO: Load all fingerprinted songs and their hashes to memory
 	// if possible to make the process a bit faster
 
+	// Report every invalid setting before opening any connection
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Init DB object
-	db, err := database.NewDatabase(config)
+	db, err := database.NewDatabase(ctx, config)
 	if err != nil {
 		return nil, err
 	}
 
-	// Setup DB
-	if err := db.Setup(); err != nil {
+	// Refuse a schema migrated by a newer release, whose tables this one may misread
+	if err := database.CheckSchema(ctx, db); err != nil {
+		db.Close()
 		return nil, err
 	}
 
+	// Setup DB, applying pending migrations
+	if err := db.Setup(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	e := NewEurekaWithDatabase(config, db)
+	if err := e.LoadStopList(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewEurekaWithDatabase creates a Eureka instance on an already connected and set up
+// database, so that any database.Database implementation can back it (embedding,
+// local development against another backend). Call LoadStopList to use the stop-list
+// of the database.
+func NewEurekaWithDatabase(config config.Config, db database.Database) *Eureka {
 	return &Eureka{
 		Config:   config,
 		database: db,
-	}, nil
+	}
 }
 
 // Save processes an audio file, generates its spectrogram, and extracts fingerprints.
-func (e *Eureka) Save(path string) error {
+// The artist and title are taken from a file named "Artist--Title.ext".
+// Cancelling ctx aborts processing and any pending database operation.
+func (e *Eureka) Save(ctx context.Context, path string) (SaveResult, error) {
 	// Check if path is dir or file
 	info, err := os.Stat(path)
 	if err != nil {
-		return fmt.Errorf("error stating path: %v", err)
+		return SaveResult{}, fmt.Errorf("error stating path: %v", err)
 	}
 
 	if info.IsDir() {
-		return fmt.Errorf("path is a directory not supported, expected a file")
+		return SaveResult{}, fmt.Errorf("path is a directory not supported, expected a file")
+	}
+
+	logger.Info("Processing audio file", "file", filepath.Base(path))
+
+	artistName, songName := ParseSongFilename(path)
+	return e.save(ctx, path, songName, artistName, saveOptions{
+		wavPath:         "output.wav",
+		spectrogramPath: "spectrogram.png",
+		progress:        true,
+	})
+}
+
+// SaveSong fingerprints the audio file at path and stores it with the given metadata,
+// returning the song ID. Unlike Save it writes no files to the working directory, so
+// it is safe to call concurrently.
+func (e *Eureka) SaveSong(ctx context.Context, path string, title string, artist string) (int, error) {
+	result, err := e.Ingest(ctx, path, title, artist, false)
+	return result.SongID, err
+}
+
+// Ingest is SaveSong returning the full summary, with an optional progress bar on stderr
+func (e *Eureka) Ingest(ctx context.Context, path string, title string, artist string, progress bool) (SaveResult, error) {
+	wavFile, err := os.CreateTemp("", "eureka-*.wav")
+	if err != nil {
+		return SaveResult{}, fmt.Errorf("error creating temporary file: %v", err)
 	}
+	wavFile.Close()
+	defer os.Remove(wavFile.Name())
 
-	logger.Info(fmt.Sprintf("Processing audio file: %s", filepath.Base(path)))
+	return e.save(ctx, path, title, artist, saveOptions{wavPath: wavFile.Name(), progress: progress})
+}
+
+// SaveResult summarizes a stored song
+type SaveResult struct {
+	SongID       int    `json:"song_id"`
+	Song         string `json:"song"`
+	Artist       string `json:"artist"`
+	File         string `json:"file"`
+	Fingerprints int    `json:"fingerprints"`
+	DurationMs   int64  `json:"duration_ms"`
+}
+
+// saveOptions controls the side outputs of save
+type saveOptions struct {
+	wavPath         string // Where the converted WAV is written
+	spectrogramPath string // Spectrogram image with peaks, skipped when empty
+	progress        bool   // Show a progress bar while storing fingerprints
+}
+
+// save fingerprints an audio file and stores it in the database, counting it in the
+// ingest metrics
+func (e *Eureka) save(ctx context.Context, path string, songName string, artistName string, opts saveOptions) (SaveResult, error) {
+	result, err := e.saveSong(ctx, path, songName, artistName, opts)
+	if err != nil {
+		metrics.IngestFailures.Inc()
+		return SaveResult{}, err
+	}
+	metrics.SongsIngested.Inc()
+	metrics.FingerprintsIngested.Add(float64(result.Fingerprints))
+	return result, nil
+}
+
+// saveSong does the work of save
+func (e *Eureka) saveSong(ctx context.Context, path string, songName string, artistName string, opts saveOptions) (SaveResult, error) {
+	start := time.Now()
+	log := logger.Logger().With("file", filepath.Base(path))
 
 	// Convert any file type to WAV
-	filePath, err := fingerprint.ConvertToWAV(path, "output.wav")
+	filePath, err := fingerprint.ConvertToWAV(path, opts.wavPath)
 	if err != nil {
-		return fmt.Errorf("error converting to WAV: %v", err)
+		return SaveResult{}, fmt.Errorf("%w: error converting to WAV: %v", ErrInvalidAudio, err)
 	}
-	logger.Info("Audio file converted to WAV format")
+	log.Debug("Audio file converted to WAV format", "wav", filePath)
 
 	// Read wav info
 	wavInfo, err := fingerprint.ReadWavInfo(filePath)
 	if err != nil {
-		return fmt.Errorf("error reading WAV info: %v", err)
+		return SaveResult{}, fmt.Errorf("%w: error reading WAV info: %v", ErrInvalidAudio, err)
 	}
 
-	logger.Info("Generating spectrogram...")
+	log.Debug("Generating spectrogram")
 	// Generate spectrogram
 	spectrogram, err := fingerprint.SamplesToSpectrogram(wavInfo.Samples, wavInfo.SampleRate)
 	if err != nil {
-		return fmt.Errorf("error creating spectrogram: %v", err)
+		return SaveResult{}, fmt.Errorf("error creating spectrogram: %v", err)
 	}
 
 	// Collect spectrogram peaks
 	peaks := fingerprint.PickPeaks(spectrogram, wavInfo.SampleRate)
-	logger.Info(fmt.Sprintf("Found %d peaks in spectrogram", len(peaks)))
+	log.Debug("Found peaks in spectrogram", "peaks", len(peaks))
 
 	// Save spectrogram image with peaks
-	if err := fingerprint.SpectrogramToImage(spectrogram, peaks, wavInfo.SampleRate, "spectrogram.png"); err != nil {
-		return fmt.Errorf("error saving spectrogram image: %v", err)
+	if opts.spectrogramPath != "" {
+		if err := fingerprint.SpectrogramToImage(spectrogram, peaks, wavInfo.SampleRate, opts.spectrogramPath); err != nil {
+			return SaveResult{}, fmt.Errorf("error saving spectrogram image: %v", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return SaveResult{}, err
 	}
 
 	// Generate fingerprints
-	logger.Info("Generating fingerprints...")
 	fingerprints := fingerprint.GenerateFingerprints(peaks)
-	logger.Info(fmt.Sprintf("Generated %d fingerprints", len(fingerprints)))
+	log.Debug("Generated fingerprints", "fingerprints", len(fingerprints))
 
 	// Calculate file hash
 	fileHash := fingerprint.CalculateFileHash(path)
 
-	// Extract song name and artist from filepath
+	// Store song in database
+	songID, err := e.database.InsertSong(ctx, songName, artistName, fileHash, len(fingerprints))
+	if err != nil {
+		return SaveResult{}, fmt.Errorf("error inserting song: %v", err)
+	}
+
+	// Store fingerprints, with a progress bar on stderr unless logs are quiet or JSON
+	log = log.With("song_id", songID)
+	log.Info("Storing fingerprints in database", "fingerprints", len(fingerprints))
+	var bar *progressbar.ProgressBar
+	if opts.progress && logger.ShowProgress() {
+		bar = progressbar.Default(int64(len(fingerprints)))
+	}
+	for _, fp := range fingerprints {
+		if err := e.database.InsertFingerprints(ctx, fp.Hash, songID, fp.Offset); err != nil {
+			return SaveResult{}, fmt.Errorf("error inserting fingerprint: %v", err)
+		}
+		if bar != nil {
+			bar.Add(1)
+		}
+	}
+
+	// Mark song as fingerprinted only after all fingerprints are stored
+	if err := e.database.UpdateSongFingerprinted(ctx, songID); err != nil {
+		return SaveResult{}, fmt.Errorf("error marking song as fingerprinted: %v", err)
+	}
+	log.Info("Song processed", "song", songName, "artist", artistName, "duration_ms", time.Since(start).Milliseconds())
+
+	return SaveResult{
+		SongID:       songID,
+		Song:         songName,
+		Artist:       artistName,
+		File:         path,
+		Fingerprints: len(fingerprints),
+		DurationMs:   time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// ParseSongFilename extracts the artist and title from a file named "Artist--Title.ext".
+// Without the separator the whole name is the title and the artist is empty.
+func ParseSongFilename(path string) (artist string, title string) {
 	fileName := filepath.Base(path)
 	parts := strings.Split(fileName, "--")
-	var artistName, songName string
 
 	if len(parts) >= 2 {
-		artistName = strings.TrimSpace(parts[0])
-		songName = strings.TrimSpace(strings.Join(parts[1:], "-"))
+		artist = strings.TrimSpace(parts[0])
+		title = strings.TrimSpace(strings.Join(parts[1:], "-"))
 		// Remove file extension from song name
-		songName = strings.TrimSuffix(songName, filepath.Ext(songName))
+		title = strings.TrimSuffix(title, filepath.Ext(title))
 	} else {
 		// If no artist separator found, use whole name as song name
-		songName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
-		artistName = "" // Empty artist name
+		title = strings.TrimSuffix(fileName, filepath.Ext(fileName))
 	}
 
-	// Store song in database
-	songID, err := e.database.InsertSong(songName, artistName, fileHash, len(fingerprints))
-	if err != nil {
-		return fmt.Errorf("error inserting song: %v", err)
-	}
+	return artist, title
+}
 
-	// Store fingerprints with progress bar
-	logger.Info("Storing fingerprints in database...")
-	bar := progressbar.Default(int64(len(fingerprints)))
-	for _, fp := range fingerprints {
-		if err := e.database.InsertFingerprints(fp.Hash, songID, fp.Offset); err != nil {
-			return fmt.Errorf("error inserting fingerprint: %v", err)
-		}
-		bar.Add(1)
-	}
+// Close releases the database connection pool
+func (e *Eureka) Close() error {
+	return e.database.Close()
+}
 
-	// Mark song as fingerprinted only after all fingerprints are stored
-	if err := e.database.UpdateSongFingerprinted(songID); err != nil {
-		return fmt.Errorf("error marking song as fingerprinted: %v", err)
-	}
-	logger.Info(fmt.Sprintf("Successfully processed %s", songName))
+// List returns the songs selected by query with the number of songs matching its
+// filters, the zero query lists every song. The error wraps catalog.ErrInvalidQuery for
+// an invalid query.
+func (e *Eureka) List(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error) {
+	return e.database.ListSongs(ctx, query)
+}
 
-	return nil
+// statsReporter is implemented by the databases able to compute catalog statistics
+type statsReporter interface {
+	Stats(ctx context.Context, topHashes int) (catalog.Stats, error)
 }
 
-// List returns all songs from the database
-func (e *Eureka) List() ([]mysql.Song, error) {
-	if db, ok := e.database.(*mysql.DB); ok {
-		return db.ListSongs()
+// Stats returns statistics of the stored catalog with the topHashes hashes shared by
+// the most songs, to tune fan_value and the peak density. It scans every fingerprint.
+func (e *Eureka) Stats(ctx context.Context, topHashes int) (catalog.Stats, error) {
+	db, ok := e.database.(statsReporter)
+	if !ok {
+		return catalog.Stats{}, fmt.Errorf("database type does not support statistics: %w", errors.ErrUnsupported)
 	}
-	return nil, fmt.Errorf("database type does not support listing songs")
+	return db.Stats(ctx, topHashes)
 }
 
 // Cleanup performs general database cleanup operations
-func (e *Eureka) Cleanup() error {
-	if db, ok := e.database.(*mysql.DB); ok {
-		return db.Cleanup()
+func (e *Eureka) Cleanup(ctx context.Context) error {
+	return e.database.Cleanup(ctx)
+}
+
+// GetSong returns a song by ID, the error wraps mysql.ErrNotFound if it does not exist
+func (e *Eureka) GetSong(ctx context.Context, songID int) (mysql.SongInfo, error) {
+	return e.database.GetSongByID(ctx, songID)
+}
+
+// UpdateSong changes the metadata of a song and returns the updated song. Fields of
+// update left nil are unchanged, the others are trimmed and the name cannot be empty.
+// The fingerprints are not touched. The error wraps mysql.ErrNotFound if the song
+// does not exist.
+func (e *Eureka) UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) (mysql.SongInfo, error) {
+	for _, field := range []struct {
+		name  string
+		value **string
+	}{{"name", &update.Name}, {"artist", &update.Artist}, {"album", &update.Album}} {
+		if *field.value == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(**field.value)
+		if utf8.RuneCountInString(trimmed) > maxMetadataLength {
+			return mysql.SongInfo{}, fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidMetadata, field.name, maxMetadataLength)
+		}
+		*field.value = &trimmed
+	}
+	if update.Name != nil && *update.Name == "" {
+		return mysql.SongInfo{}, fmt.Errorf("%w: name cannot be empty", ErrInvalidMetadata)
 	}
-	return fmt.Errorf("database type does not support cleanup")
+
+	if err := e.database.UpdateSong(ctx, songID, update); err != nil {
+		return mysql.SongInfo{}, err
+	}
+	return e.database.GetSongByID(ctx, songID)
 }
 
 // Delete deletes a song and its fingerprints from the database
-func (e *Eureka) Delete(songID int) error {
-	return e.database.DeleteSong(songID)
+func (e *Eureka) Delete(ctx context.Context, songID int) error {
+	return e.database.DeleteSong(ctx, songID)
+}
+
+// RecordPlay stores a song airing detected by the broadcast monitor
+func (e *Eureka) RecordPlay(ctx context.Context, play mysql.Play) error {
+	return e.database.InsertPlay(ctx, play)
 }
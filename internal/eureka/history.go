@@ -0,0 +1,162 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/metrics"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// Recognition sources stored in the history, live sessions are also counted by
+// source in the eureka_stream_sessions_total metric
+const (
+	SourceFile = "file" // One-shot recognition from the CLI or the library
+	SourceMic  = "mic"  // Live session from the CLI or the library
+	SourceAPI  = "api"  // Any recognition requested over HTTP or gRPC
+)
+
+// Sources lists the recognition sources, in the order of the constants
+var Sources = []string{SourceFile, SourceMic, SourceAPI}
+
+func init() {
+	// Export every live session series from the start, so that ratios are defined
+	// before the first session
+	for _, source := range []string{SourceMic, SourceAPI} {
+		for _, outcome := range []string{metrics.ResultMatch, metrics.ResultNoMatch} {
+			metrics.StreamSessions.WithLabelValues(source, outcome)
+		}
+	}
+}
+
+const (
+	// maxClientIDLength is the size of the client_id column in characters
+	maxClientIDLength = 250
+	// historyTimeout bounds recording a recognition, which can outlive the request
+	historyTimeout = 5 * time.Second
+	// purgeInterval is how often expired recognitions are deleted
+	purgeInterval = time.Hour
+)
+
+// historyStore is implemented by the databases able to store the recognition history
+type historyStore interface {
+	InsertRecognition(ctx context.Context, recognition mysql.Recognition) error
+	ListRecognitions(ctx context.Context, query mysql.RecognitionQuery) (mysql.RecognitionPage, error)
+	DeleteRecognitionsBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+// historyState tracks the retention purges of a Eureka instance
+type historyState struct {
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+type clientKey struct{}
+
+// WithClient returns a context recording the recognitions made with it as requested
+// by an API client, identified by clientID
+func WithClient(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientKey{}, clientID)
+}
+
+// clientFrom returns the API client set by WithClient and whether there is one
+func clientFrom(ctx context.Context) (string, bool) {
+	clientID, ok := ctx.Value(clientKey{}).(string)
+	return clientID, ok
+}
+
+// History returns the recorded recognitions selected by query, newest first
+func (e *Eureka) History(ctx context.Context, query mysql.RecognitionQuery) (mysql.RecognitionPage, error) {
+	db, ok := e.database.(historyStore)
+	if !ok {
+		return mysql.RecognitionPage{}, fmt.Errorf("database type does not support a recognition history: %w", errors.ErrUnsupported)
+	}
+	return db.ListRecognitions(ctx, query)
+}
+
+// PurgeHistory deletes the recognitions older than history.retention_days and returns
+// their number, nothing when the retention is 0
+func (e *Eureka) PurgeHistory(ctx context.Context) (int64, error) {
+	db, ok := e.database.(historyStore)
+	if !ok {
+		return 0, fmt.Errorf("database type does not support a recognition history: %w", errors.ErrUnsupported)
+	}
+	if e.Config.History.RetentionDays == 0 {
+		return 0, nil
+	}
+
+	deleted, err := db.DeleteRecognitionsBefore(ctx, time.Now().AddDate(0, 0, -e.Config.History.RetentionDays))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		logger.Info("Purged recognition history", "recognitions", deleted, "retention_days", e.Config.History.RetentionDays)
+	}
+	return deleted, nil
+}
+
+// record stores a finished recognition in the history. Failing to record never fails
+// the recognition, it is logged instead.
+func (e *Eureka) record(ctx context.Context, source string, start time.Time, latency time.Duration, audioSeconds float64, matches []Match) {
+	db, ok := e.database.(historyStore)
+	if !ok || !e.Config.History.Enabled {
+		return
+	}
+
+	clientID, api := clientFrom(ctx)
+	if api {
+		source = SourceAPI
+	}
+	if runes := []rune(clientID); len(runes) > maxClientIDLength {
+		clientID = string(runes[:maxClientIDLength])
+	}
+
+	recognition := mysql.Recognition{
+		RecognizedAt: start,
+		Source:       source,
+		ClientID:     clientID,
+		AudioMs:      int64(audioSeconds * 1000),
+		LatencyMs:    latency.Milliseconds(),
+		Matches:      make([]mysql.RecognitionMatch, 0, len(matches)),
+	}
+	for _, m := range matches {
+		recognition.Matches = append(recognition.Matches, mysql.RecognitionMatch{
+			SongID: m.SongID,
+			Song:   m.SongName,
+			Artist: m.Artist,
+			Score:  m.Score,
+			Offset: m.Offset,
+		})
+	}
+
+	// The request context may already be done, a cancelled client must still be recorded
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), historyTimeout)
+	defer cancel()
+	if err := db.InsertRecognition(ctx, recognition); err != nil {
+		logger.Warn("Error recording recognition", "error", err)
+		return
+	}
+
+	e.purgeExpired(ctx)
+}
+
+// purgeExpired runs PurgeHistory when the previous purge is older than purgeInterval
+func (e *Eureka) purgeExpired(ctx context.Context) {
+	e.history.mu.Lock()
+	due := time.Since(e.history.lastPurge) >= purgeInterval
+	if due {
+		e.history.lastPurge = time.Now()
+	}
+	e.history.mu.Unlock()
+	if !due {
+		return
+	}
+
+	if _, err := e.PurgeHistory(ctx); err != nil {
+		logger.Warn("Error purging recognition history", "error", err)
+	}
+}
@@ -0,0 +1,431 @@
+package eureka
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/migrate"
+	"github.com/media-luna/eureka/internal/database/mysql"
+)
+
+// OutputFormats lists the formats accepted by the Write functions of this file
+var OutputFormats = []string{"table", "json", "csv"}
+
+// songRecord is the output schema of a song, field names match the HTTP API
+type songRecord struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Artist        string `json:"artist"`
+	Album         string `json:"album"`
+	Fingerprinted bool   `json:"fingerprinted"`
+	FileSHA1      string `json:"file_sha1"`
+	TotalHashes   int    `json:"total_hashes"`
+	DateCreated   string `json:"date_created"`
+}
+
+// CheckOutputFormat returns an error unless format is table, json or csv
+func CheckOutputFormat(format string) error {
+	for _, f := range OutputFormats {
+		if strings.ToLower(format) == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format: %s, expected %s", format, strings.Join(OutputFormats, ", "))
+}
+
+// WriteMatches writes recognition results in the given format. JSON is an array of
+// matches, CSV has the columns rank, song_id, song, artist, score and offset_ms.
+func WriteMatches(w io.Writer, format string, matches []Match) error {
+	switch strings.ToLower(format) {
+	case "json":
+		if matches == nil {
+			matches = []Match{}
+		}
+		return writeJSON(w, matches)
+	case "csv":
+		rows := make([][]string, len(matches))
+		for i, m := range matches {
+			rows[i] = []string{strconv.Itoa(i + 1), strconv.Itoa(m.SongID), m.SongName, m.Artist,
+				strconv.FormatFloat(m.Score, 'f', 3, 64), strconv.Itoa(m.Offset)}
+		}
+		return writeCSV(w, []string{"rank", "song_id", "song", "artist", "score", "offset_ms"}, rows)
+	case "table":
+		rows := make([][]string, len(matches))
+		for i, m := range matches {
+			rows[i] = []string{strconv.Itoa(i + 1), strconv.Itoa(m.SongID), m.SongName, m.Artist,
+				strconv.FormatFloat(m.Score, 'f', 3, 64), fmt.Sprintf("%dms", m.Offset)}
+		}
+		return writeTable(w, []string{"#", "ID", "SONG", "ARTIST", "SCORE", "OFFSET"}, rows)
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// WriteSongs writes a song list in the given format. JSON is an array of songs, CSV has
+// the columns id, name, artist, album, fingerprinted, file_sha1, total_hashes and date_created.
+func WriteSongs(w io.Writer, format string, songs []mysql.Song) error {
+	switch strings.ToLower(format) {
+	case "json":
+		records := make([]songRecord, len(songs))
+		for i, s := range songs {
+			records[i] = songRecord(s)
+		}
+		return writeJSON(w, records)
+	case "csv":
+		rows := make([][]string, len(songs))
+		for i, s := range songs {
+			rows[i] = []string{strconv.Itoa(s.ID), s.Name, s.Artist, s.Album, strconv.FormatBool(s.Fingerprinted),
+				s.FileSHA1, strconv.Itoa(s.TotalHashes), s.DateCreated}
+		}
+		return writeCSV(w, []string{"id", "name", "artist", "album", "fingerprinted", "file_sha1", "total_hashes", "date_created"}, rows)
+	case "table":
+		rows := make([][]string, len(songs))
+		for i, s := range songs {
+			rows[i] = []string{strconv.Itoa(s.ID), s.Name, s.Artist, s.Album, strconv.FormatBool(s.Fingerprinted),
+				strconv.Itoa(s.TotalHashes), s.DateCreated}
+		}
+		return writeTable(w, []string{"ID", "NAME", "ARTIST", "ALBUM", "FINGERPRINTED", "HASHES", "CREATED"}, rows)
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// WriteSongInfo writes a single song in the given format. JSON is an object, CSV has
+// the columns id, name, artist and album.
+func WriteSongInfo(w io.Writer, format string, song mysql.SongInfo) error {
+	row := []string{strconv.Itoa(song.ID), song.Name, song.Artist, song.Album}
+
+	switch strings.ToLower(format) {
+	case "json":
+		return writeJSON(w, struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Artist string `json:"artist"`
+			Album  string `json:"album"`
+		}{song.ID, song.Name, song.Artist, song.Album})
+	case "csv":
+		return writeCSV(w, []string{"id", "name", "artist", "album"}, [][]string{row})
+	case "table":
+		return writeTable(w, []string{"ID", "NAME", "ARTIST", "ALBUM"}, [][]string{row})
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// WriteSaveResult writes the summary of an ingested song in the given format. JSON is
+// a single object, CSV has the columns song_id, song, artist, file, fingerprints and duration_ms.
+func WriteSaveResult(w io.Writer, format string, result SaveResult) error {
+	row := []string{strconv.Itoa(result.SongID), result.Song, result.Artist, result.File,
+		strconv.Itoa(result.Fingerprints), strconv.FormatInt(result.DurationMs, 10)}
+
+	switch strings.ToLower(format) {
+	case "json":
+		return writeJSON(w, result)
+	case "csv":
+		return writeCSV(w, []string{"song_id", "song", "artist", "file", "fingerprints", "duration_ms"}, [][]string{row})
+	case "table":
+		return writeTable(w, []string{"ID", "SONG", "ARTIST", "FILE", "FINGERPRINTS", "DURATION_MS"}, [][]string{row})
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// migrationRecord is the output schema of a migration in WriteMigrationStatus
+type migrationRecord struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"applied_at"`
+}
+
+// WriteMigrationStatus writes the applied and pending migrations in the given format.
+// JSON is an object with the current and latest versions and the migrations, CSV has
+// the columns version, description, applied and applied_at.
+func WriteMigrationStatus(w io.Writer, format string, status migrate.Status) error {
+	var records []migrationRecord
+	for _, a := range status.Applied {
+		appliedAt := a.AppliedAt.UTC()
+		records = append(records, migrationRecord{a.Version, a.Description, true, &appliedAt})
+	}
+	for _, m := range status.Pending {
+		records = append(records, migrationRecord{Version: m.Version, Description: m.Description})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Version < records[j].Version })
+
+	rows := make([][]string, len(records))
+	for i, r := range records {
+		appliedAt := ""
+		if r.AppliedAt != nil {
+			appliedAt = r.AppliedAt.Format(time.RFC3339)
+		}
+		rows[i] = []string{strconv.Itoa(r.Version), r.Description, strconv.FormatBool(r.Applied), appliedAt}
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		if records == nil {
+			records = []migrationRecord{}
+		}
+		return writeJSON(w, struct {
+			Current    int               `json:"current"`
+			Latest     int               `json:"latest"`
+			Migrations []migrationRecord `json:"migrations"`
+		}{status.Current, status.Latest, records})
+	case "csv":
+		return writeCSV(w, []string{"version", "description", "applied", "applied_at"}, rows)
+	case "table":
+		fmt.Fprintf(w, "Schema version %d, latest %d\n\n", status.Current, status.Latest)
+		return writeTable(w, []string{"VERSION", "DESCRIPTION", "APPLIED", "APPLIED_AT"}, rows)
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// statsRecord is the JSON schema of WriteStats
+type statsRecord struct {
+	Songs                int               `json:"songs"`
+	FingerprintedSongs   int               `json:"fingerprinted_songs"`
+	UnfingerprintedRatio float64           `json:"unfingerprinted_ratio"`
+	Fingerprints         int64             `json:"fingerprints"`
+	DistinctHashes       int64             `json:"distinct_hashes"`
+	FingerprintsPerSong  countRecord       `json:"fingerprints_per_song"`
+	TopHashes            []hashRecord      `json:"top_hashes"`
+	Collisions           []collisionRecord `json:"collisions"`
+	Tables               []tableRecord     `json:"tables"`
+}
+
+type countRecord struct {
+	Min    int `json:"min"`
+	Median int `json:"median"`
+	Max    int `json:"max"`
+}
+
+type hashRecord struct {
+	Hash        string `json:"hash"`
+	Songs       int    `json:"songs"`
+	Occurrences int64  `json:"occurrences"`
+}
+
+type collisionRecord struct {
+	MinSongs int   `json:"min_songs"`
+	MaxSongs int   `json:"max_songs"`
+	Hashes   int64 `json:"hashes"`
+}
+
+type tableRecord struct {
+	Name  string `json:"name"`
+	Rows  int64  `json:"rows"`
+	Bytes int64  `json:"bytes"`
+}
+
+// WriteStats writes catalog statistics as a table or JSON, CSV is not supported since
+// the statistics are not a single list
+func WriteStats(w io.Writer, format string, stats catalog.Stats) error {
+	unfingerprinted := 0.0
+	if stats.Songs > 0 {
+		unfingerprinted = float64(stats.Songs-stats.FingerprintedSongs) / float64(stats.Songs)
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		record := statsRecord{
+			Songs:                stats.Songs,
+			FingerprintedSongs:   stats.FingerprintedSongs,
+			UnfingerprintedRatio: unfingerprinted,
+			Fingerprints:         stats.Fingerprints,
+			DistinctHashes:       stats.DistinctHashes,
+			FingerprintsPerSong:  countRecord(stats.FingerprintsPerSong),
+			TopHashes:            make([]hashRecord, len(stats.TopHashes)),
+			Collisions:           make([]collisionRecord, len(stats.Collisions)),
+			Tables:               make([]tableRecord, len(stats.Tables)),
+		}
+		for i, h := range stats.TopHashes {
+			record.TopHashes[i] = hashRecord(h)
+		}
+		for i, c := range stats.Collisions {
+			record.Collisions[i] = collisionRecord(c)
+		}
+		for i, t := range stats.Tables {
+			record.Tables[i] = tableRecord(t)
+		}
+		return writeJSON(w, record)
+	case "table":
+		summary := [][]string{
+			{"Songs", fmt.Sprintf("%d (%d not fingerprinted, %.1f%%)", stats.Songs, stats.Songs-stats.FingerprintedSongs, 100*unfingerprinted)},
+			{"Fingerprints", strconv.FormatInt(stats.Fingerprints, 10)},
+			{"Distinct hashes", strconv.FormatInt(stats.DistinctHashes, 10)},
+			{"Fingerprints per song", fmt.Sprintf("min %d, median %d, max %d",
+				stats.FingerprintsPerSong.Min, stats.FingerprintsPerSong.Median, stats.FingerprintsPerSong.Max)},
+		}
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, row := range summary {
+			fmt.Fprintln(table, row[0]+":\t"+row[1])
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(stats.TopHashes))
+		for i, h := range stats.TopHashes {
+			rows[i] = []string{h.Hash, strconv.Itoa(h.Songs), strconv.FormatInt(h.Occurrences, 10)}
+		}
+		fmt.Fprintln(w)
+		if err := writeTable(w, []string{"MOST COMMON HASH", "SONGS", "OCCURRENCES"}, rows); err != nil {
+			return err
+		}
+
+		rows = make([][]string, len(stats.Collisions))
+		for i, c := range stats.Collisions {
+			songs := strconv.Itoa(c.MinSongs)
+			if c.MaxSongs > c.MinSongs {
+				songs += "-" + strconv.Itoa(c.MaxSongs)
+			}
+			share := 0.0
+			if stats.DistinctHashes > 0 {
+				share = 100 * float64(c.Hashes) / float64(stats.DistinctHashes)
+			}
+			rows[i] = []string{songs, strconv.FormatInt(c.Hashes, 10), fmt.Sprintf("%.2f%%", share)}
+		}
+		fmt.Fprintln(w)
+		if err := writeTable(w, []string{"SONGS SHARING A HASH", "HASHES", "SHARE"}, rows); err != nil {
+			return err
+		}
+
+		rows = make([][]string, len(stats.Tables))
+		for i, t := range stats.Tables {
+			rows[i] = []string{t.Name, strconv.FormatInt(t.Rows, 10), formatBytes(t.Bytes)}
+		}
+		fmt.Fprintln(w)
+		return writeTable(w, []string{"TABLE", "ROWS (ESTIMATED)", "SIZE"}, rows)
+	case "csv":
+		return fmt.Errorf("csv output is not supported for statistics, use table or json")
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// WriteHashes writes hash frequencies in the given format. JSON is an array, CSV has
+// the columns hash, songs and occurrences.
+func WriteHashes(w io.Writer, format string, hashes []catalog.HashFrequency) error {
+	rows := make([][]string, len(hashes))
+	for i, h := range hashes {
+		rows[i] = []string{h.Hash, strconv.Itoa(h.Songs), strconv.FormatInt(h.Occurrences, 10)}
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		records := make([]hashRecord, len(hashes))
+		for i, h := range hashes {
+			records[i] = hashRecord(h)
+		}
+		return writeJSON(w, records)
+	case "csv":
+		return writeCSV(w, []string{"hash", "songs", "occurrences"}, rows)
+	case "table":
+		return writeTable(w, []string{"HASH", "SONGS", "OCCURRENCES"}, rows)
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// recognitionRecord is the output schema of a recorded recognition, field names match
+// the HTTP API
+type recognitionRecord struct {
+	ID           int64                    `json:"id"`
+	RecognizedAt time.Time                `json:"recognized_at"`
+	Source       string                   `json:"source"`
+	ClientID     string                   `json:"client_id,omitempty"`
+	AudioMs      int64                    `json:"audio_ms"`
+	LatencyMs    int64                    `json:"latency_ms"`
+	Matches      []mysql.RecognitionMatch `json:"matches"`
+}
+
+// WriteRecognitions writes recorded recognitions in the given format. JSON is an array
+// of recognitions with all their matches, CSV has the columns id, recognized_at, source,
+// client_id, audio_ms, latency_ms, song_id, song, artist and score of the best match.
+func WriteRecognitions(w io.Writer, format string, recognitions []mysql.Recognition) error {
+	switch strings.ToLower(format) {
+	case "json":
+		records := make([]recognitionRecord, len(recognitions))
+		for i, r := range recognitions {
+			records[i] = recognitionRecord(r)
+		}
+		return writeJSON(w, records)
+	case "csv":
+		rows := make([][]string, len(recognitions))
+		for i, r := range recognitions {
+			rows[i] = []string{strconv.FormatInt(r.ID, 10), r.RecognizedAt.Format(time.RFC3339Nano), r.Source, r.ClientID,
+				strconv.FormatInt(r.AudioMs, 10), strconv.FormatInt(r.LatencyMs, 10), "", "", "", ""}
+			if len(r.Matches) > 0 {
+				m := r.Matches[0]
+				copy(rows[i][6:], []string{strconv.Itoa(m.SongID), m.Song, m.Artist, strconv.FormatFloat(m.Score, 'f', 3, 64)})
+			}
+		}
+		return writeCSV(w, []string{"id", "recognized_at", "source", "client_id", "audio_ms", "latency_ms",
+			"song_id", "song", "artist", "score"}, rows)
+	case "table":
+		rows := make([][]string, len(recognitions))
+		for i, r := range recognitions {
+			rows[i] = []string{strconv.FormatInt(r.ID, 10), r.RecognizedAt.Local().Format(time.DateTime), r.Source, r.ClientID,
+				fmt.Sprintf("%.1fs", float64(r.AudioMs)/1000), fmt.Sprintf("%dms", r.LatencyMs), "-", ""}
+			if len(r.Matches) > 0 {
+				m := r.Matches[0]
+				rows[i][6] = fmt.Sprintf("%s - %s (%d)", m.Artist, m.Song, m.SongID)
+				rows[i][7] = strconv.FormatFloat(m.Score, 'f', 3, 64)
+			}
+		}
+		return writeTable(w, []string{"ID", "TIME", "SOURCE", "CLIENT", "AUDIO", "LATENCY", "MATCH", "SCORE"}, rows)
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+// formatBytes formats a size with a binary unit, such as 1.5 MiB
+func formatBytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, unit := float64(n)/1024, 0
+	for value >= 1024 && unit < 4 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, []string{"KiB", "MiB", "GiB", "TiB", "PiB"}[unit])
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeCSV writes rows with a header row
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// writeTable writes rows as aligned columns for terminals
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	return table.Flush()
+}
@@ -1,90 +1,231 @@
 package eureka
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/signal"
 	"sort"
-	"syscall"
 	"time"
 
+	"github.com/media-luna/eureka/internal/audio"
 	"github.com/media-luna/eureka/internal/database/mysql"
 	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+	"github.com/media-luna/eureka/internal/metrics"
 	"github.com/media-luna/eureka/utils/logger"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Match represents a potential song match
 type Match struct {
-	SongID    int
-	SongName  string
-	Artist    string
-	Score     float64
-	Offset    int
-	Timestamp float64
+	SongID    int     `json:"song_id"`
+	SongName  string  `json:"song"`
+	Artist    string  `json:"artist"`
+	Score     float64 `json:"score"`
+	Offset    int     `json:"offset_ms"`
+	Timestamp float64 `json:"timestamp,omitempty"`
 }
 
+// RECOGNITION_SECONDS is how much of a sample is used for recognition, to avoid too many fingerprints
+const RECOGNITION_SECONDS = 30
+
 // Recognize processes an audio sample and tries to find matches in the database
-func (e *Eureka) Recognize(audioPath string) ([]Match, error) {
-	logger.Info(fmt.Sprintf("Recognizing audio file: %s", audioPath))
+func (e *Eureka) Recognize(ctx context.Context, audioPath string) ([]Match, error) {
+	start := time.Now()
+	logger.Info("Recognizing audio file", "file", audioPath)
+	ctx, span := tracer().Start(ctx, "Recognize")
+
+	_, decodeSpan := tracer().Start(ctx, metrics.StageDecode)
+	wavInfo, err := loadAudio(audioPath)
+	observeDecode(decodeSpan, start, err)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+
+	matches, err := e.recognizeSamples(ctx, start, wavInfo.Samples, wavInfo.SampleRate)
+	endSpan(span, err)
+	return matches, err
+}
 
-	// Convert audio to WAV
-	filePath, err := fingerprint.ConvertToWAV(audioPath, "recognize_output.wav")
+// RecognizeReader recognizes an encoded audio stream (WAV, FLAC or MP3, detected
+// from its content) such as stdin. Only the first 30 seconds are read.
+func (e *Eureka) RecognizeReader(ctx context.Context, r io.Reader) ([]Match, error) {
+	start := time.Now()
+	logger.Debug("Recognizing audio stream")
+	ctx, span := tracer().Start(ctx, "RecognizeReader")
+
+	_, decodeSpan := tracer().Start(ctx, metrics.StageDecode)
+	samples, sampleRate, err := fingerprint.DecodeReader(r, RECOGNITION_SECONDS)
+	observeDecode(decodeSpan, start, err)
 	if err != nil {
-		return nil, fmt.Errorf("error converting to WAV: %v", err)
+		err = fmt.Errorf("%w: %v", ErrInvalidAudio, err)
+		endSpan(span, err)
+		return nil, err
 	}
 
-	// Read wav info
-	wavInfo, err := fingerprint.ReadWavInfo(filePath)
+	matches, err := e.recognizeSamples(ctx, start, samples, sampleRate)
+	endSpan(span, err)
+	return matches, err
+}
+
+// RecognizePCM recognizes a headerless PCM stream in the given format.
+// Only the first 30 seconds are read.
+func (e *Eureka) RecognizePCM(ctx context.Context, r io.Reader, format audio.PCMFormat) ([]Match, error) {
+	start := time.Now()
+	logger.Debug("Recognizing raw PCM stream", "format", format.String())
+	ctx, span := tracer().Start(ctx, "RecognizePCM")
+
+	_, decodeSpan := tracer().Start(ctx, metrics.StageDecode)
+	samples, err := fingerprint.DecodePCMReader(r, format, RECOGNITION_SECONDS)
+	observeDecode(decodeSpan, start, err)
 	if err != nil {
-		return nil, fmt.Errorf("error reading WAV info: %v", err)
+		err = fmt.Errorf("%w: %v", ErrInvalidAudio, err)
+		endSpan(span, err)
+		return nil, err
+	}
+
+	matches, err := e.recognizeSamples(ctx, start, samples, format.SampleRate)
+	endSpan(span, err)
+	return matches, err
+}
+
+// RecognizeSamples tries to find matches for mono samples in the database
+func (e *Eureka) RecognizeSamples(ctx context.Context, samples []float64, sampleRate int) ([]Match, error) {
+	start := time.Now()
+	ctx, span := tracer().Start(ctx, "RecognizeSamples")
+	matches, err := e.recognizeSamples(ctx, start, samples, sampleRate)
+	endSpan(span, err)
+	return matches, err
+}
+
+// observeDecode ends the decode span and observes the stage started at start, a failed
+// decode counts as a failed recognition
+func observeDecode(span trace.Span, start time.Time, err error) {
+	endSpan(span, err)
+	observeStage(metrics.StageDecode, start)
+	if err != nil {
+		metrics.Recognitions.WithLabelValues(metrics.ResultError).Inc()
 	}
+}
+
+// observeStage observes the duration of a recognition stage started at start
+func observeStage(stage string, start time.Time) {
+	metrics.StageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
 
-	logger.Info(fmt.Sprintf("Original audio: %d samples at %d Hz (%.2f seconds)", len(wavInfo.Samples), wavInfo.SampleRate, float64(len(wavInfo.Samples))/float64(wavInfo.SampleRate)))
+// recognizeSamples matches samples and records the recognition in the history and the
+// metrics, with its latency counted from start
+func (e *Eureka) recognizeSamples(ctx context.Context, start time.Time, samples []float64, sampleRate int) ([]Match, error) {
+	matches, err := e.matchSamples(ctx, start, samples, sampleRate)
+	if err != nil {
+		metrics.Recognitions.WithLabelValues(metrics.ResultError).Inc()
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrMatches.Int(len(matches)))
+
+	result := metrics.ResultNoMatch
+	if len(matches) > 0 {
+		result = metrics.ResultMatch
+	}
+	metrics.Recognitions.WithLabelValues(result).Inc()
+	metrics.RecognitionDuration.Observe(time.Since(start).Seconds())
+
+	seconds := float64(min(len(samples), sampleRate*RECOGNITION_SECONDS)) / float64(sampleRate)
+	e.record(ctx, SourceFile, start, time.Since(start), seconds, matches)
+	return matches, nil
+}
+
+// matchSamples finds matches for the first 30 seconds of samples in the database
+func (e *Eureka) matchSamples(ctx context.Context, start time.Time, samples []float64, sampleRate int) ([]Match, error) {
+	logger.Debug("Original audio", "samples", len(samples), "sample_rate", sampleRate, "seconds", float64(len(samples))/float64(sampleRate))
 
 	// For recognition, only use first 30 seconds to avoid too many fingerprints
-	maxSamples := wavInfo.SampleRate * 30 // 30 seconds
-	originalLength := len(wavInfo.Samples)
+	maxSamples := sampleRate * RECOGNITION_SECONDS
+	originalLength := len(samples)
 	if originalLength > maxSamples {
-		wavInfo.Samples = wavInfo.Samples[:maxSamples]
-		logger.Info(fmt.Sprintf("Limited audio from %d to %d samples (first 30 seconds for recognition)", originalLength, len(wavInfo.Samples)))
+		samples = samples[:maxSamples]
+		logger.Debug("Limited audio to the first 30 seconds for recognition", "samples", len(samples), "original_samples", originalLength)
 	}
 
-	logger.Info("Generating spectrogram for recognition...")
 	// Generate spectrogram
-	spectrogram, err := fingerprint.SamplesToSpectrogram(wavInfo.Samples, wavInfo.SampleRate)
+	stageStart := time.Now()
+	_, span := tracer().Start(ctx, metrics.StageSpectrogram, trace.WithAttributes(
+		attrSamples.Int(len(samples)), attrSampleRate.Int(sampleRate)))
+	spectrogram, err := fingerprint.SamplesToSpectrogram(samples, sampleRate)
+	endSpan(span, err)
 	if err != nil {
 		return nil, fmt.Errorf("error creating spectrogram: %v", err)
 	}
+	observeStage(metrics.StageSpectrogram, stageStart)
 
 	// Extract peaks
-	peaks := fingerprint.PickPeaks(spectrogram, wavInfo.SampleRate)
-	logger.Info(fmt.Sprintf("Found %d peaks for recognition", len(peaks)))
+	stageStart = time.Now()
+	_, span = tracer().Start(ctx, metrics.StagePeaks)
+	peaks := fingerprint.PickPeaks(spectrogram, sampleRate)
+	span.SetAttributes(attrPeaks.Int(len(peaks)))
+	span.End()
+	observeStage(metrics.StagePeaks, stageStart)
+	logger.Debug("Found peaks for recognition", "peaks", len(peaks))
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Generate fingerprints
 	fingerprints := fingerprint.GenerateFingerprints(peaks)
-	logger.Info(fmt.Sprintf("Generated %d fingerprints for recognition", len(fingerprints)))
+	logger.Debug("Generated fingerprints for recognition", "fingerprints", len(fingerprints))
+	trace.SpanFromContext(ctx).SetAttributes(attrPeaks.Int(len(peaks)), attrPrints.Int(len(fingerprints)))
 
 	if len(fingerprints) == 0 {
 		return []Match{}, nil
 	}
 
-	// Create a map of sample fingerprints with their time offsets
-	sampleFingerprintMap := make(map[string]int)
-	for _, fp := range fingerprints {
-		sampleFingerprintMap[fp.Hash] = fp.Offset
-	}
-
 	// Query database for matching fingerprints
-	matches, err := e.findMatches(sampleFingerprintMap, false)
+	matches, err := e.findMatches(ctx, fingerprintMap(fingerprints), false)
 	if err != nil {
 		return nil, fmt.Errorf("error finding matches: %v", err)
 	}
 
+	logger.Info("Recognition finished", "matches", len(matches), "duration_ms", time.Since(start).Milliseconds())
 	return matches, nil
 }
 
+// loadAudio converts an audio file to a temporary WAV file and reads back its samples.
+// The file is removed afterwards, so concurrent calls do not overwrite each other.
+func loadAudio(audioPath string) (*fingerprint.WavInfo, error) {
+	wavFile, err := os.CreateTemp("", "eureka-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary file: %v", err)
+	}
+	wavFile.Close()
+	defer os.Remove(wavFile.Name())
+
+	filePath, err := fingerprint.ConvertToWAV(audioPath, wavFile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("error converting to WAV: %v", err)
+	}
+
+	wavInfo, err := fingerprint.ReadWavInfo(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading WAV info: %v", err)
+	}
+
+	return wavInfo, nil
+}
+
+// fingerprintMap maps each fingerprint hash to its time offset in the sample
+func fingerprintMap(fingerprints []fingerprint.Fingerprint) map[string]int {
+	sampleFingerprintMap := make(map[string]int, len(fingerprints))
+	for _, fp := range fingerprints {
+		sampleFingerprintMap[fp.Hash] = fp.Offset
+	}
+	return sampleFingerprintMap
+}
+
 // findMatches searches for fingerprint matches in the database and scores them
-func (e *Eureka) findMatches(sampleFingerprints map[string]int, isFromMicrophone bool) ([]Match, error) {
+func (e *Eureka) findMatches(ctx context.Context, sampleFingerprints map[string]int, isFromMicrophone bool) ([]Match, error) {
 	// Get all sample fingerprint hashes
 	hashes := make([]string, 0, len(sampleFingerprints))
 	for hash := range sampleFingerprints {
@@ -95,33 +236,20 @@ func (e *Eureka) findMatches(sampleFingerprints map[string]int, isFromMicrophone
 		return []Match{}, nil
 	}
 
-	logger.Info(fmt.Sprintf("Starting fingerprint matching with %d hashes", len(hashes)))
-
-	// Process in batches to avoid MySQL placeholder limit
-	const maxBatchSize = 1000 // Very conservative limit
-	var allDbMatches []mysql.FingerprintMatch
+	logger.Debug("Starting fingerprint matching", "hashes", len(hashes))
 
-	logger.Info(fmt.Sprintf("Will process in %d batches of max %d hashes each", (len(hashes)+maxBatchSize-1)/maxBatchSize, maxBatchSize))
-
-	for i := 0; i < len(hashes); i += maxBatchSize {
-		end := i + maxBatchSize
-		if end > len(hashes) {
-			end = len(hashes)
-		}
-
-		batchHashes := hashes[i:end]
-		logger.Info(fmt.Sprintf("Processing batch %d: %d hashes", (i/maxBatchSize)+1, len(batchHashes)))
-		dbMatches, err := e.database.QueryFingerprints(batchHashes)
-		if err != nil {
-			return nil, err
-		}
-
-		// Add all matches to our collection
-		allDbMatches = append(allDbMatches, dbMatches...)
+	queryStart := time.Now()
+	allDbMatches, err := e.queryFingerprints(ctx, hashes)
+	if err != nil {
+		return nil, err
 	}
+	observeStage(metrics.StageQuery, queryStart)
+	defer observeStage(metrics.StageScoring, time.Now())
+	ctx, span := tracer().Start(ctx, metrics.StageScoring)
+	defer span.End()
 
 	if len(allDbMatches) == 0 {
-		logger.Info("No matches found in database")
+		logger.Debug("No matches found in database")
 		return []Match{}, nil
 	}
 
@@ -158,9 +286,9 @@ func (e *Eureka) findMatches(sampleFingerprints map[string]int, isFromMicrophone
 
 		if score > scoreThreshold {
 			// Get song info
-			songInfo, err := e.database.GetSongByID(songID)
+			songInfo, err := e.getSongByID(ctx, songID)
 			if err != nil {
-				logger.Info(fmt.Sprintf("Error getting song info for ID %d: %v", songID, err))
+				logger.Warn("Error getting song info", "song_id", songID, "error", err)
 				continue
 			}
 
@@ -180,14 +308,68 @@ func (e *Eureka) findMatches(sampleFingerprints map[string]int, isFromMicrophone
 	})
 
 	// Return top matches
-	maxResults := 5
+	maxResults := e.Config.Recognition.TopResults
+	if maxResults <= 0 {
+		maxResults = 5
+	}
 	if len(matches) > maxResults {
 		matches = matches[:maxResults]
 	}
+	span.SetAttributes(attrMatches.Int(len(matches)))
 
 	return matches, nil
 }
 
+// queryFingerprints looks up hashes in the database in batches, skipping the
+// stop-listed ones
+func (e *Eureka) queryFingerprints(ctx context.Context, hashes []string) ([]mysql.FingerprintMatch, error) {
+	// Process in batches to avoid MySQL placeholder limit
+	const maxBatchSize = 1000 // Very conservative limit
+	var allDbMatches []mysql.FingerprintMatch
+
+	queried := len(hashes)
+	if queried > 0 {
+		hashes = e.pruneStopListed(hashes)
+		if pruned := queried - len(hashes); pruned > 0 {
+			metrics.PrunedHashes.Add(float64(pruned))
+			logger.Debug("Pruned stop-listed hashes", "hashes", queried, "stop_listed", pruned)
+		}
+	}
+
+	batches := (len(hashes) + maxBatchSize - 1) / maxBatchSize
+	ctx, span := tracer().Start(ctx, metrics.StageQuery, trace.WithAttributes(
+		attrHashes.Int(len(hashes)), attrStopListed.Int(queried-len(hashes)), attrBatches.Int(batches)))
+	defer span.End()
+
+	logger.Debug("Querying fingerprints", "batches", batches, "batch_size", maxBatchSize)
+
+	for i := 0; i < len(hashes); i += maxBatchSize {
+		end := i + maxBatchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		batchHashes := hashes[i:end]
+		logger.Debug("Processing batch", "batch", (i/maxBatchSize)+1, "hashes", len(batchHashes))
+		metrics.BatchSize.Observe(float64(len(batchHashes)))
+		batchCtx, batchSpan := tracer().Start(ctx, "QueryFingerprints", trace.WithAttributes(
+			attrBatch.Int((i/maxBatchSize)+1), attrHashes.Int(len(batchHashes))))
+		dbMatches, err := e.database.QueryFingerprints(batchCtx, batchHashes)
+		batchSpan.SetAttributes(attrRows.Int(len(dbMatches)))
+		endSpan(batchSpan, err)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		// Add all matches to our collection
+		allDbMatches = append(allDbMatches, dbMatches...)
+	}
+
+	span.SetAttributes(attrRows.Int(len(allDbMatches)))
+	return allDbMatches, nil
+}
+
 // TimeMatch represents a time alignment between sample and database
 type TimeMatch struct {
 	SampleTime int
@@ -260,174 +442,129 @@ func findMostCommonTimeDiff(timeMatches []TimeMatch) int {
 	return bestTimeDiff
 }
 
-// RecognizeFromMicrophone starts real-time recognition from microphone
-// Works like Shazam: listens until a match is found or 30 seconds timeout
-func (e *Eureka) RecognizeFromMicrophone() error {
-	logger.Info("Starting microphone recognition...")
+// RecognizeFromMicrophone starts real-time recognition from a live audio source
+// (microphone, file playback, stdin PCM...) and returns the song it found.
+// Works like Shazam: listens until a match is found, 30 seconds timeout, the source
+// ends or ctx is cancelled. The bool is false when no song was found.
+func (e *Eureka) RecognizeFromMicrophone(ctx context.Context, source audio.Source) (Match, bool, error) {
+	logger.Debug("Starting microphone recognition")
 
-	// Create microphone recorder
-	recorder, err := fingerprint.NewMicrophoneRecorder()
+	// Create recorder
+	recorder, err := fingerprint.NewRecorder(source)
 	if err != nil {
-		return fmt.Errorf("failed to create microphone recorder: %v", err)
+		return Match{}, false, fmt.Errorf("failed to create recorder: %v", err)
 	}
 	defer recorder.Cleanup()
 
 	// Start recording
 	err = recorder.StartRecording()
 	if err != nil {
-		return fmt.Errorf("failed to start recording: %v", err)
+		return Match{}, false, fmt.Errorf("failed to start recording: %v", err)
 	}
 
-	// Set up signal handling for graceful shutdown
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
-
 	// Set up 30-second timeout like Shazam
+	start := time.Now()
 	timeout := time.NewTimer(30 * time.Second)
 	defer timeout.Stop()
 
-	// Channel to receive match found signal
-	matchFoundChan := make(chan Match, 1)
+	// Fingerprint incrementally, only the audio recorded since the previous tick
+	opts := DefaultStreamOptions()
+	opts.SampleRate = recorder.SampleRate()
+	stream := e.NewStreamRecognizer(opts)
+	defer stream.Close()
+	var position int64
 
-	logger.Info("🎤 Listening for audio... (30s timeout)")
+	logger.Info("Listening for audio", "timeout_seconds", 30)
 
 	// Main recognition loop
-	recognitionTicker := time.NewTicker(2 * time.Second) // Check every 2 seconds
+	recognitionTicker := time.NewTicker(500 * time.Millisecond)
 	defer recognitionTicker.Stop()
 
 	for {
 		select {
-		case <-signalChan:
-			logger.Info("Received interrupt signal, stopping...")
+		case <-ctx.Done():
+			logger.Info("Recognition cancelled, stopping")
 			recorder.StopRecording()
-			return nil
+			return Match{}, false, ctx.Err()
 
 		case <-timeout.C:
-			logger.Info("⏰ No match found within 30 seconds, stopping...")
+			logger.Info("No match found within 30 seconds, stopping")
 			recorder.StopRecording()
-			return nil
+			return Match{}, false, nil
 
-		case match := <-matchFoundChan:
-			logger.Info(fmt.Sprintf("🎵 SONG FOUND: %s by %s (Score: %.3f)",
-				match.SongName, match.Artist, match.Score))
+		case event := <-stream.Events():
+			match := event.Match
+			if event.Type == EventPossibleMatch {
+				logger.Info("Possible match", "song_id", match.SongID, "song", match.SongName,
+					"artist", match.Artist, "score", match.Score)
+				continue
+			}
+			logger.Info("Song found", "song_id", match.SongID, "score", match.Score,
+				"duration_ms", time.Since(start).Milliseconds())
 			recorder.StopRecording()
-			return nil
+			return match, true, nil
 
 		case <-recognitionTicker.C:
-			// Get current audio buffer
-			audioBuffer := recorder.GetAudioBuffer()
+			var samples []float64
+			samples, position = recorder.ReadSince(position)
+			if err := stream.WriteContext(ctx, samples); err != nil {
+				logger.Warn("Recognition error", "error", err)
+			}
 
-			// Only process if we have enough audio (at least 3 seconds)
-			minSamples := 44100 * 3 // 3 seconds at 44.1kHz
-			if len(audioBuffer) >= minSamples {
-				go e.processRealtimeAudioWithMatch(audioBuffer, matchFoundChan)
+		case <-recorder.Done():
+			recorder.StopRecording()
+			if err := recorder.Err(); err != nil {
+				return Match{}, false, fmt.Errorf("audio source failed: %v", err)
 			}
 
-		case result := <-recorder.GetResultChannel():
-			if result.Error != nil {
-				logger.Info(fmt.Sprintf("Recognition error: %v", result.Error))
+			// Recognize the audio left in the buffer before giving up
+			samples, _ := recorder.ReadSince(position)
+			if err := stream.WriteContext(ctx, samples); err != nil {
+				return Match{}, false, err
+			}
+			for {
+				select {
+				case event := <-stream.Events():
+					if event.Type == EventMatch {
+						logger.Info("Song found", "song_id", event.Match.SongID, "score", event.Match.Score,
+							"duration_ms", time.Since(start).Milliseconds())
+						return event.Match, true, nil
+					}
+				default:
+					logger.Info("Audio source ended before a match was found")
+					return Match{}, false, nil
+				}
 			}
 		}
 	}
 }
 
-// processRealtimeAudioWithMatch processes audio buffer for real-time recognition with match detection
-func (e *Eureka) processRealtimeAudioWithMatch(audioBuffer []float64, matchFoundChan chan<- Match) {
-	defer func() {
-		if r := recover(); r != nil {
-			logger.Info(fmt.Sprintf("Recovery in processRealtimeAudioWithMatch: %v", r))
-		}
-	}()
-
-	// Use last 5 seconds of audio for recognition
-	sampleRate := 44100
-	windowSamples := sampleRate * 5 // 5 seconds
-
-	if len(audioBuffer) < windowSamples {
-		logger.Info(fmt.Sprintf("🔧 Audio buffer too small: %d < %d", len(audioBuffer), windowSamples))
-		return
-	}
-
-	// Take the most recent window
-	audioWindow := audioBuffer[len(audioBuffer)-windowSamples:]
-
-	// Calculate audio levels for debugging
-	var maxLevel, avgLevel float64
-	for _, sample := range audioWindow {
-		absVal := sample
-		if absVal < 0 {
-			absVal = -absVal
-		}
-		if absVal > maxLevel {
-			maxLevel = absVal
-		}
-		avgLevel += absVal
-	}
-	avgLevel /= float64(len(audioWindow))
-
-	logger.Info(fmt.Sprintf("🎚️ Audio levels - Max: %.4f, Avg: %.4f", maxLevel, avgLevel))
-
+// RecognizeWindow matches a short window of live audio (microphone, broadcast feed)
+// against the database. It fingerprints with microphone tolerance and returns no
+// matches when the window has too few peaks or fingerprints to be reliable.
+func (e *Eureka) RecognizeWindow(ctx context.Context, audioWindow []float64, sampleRate int) ([]Match, error) {
 	// Generate spectrogram
 	spectrogram, err := fingerprint.SamplesToSpectrogram(audioWindow, sampleRate)
 	if err != nil {
-		logger.Info(fmt.Sprintf("Spectrogram generation failed: %v", err))
-		return
+		return nil, fmt.Errorf("spectrogram generation failed: %v", err)
 	}
 
 	// Extract peaks
 	peaks := fingerprint.PickPeaks(spectrogram, sampleRate)
-	logger.Info(fmt.Sprintf("🎯 Found %d peaks from audio", len(peaks)))
+	logger.Debug("Found peaks from audio", "peaks", len(peaks))
 	if len(peaks) < 20 { // Lowered from 50 to be more tolerant
-		logger.Info("❌ Not enough peaks for reliable recognition (need 20+)")
-		return
+		logger.Debug("Not enough peaks for reliable recognition (need 20+)")
+		return []Match{}, nil
 	}
 
 	// Generate fingerprints with microphone tolerance
 	fingerprints := fingerprint.GenerateFingerprintsForMicrophone(peaks)
-	logger.Info(fmt.Sprintf("🔑 Generated %d fingerprints (with microphone tolerance)", len(fingerprints)))
+	logger.Debug("Generated fingerprints with microphone tolerance", "fingerprints", len(fingerprints))
 	if len(fingerprints) < 50 { // Lowered from 100 to be more tolerant
-		logger.Info("❌ Not enough fingerprints for reliable recognition (need 50+)")
-		return
-	}
-
-	// Create fingerprint map
-	sampleFingerprintMap := make(map[string]int)
-	for _, fp := range fingerprints {
-		sampleFingerprintMap[fp.Hash] = fp.Offset
+		logger.Debug("Not enough fingerprints for reliable recognition (need 50+)")
+		return []Match{}, nil
 	}
 
 	// Try to find matches with microphone-specific parameters
-	matches, err := e.findMatches(sampleFingerprintMap, true)
-	if err != nil {
-		logger.Info(fmt.Sprintf("Match finding failed: %v", err))
-		return
-	}
-
-	logger.Info(fmt.Sprintf("📊 Found %d potential matches", len(matches)))
-
-	// Check for high-confidence matches and send to channel if found
-	for _, match := range matches {
-		if match.Score > 0.3 { // Lower threshold for microphone recognition
-			logger.Info(fmt.Sprintf("🎵 MATCH FOUND: %s by %s (Score: %.3f)",
-				match.SongName, match.Artist, match.Score))
-
-			// Send match to channel (non-blocking)
-			select {
-			case matchFoundChan <- match:
-			default:
-				// Channel is full, match already found
-			}
-			return
-		} else if match.Score > 0.1 { // Lower threshold for progress indication
-			logger.Info(fmt.Sprintf("🔍 Possible match: %s by %s (Score: %.3f)",
-				match.SongName, match.Artist, match.Score))
-		} else if match.Score > 0.05 { // Even lower threshold to see any activity
-			logger.Info(fmt.Sprintf("🔍 Weak match: %s by %s (Score: %.3f)",
-				match.SongName, match.Artist, match.Score))
-		}
-	}
-
-	if len(matches) == 0 {
-		logger.Info("❌ No matches found in database")
-	}
+	return e.findMatches(ctx, fingerprintMap(fingerprints), true)
 }
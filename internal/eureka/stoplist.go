@@ -0,0 +1,131 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// Hashes found in a large share of the songs (silence, steady tones, common drum
+// patterns) cannot tell songs apart: they bloat every QueryFingerprints result and add
+// noise to the scores. BuildStopList lists them, after which they are not looked up at
+// recognition. They are still stored at ingest and import, so that the document
+// frequencies of a later build count every song.
+
+// stopLister is implemented by the databases able to store a stop-list
+type stopLister interface {
+	StopList(ctx context.Context) ([]catalog.HashFrequency, error)
+	BuildStopList(ctx context.Context, minSongs int) (int, error)
+	ClearStopList(ctx context.Context) error
+}
+
+// StopListResult summarizes a built stop-list
+type StopListResult struct {
+	Songs        int   `json:"songs"`        // Fingerprinted songs in the catalog
+	MinSongs     int   `json:"min_songs"`    // Songs a hash was found in to be stop-listed
+	Hashes       int   `json:"hashes"`       // Stop-listed hashes
+	Fingerprints int64 `json:"fingerprints"` // Stored fingerprints with a stop-listed hash
+}
+
+// LoadStopList reads the stop-list of the database into memory. Databases without a
+// stop-list leave it empty.
+func (e *Eureka) LoadStopList(ctx context.Context) error {
+	db, ok := e.database.(stopLister)
+	if !ok {
+		return nil
+	}
+	hashes, err := db.StopList(ctx)
+	if err != nil {
+		return err
+	}
+
+	stopList := make(map[string]struct{}, len(hashes))
+	for _, h := range hashes {
+		stopList[h.Hash] = struct{}{}
+	}
+	e.stopMu.Lock()
+	e.stopList = stopList
+	e.stopMu.Unlock()
+	if len(stopList) > 0 {
+		logger.Debug("Loaded stop-list", "hashes", len(stopList))
+	}
+	return nil
+}
+
+// StopList returns the stop-listed hashes, found in the most songs first
+func (e *Eureka) StopList(ctx context.Context) ([]catalog.HashFrequency, error) {
+	db, ok := e.database.(stopLister)
+	if !ok {
+		return nil, fmt.Errorf("database type does not support a stop-list: %w", errors.ErrUnsupported)
+	}
+	return db.StopList(ctx)
+}
+
+// BuildStopList replaces the stop-list with the hashes found in more than
+// stoplist.max_document_frequency of the fingerprinted songs, and in at least
+// stoplist.min_songs songs. Stop-listed hashes are only skipped at recognition, so
+// clearing or rebuilding the stop-list with another threshold takes effect at once.
+func (e *Eureka) BuildStopList(ctx context.Context) (StopListResult, error) {
+	db, ok := e.database.(stopLister)
+	if !ok {
+		return StopListResult{}, fmt.Errorf("database type does not support a stop-list: %w", errors.ErrUnsupported)
+	}
+
+	fingerprinted := true
+	page, err := e.List(ctx, catalog.SongQuery{Fingerprinted: &fingerprinted, Limit: 1})
+	if err != nil {
+		return StopListResult{}, err
+	}
+	result := StopListResult{
+		Songs:    page.Total,
+		MinSongs: max(e.Config.StopList.MinSongs, int(math.Floor(e.Config.StopList.MaxDocumentFrequency*float64(page.Total)))+1),
+	}
+
+	if result.Hashes, err = db.BuildStopList(ctx, result.MinSongs); err != nil {
+		return StopListResult{}, err
+	}
+	hashes, err := db.StopList(ctx)
+	if err != nil {
+		return StopListResult{}, err
+	}
+	for _, h := range hashes {
+		result.Fingerprints += h.Occurrences
+	}
+	if err := e.LoadStopList(ctx); err != nil {
+		return StopListResult{}, err
+	}
+	return result, nil
+}
+
+// ClearStopList removes every stop-listed hash
+func (e *Eureka) ClearStopList(ctx context.Context) error {
+	db, ok := e.database.(stopLister)
+	if !ok {
+		return fmt.Errorf("database type does not support a stop-list: %w", errors.ErrUnsupported)
+	}
+	if err := db.ClearStopList(ctx); err != nil {
+		return err
+	}
+	return e.LoadStopList(ctx)
+}
+
+// pruneStopListed returns the hashes that are not stop-listed, reusing the array of hashes
+func (e *Eureka) pruneStopListed(hashes []string) []string {
+	e.stopMu.RLock()
+	defer e.stopMu.RUnlock()
+	if len(e.stopList) == 0 {
+		return hashes
+	}
+
+	kept := hashes[:0]
+	for _, hash := range hashes {
+		if _, ok := e.stopList[hash]; !ok {
+			kept = append(kept, hash)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,48 @@
+package eureka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eurekatest"
+	"github.com/media-luna/eureka/internal/metrics"
+)
+
+func TestRecognitionPrunesStopListedHashes(t *testing.T) {
+	_, samples := eurekatest.ChirpPCM(t, 8)
+	fingerprints := eurekatest.Fingerprints(t, samples)
+	catalog := &eurekatest.Catalog{}
+	catalog.Add(mysql.SongInfo{ID: 1, Name: "Chirp"}, fingerprints, 0)
+
+	e := NewEurekaWithDatabase(config.Default(), catalog)
+	e.stopList = make(map[string]struct{})
+	for _, fp := range fingerprints {
+		e.stopList[fp.Hash] = struct{}{}
+	}
+
+	before := testutil.ToFloat64(metrics.PrunedHashes)
+	matches, err := e.RecognizeSamples(context.Background(), samples, eurekatest.SampleRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("matches = %+v, want none with every hash stop-listed", matches)
+	}
+	if pruned := testutil.ToFloat64(metrics.PrunedHashes) - before; pruned == 0 {
+		t.Fatal("no pruned hashes counted")
+	}
+
+	// Clearing the stop-list takes effect at once, the fingerprints are still stored
+	e.stopList = nil
+	matches, err = e.RecognizeSamples(context.Background(), samples, eurekatest.SampleRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].SongID != 1 {
+		t.Fatalf("matches = %+v, want song 1", matches)
+	}
+}
@@ -0,0 +1,294 @@
+package eureka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/mysql"
+	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+	"github.com/media-luna/eureka/internal/metrics"
+)
+
+// MatchEventType distinguishes interim and final stream recognition events
+type MatchEventType string
+
+const (
+	// EventPossibleMatch reports a new leading candidate that is not yet confident enough
+	EventPossibleMatch MatchEventType = "possible_match"
+	// EventMatch reports that a song crossed the match threshold, it is sent once per stream
+	EventMatch MatchEventType = "match"
+)
+
+// MatchEvent is emitted by a StreamRecognizer as its confidence in a song grows
+type MatchEvent struct {
+	Type    MatchEventType
+	Match   Match
+	Seconds float64 // Amount of audio analyzed when the event fired
+}
+
+// StreamOptions configures a StreamRecognizer
+type StreamOptions struct {
+	SampleRate        int
+	Microphone        bool    // Add tolerance hashes and use the more generous microphone scoring
+	MatchThreshold    float64 // Score at which EventMatch is emitted
+	PossibleThreshold float64 // Score at which EventPossibleMatch is emitted
+	MinMatches        int     // Minimum number of hash hits before a song is scored
+}
+
+// DefaultStreamOptions returns the options used for live microphone recognition
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		SampleRate:        44100,
+		Microphone:        true,
+		MatchThreshold:    0.3, // Lower threshold for microphone recognition
+		PossibleThreshold: 0.1,
+		MinMatches:        3,
+	}
+}
+
+// StreamRecognizer recognizes a song from audio pushed in chunks. It fingerprints only
+// the frames completed by each chunk, accumulates per-song offset histograms across
+// chunks, and emits events as soon as the leading song crosses a threshold.
+// Write is synchronous and not safe for concurrent use.
+type StreamRecognizer struct {
+	eureka        *Eureka
+	opts          StreamOptions
+	fingerprinter *fingerprint.StreamFingerprinter
+	histograms    map[int]*offsetHistogram
+	songInfo      map[int]mysql.SongInfo
+	events        chan MatchEvent
+	possibleID    int
+	matched       bool
+	closed        bool
+
+	// Recorded in the history by Close
+	started  time.Time // First audio written
+	finished time.Time // Match emitted
+	clientID string
+	api      bool
+	final    Match
+}
+
+// offsetHistogram counts the time differences between database and stream offsets of one song
+type offsetHistogram struct {
+	counts    map[int]int
+	total     int
+	bestCount int
+	bestDiff  int
+}
+
+func (h *offsetHistogram) add(timeDiff int) {
+	h.counts[timeDiff]++
+	h.total++
+	if h.counts[timeDiff] > h.bestCount {
+		h.bestCount = h.counts[timeDiff]
+		h.bestDiff = timeDiff
+	}
+}
+
+// NewStreamRecognizer creates a recognizer for a single audio stream
+func (e *Eureka) NewStreamRecognizer(opts StreamOptions) *StreamRecognizer {
+	return &StreamRecognizer{
+		eureka:        e,
+		opts:          opts,
+		fingerprinter: fingerprint.NewStreamFingerprinter(opts.SampleRate, opts.Microphone),
+		histograms:    make(map[int]*offsetHistogram),
+		songInfo:      make(map[int]mysql.SongInfo),
+		events:        make(chan MatchEvent, 16),
+	}
+}
+
+// Events returns the channel on which match events are delivered. It is closed by Close.
+func (s *StreamRecognizer) Events() <-chan MatchEvent {
+	return s.events
+}
+
+// Write feeds mono samples to the recognizer. Once a match was emitted further
+// audio is ignored.
+func (s *StreamRecognizer) Write(samples []float64) error {
+	return s.WriteContext(context.Background(), samples)
+}
+
+// WriteContext is like Write, with ctx bounding the database lookups
+func (s *StreamRecognizer) WriteContext(ctx context.Context, samples []float64) error {
+	if s.closed {
+		return fmt.Errorf("stream recognizer is closed")
+	}
+	if s.matched {
+		return nil
+	}
+	if s.started.IsZero() && len(samples) > 0 {
+		s.started = time.Now()
+		s.clientID, s.api = clientFrom(ctx)
+	}
+
+	fingerprints := s.fingerprinter.Write(samples)
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	// A hash can occur several times in a stream, keep every offset
+	offsets := make(map[string][]int)
+	for _, fp := range fingerprints {
+		offsets[fp.Hash] = append(offsets[fp.Hash], fp.Offset)
+	}
+
+	hashes := make([]string, 0, len(offsets))
+	for hash := range offsets {
+		hashes = append(hashes, hash)
+	}
+
+	dbMatches, err := s.eureka.queryFingerprints(ctx, hashes)
+	if err != nil {
+		return fmt.Errorf("error finding matches: %v", err)
+	}
+
+	for _, dbMatch := range dbMatches {
+		histogram, ok := s.histograms[dbMatch.SongID]
+		if !ok {
+			histogram = &offsetHistogram{counts: make(map[int]int)}
+			s.histograms[dbMatch.SongID] = histogram
+		}
+		for _, offset := range offsets[dbMatch.Hash] {
+			histogram.add(dbMatch.Offset - offset)
+		}
+	}
+
+	return s.evaluate(ctx)
+}
+
+// Best returns the current leading song, if any song has enough hash hits to be scored
+func (s *StreamRecognizer) Best(ctx context.Context) (Match, bool) {
+	songID, score := s.leader()
+	if songID == 0 {
+		return Match{}, false
+	}
+
+	match, err := s.match(ctx, songID, score)
+	if err != nil {
+		return Match{}, false
+	}
+	return match, true
+}
+
+// Seconds returns the amount of audio analyzed so far
+func (s *StreamRecognizer) Seconds() float64 {
+	return s.fingerprinter.Seconds()
+}
+
+// Close releases the recognizer and closes the events channel. A session that received
+// audio is recorded in the history, with its match if one was emitted.
+func (s *StreamRecognizer) Close() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.events)
+
+	// Sessions closed before any audio, such as rejected requests, are not counted
+	if s.started.IsZero() || s.Seconds() == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	source := SourceMic
+	if s.api {
+		ctx = WithClient(ctx, s.clientID)
+		source = SourceAPI
+	}
+	outcome := metrics.ResultNoMatch
+	var matches []Match
+	finished := time.Now()
+	if s.matched {
+		outcome = metrics.ResultMatch
+		matches = []Match{s.final}
+		finished = s.finished
+	}
+	metrics.StreamSessions.WithLabelValues(source, outcome).Inc()
+	s.eureka.record(ctx, source, s.started, finished.Sub(s.started), s.Seconds(), matches)
+}
+
+// evaluate emits events for the leading song
+func (s *StreamRecognizer) evaluate(ctx context.Context) error {
+	songID, score := s.leader()
+	if songID == 0 || score < s.opts.PossibleThreshold {
+		return nil
+	}
+
+	if score < s.opts.MatchThreshold && songID == s.possibleID {
+		return nil
+	}
+
+	match, err := s.match(ctx, songID, score)
+	if err != nil {
+		return err
+	}
+
+	if score >= s.opts.MatchThreshold {
+		s.matched = true
+		s.finished = time.Now()
+		s.final = match
+		// The last slot of the buffer is reserved for this event so it is never dropped
+		s.events <- MatchEvent{Type: EventMatch, Match: match, Seconds: s.Seconds()}
+		return nil
+	}
+
+	s.possibleID = songID
+	if len(s.events) < cap(s.events)-1 {
+		s.events <- MatchEvent{Type: EventPossibleMatch, Match: match, Seconds: s.Seconds()}
+	}
+	return nil
+}
+
+// leader returns the highest scoring song, or 0 when no song has enough hits
+func (s *StreamRecognizer) leader() (int, float64) {
+	bestID, bestScore := 0, 0.0
+	for songID, histogram := range s.histograms {
+		if histogram.total < s.opts.MinMatches {
+			continue
+		}
+		if score := s.score(histogram); score > bestScore {
+			bestID, bestScore = songID, score
+		}
+	}
+	return bestID, bestScore
+}
+
+// score applies the scoring of calculateTemporalScore to an accumulated histogram
+func (s *StreamRecognizer) score(h *offsetHistogram) float64 {
+	alignedRatio := float64(h.bestCount) / float64(h.total)
+	rawScore := float64(h.bestCount) * alignedRatio
+
+	normalizationFactor := 100.0
+	if s.opts.Microphone {
+		normalizationFactor = 50.0 // More generous scoring
+	}
+
+	score := rawScore / normalizationFactor
+	if score > 1.0 {
+		score = 1.0
+	}
+	return score
+}
+
+// match builds a Match for a song, caching the song information
+func (s *StreamRecognizer) match(ctx context.Context, songID int, score float64) (Match, error) {
+	info, ok := s.songInfo[songID]
+	if !ok {
+		var err error
+		info, err = s.eureka.getSongByID(ctx, songID)
+		if err != nil {
+			return Match{}, fmt.Errorf("error getting song info for ID %d: %v", songID, err)
+		}
+		s.songInfo[songID] = info
+	}
+
+	return Match{
+		SongID:   songID,
+		SongName: info.Name,
+		Artist:   info.Artist,
+		Score:    score,
+		Offset:   s.histograms[songID].bestDiff,
+	}, nil
+}
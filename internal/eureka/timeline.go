@@ -0,0 +1,260 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+// Segment represents a contiguous stretch of a recording identified as one song
+type Segment struct {
+	Start           float64 `json:"start"` // Seconds from the beginning of the recording
+	End             float64 `json:"end"`   // Seconds from the beginning of the recording
+	SongID          int     `json:"song_id"`
+	SongName        string  `json:"song"`
+	Artist          string  `json:"artist"`
+	ReferenceOffset int     `json:"reference_offset_ms"` // Position in the reference song at Start
+	Confidence      float64 `json:"confidence"`
+}
+
+// TimelineOptions controls how a long recording is split into analysis windows
+type TimelineOptions struct {
+	WindowSeconds   float64 // Length of each analysis window
+	HopSeconds      float64 // Distance between the starts of consecutive windows
+	MinConfidence   float64 // Windows whose best match scores below this are treated as unknown
+	MaxGapSeconds   float64 // Largest gap between detections of the same song that is still merged
+	OffsetTolerance int     // Allowed drift (ms) of the reference offset when merging windows
+}
+
+// DefaultTimelineOptions returns the window settings used by the CLI
+func DefaultTimelineOptions() TimelineOptions {
+	return TimelineOptions{
+		WindowSeconds:   10,
+		HopSeconds:      5,
+		MinConfidence:   0.1,
+		MaxGapSeconds:   10,
+		OffsetTolerance: 2000,
+	}
+}
+
+// RecognizeTimeline slides a window over the whole recording and returns every
+// detected song as a segment, merging adjacent windows that match the same song.
+// The recording is decoded as the window slides, only one window is held in memory.
+func (e *Eureka) RecognizeTimeline(ctx context.Context, audioPath string, opts TimelineOptions) ([]Segment, error) {
+	if opts.WindowSeconds <= 0 || opts.HopSeconds <= 0 {
+		return nil, fmt.Errorf("window and hop must be positive")
+	}
+
+	logger.Info("Building timeline", "file", audioPath)
+
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audio file: %v", err)
+	}
+	defer file.Close()
+
+	decoder, err := fingerprint.NewDecoder(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAudio, err)
+	}
+	defer decoder.Close()
+
+	sampleRate := decoder.SampleRate()
+	windowSamples := int(opts.WindowSeconds * float64(sampleRate))
+	hopSamples := int(opts.HopSeconds * float64(sampleRate))
+	if windowSamples == 0 || hopSamples == 0 {
+		return nil, fmt.Errorf("window and hop must hold at least one sample at %d Hz", sampleRate)
+	}
+
+	logger.Debug("Scanning recording", "window_seconds", opts.WindowSeconds, "hop_seconds", opts.HopSeconds)
+
+	windows := &slidingWindow{reader: decoder, window: windowSamples, hop: hopSamples}
+	builder := timelineBuilder{opts: opts}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		samples, start, ok, err := windows.next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAudio, err)
+		}
+		if !ok {
+			break
+		}
+
+		match, ok, err := e.matchWindow(ctx, samples, sampleRate, opts.MinConfidence)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			builder.add(match, float64(start)/float64(sampleRate), float64(start+len(samples))/float64(sampleRate))
+		}
+	}
+
+	logger.Info("Timeline built", "segments", len(builder.segments))
+	return builder.segments, nil
+}
+
+// sampleReader is a source of mono samples, such as a fingerprint.Decoder
+type sampleReader interface {
+	Read(dst []float64) (int, error)
+}
+
+// slidingWindow reads the analysis windows of a recording one after the other,
+// keeping only the samples of the current window
+type slidingWindow struct {
+	reader  sampleReader
+	window  int // Samples per window
+	hop     int // Samples between the starts of consecutive windows
+	samples []float64
+	start   int  // Position of the window in the recording, in samples
+	done    bool // The reader reached the end of the recording
+}
+
+// next returns the next window and its start. The last window is shorter when the
+// recording ends inside it, and ok is false once the recording is covered.
+func (w *slidingWindow) next() (samples []float64, start int, ok bool, err error) {
+	if w.done {
+		return nil, 0, false, nil
+	}
+
+	if w.samples == nil {
+		w.samples = make([]float64, 0, w.window)
+	} else {
+		// Keep the overlap with the next window, or read past the gap before it
+		if w.hop < len(w.samples) {
+			w.samples = append(w.samples[:0], w.samples[w.hop:]...)
+		} else {
+			if err := w.skip(w.hop - len(w.samples)); err != nil {
+				return nil, 0, false, err
+			}
+			w.samples = w.samples[:0]
+		}
+		w.start += w.hop
+	}
+
+	kept := len(w.samples)
+	for !w.done && len(w.samples) < w.window {
+		n, err := w.reader.Read(w.samples[len(w.samples):w.window])
+		w.samples = w.samples[:len(w.samples)+n]
+		if errors.Is(err, io.EOF) {
+			w.done = true
+		} else if err != nil {
+			return nil, 0, false, err
+		}
+	}
+
+	// Nothing new since the previous window, which already ended with the recording
+	if len(w.samples) == kept {
+		w.done = true
+		return nil, 0, false, nil
+	}
+	return w.samples, w.start, true, nil
+}
+
+// skip reads and drops n samples
+func (w *slidingWindow) skip(n int) error {
+	for n > 0 && !w.done {
+		read, err := w.reader.Read(w.samples[:min(n, w.window)])
+		n -= read
+		if errors.Is(err, io.EOF) {
+			w.done = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timelineBuilder turns the matches of consecutive windows into segments
+type timelineBuilder struct {
+	opts        TimelineOptions
+	segments    []Segment
+	windowCount []int // Number of windows merged into each segment, for averaging confidence
+}
+
+// add records the match of the window from windowStart to windowEnd seconds, extending
+// the last segment when the match continues it
+func (b *timelineBuilder) add(match Match, windowStart, windowEnd float64) {
+	last := len(b.segments) - 1
+	if last >= 0 && canMerge(b.segments[last], match, windowStart, b.opts) {
+		n := float64(b.windowCount[last])
+		b.segments[last].End = windowEnd
+		b.segments[last].Confidence = (b.segments[last].Confidence*n + match.Score) / (n + 1)
+		b.windowCount[last]++
+		return
+	}
+
+	segment := Segment{
+		Start:           windowStart,
+		End:             windowEnd,
+		SongID:          match.SongID,
+		SongName:        match.SongName,
+		Artist:          match.Artist,
+		ReferenceOffset: match.Offset,
+		Confidence:      match.Score,
+	}
+
+	// Overlapping windows detected different songs, split the overlap in half
+	if last >= 0 && b.segments[last].End > segment.Start {
+		boundary := (b.segments[last].End + segment.Start) / 2
+		b.segments[last].End = boundary
+		segment.ReferenceOffset += int(math.Round((boundary - segment.Start) * 1000))
+		segment.Start = boundary
+	}
+
+	b.segments = append(b.segments, segment)
+	b.windowCount = append(b.windowCount, 1)
+}
+
+// matchWindow fingerprints a single window and returns its best match above minScore
+func (e *Eureka) matchWindow(ctx context.Context, samples []float64, sampleRate int, minScore float64) (Match, bool, error) {
+	spectrogram, err := fingerprint.SamplesToSpectrogram(samples, sampleRate)
+	if err != nil {
+		return Match{}, false, fmt.Errorf("error creating spectrogram: %v", err)
+	}
+
+	peaks := fingerprint.PickPeaks(spectrogram, sampleRate)
+	fingerprints := fingerprint.GenerateFingerprints(peaks)
+	if len(fingerprints) == 0 {
+		return Match{}, false, nil
+	}
+
+	matches, err := e.findMatches(ctx, fingerprintMap(fingerprints), false)
+	if err != nil {
+		return Match{}, false, fmt.Errorf("error finding matches: %v", err)
+	}
+
+	if len(matches) == 0 || matches[0].Score < minScore {
+		return Match{}, false, nil
+	}
+
+	return matches[0], true, nil
+}
+
+// canMerge reports whether a window match continues the given segment: same song,
+// small enough gap, and a reference offset that advanced together with the recording
+func canMerge(segment Segment, match Match, windowStart float64, opts TimelineOptions) bool {
+	if segment.SongID != match.SongID {
+		return false
+	}
+
+	if windowStart-segment.End > opts.MaxGapSeconds {
+		return false
+	}
+
+	expectedOffset := segment.ReferenceOffset + int(math.Round((windowStart-segment.Start)*1000))
+	drift := match.Offset - expectedOffset
+	if drift < 0 {
+		drift = -drift
+	}
+
+	return drift <= opts.OffsetTolerance
+}
@@ -0,0 +1,236 @@
+package eureka
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eurekatest"
+)
+
+// countingReader returns the samples 0, 1, 2... up to total, in chunks of the sizes
+// in turn
+type countingReader struct {
+	total  int
+	chunks []int
+	pos    int
+	reads  int
+}
+
+func (r *countingReader) Read(dst []float64) (int, error) {
+	if r.pos == r.total {
+		return 0, io.EOF
+	}
+	n := min(len(dst), r.chunks[r.reads%len(r.chunks)], r.total-r.pos)
+	r.reads++
+	for i := range dst[:n] {
+		dst[i] = float64(r.pos + i)
+	}
+	r.pos += n
+	return n, nil
+}
+
+func TestSlidingWindow(t *testing.T) {
+	type window struct{ start, length int }
+	tests := []struct {
+		name        string
+		total       int
+		window, hop int
+		want        []window
+	}{
+		{"overlapping", 25, 10, 5, []window{{0, 10}, {5, 10}, {10, 10}, {15, 10}}},
+		{"last window shorter", 27, 10, 5, []window{{0, 10}, {5, 10}, {10, 10}, {15, 10}, {20, 7}}},
+		{"recording shorter than a window", 4, 10, 5, []window{{0, 4}}},
+		{"adjacent", 20, 10, 10, []window{{0, 10}, {10, 10}}},
+		{"gaps", 33, 10, 15, []window{{0, 10}, {15, 10}, {30, 3}}},
+		{"gap past the end", 22, 10, 25, []window{{0, 10}}},
+		{"empty", 0, 10, 5, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &slidingWindow{reader: &countingReader{total: tt.total, chunks: []int{3, 7, 1}}, window: tt.window, hop: tt.hop}
+			var got []window
+			for {
+				samples, start, ok, err := w.next()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					break
+				}
+				for i, v := range samples {
+					if v != float64(start+i) {
+						t.Fatalf("window at %d holds sample %v at %d", start, v, i)
+					}
+				}
+				if cap(samples) > tt.window {
+					t.Fatalf("window buffer holds %d samples, want at most %d", cap(samples), tt.window)
+				}
+				got = append(got, window{start, len(samples)})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("windows = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("windows = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCanMerge(t *testing.T) {
+	opts := DefaultTimelineOptions()
+	segment := Segment{Start: 10, End: 20, SongID: 1, ReferenceOffset: 30000}
+	tests := []struct {
+		name        string
+		match       Match
+		windowStart float64
+		want        bool
+	}{
+		{"continues", Match{SongID: 1, Offset: 35000}, 15, true},
+		{"drift within tolerance", Match{SongID: 1, Offset: 37000}, 15, true},
+		{"drift beyond tolerance", Match{SongID: 1, Offset: 37001}, 15, false},
+		{"earlier part of the song", Match{SongID: 1, Offset: 20000}, 15, false},
+		{"other song", Match{SongID: 2, Offset: 35000}, 15, false},
+		{"gap within limit", Match{SongID: 1, Offset: 50000}, 30, true},
+		{"gap too long", Match{SongID: 1, Offset: 50500}, 30.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canMerge(segment, tt.match, tt.windowStart, opts); got != tt.want {
+				t.Fatalf("canMerge = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimelineBuilder(t *testing.T) {
+	type window struct {
+		match      Match
+		start, end float64
+	}
+	tests := []struct {
+		name    string
+		windows []window
+		want    []Segment
+	}{
+		{
+			"merged windows average the confidence",
+			[]window{
+				{Match{SongID: 1, Offset: 1000, Score: 0.4}, 0, 10},
+				{Match{SongID: 1, Offset: 6000, Score: 0.6}, 5, 15},
+				{Match{SongID: 1, Offset: 11000, Score: 0.8}, 10, 20},
+			},
+			[]Segment{{Start: 0, End: 20, SongID: 1, ReferenceOffset: 1000, Confidence: 0.6}},
+		},
+		{
+			"overlap with another song is split in half",
+			[]window{
+				{Match{SongID: 1, Offset: 0, Score: 0.5}, 0, 10},
+				{Match{SongID: 2, Offset: 4000, Score: 0.5}, 5, 15},
+			},
+			[]Segment{
+				{Start: 0, End: 7.5, SongID: 1, ReferenceOffset: 0, Confidence: 0.5},
+				{Start: 7.5, End: 15, SongID: 2, ReferenceOffset: 6500, Confidence: 0.5},
+			},
+		},
+		{
+			"jump in the same song starts a segment",
+			[]window{
+				{Match{SongID: 1, Offset: 0, Score: 0.5}, 0, 10},
+				{Match{SongID: 1, Offset: 60000, Score: 0.5}, 5, 15},
+			},
+			[]Segment{
+				{Start: 0, End: 7.5, SongID: 1, ReferenceOffset: 0, Confidence: 0.5},
+				{Start: 7.5, End: 15, SongID: 1, ReferenceOffset: 62500, Confidence: 0.5},
+			},
+		},
+		{
+			"unknown audio between songs keeps both boundaries",
+			[]window{
+				{Match{SongID: 1, Offset: 0, Score: 0.5}, 0, 10},
+				{Match{SongID: 2, Offset: 0, Score: 0.5}, 20, 30},
+			},
+			[]Segment{
+				{Start: 0, End: 10, SongID: 1, ReferenceOffset: 0, Confidence: 0.5},
+				{Start: 20, End: 30, SongID: 2, ReferenceOffset: 0, Confidence: 0.5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := timelineBuilder{opts: DefaultTimelineOptions()}
+			for _, w := range tt.windows {
+				builder.add(w.match, w.start, w.end)
+			}
+			if len(builder.segments) != len(tt.want) {
+				t.Fatalf("segments = %+v, want %+v", builder.segments, tt.want)
+			}
+			for i, got := range builder.segments {
+				want := tt.want[i]
+				if math.Abs(got.Confidence-want.Confidence) > 1e-9 {
+					t.Fatalf("segment %d confidence = %v, want %v", i, got.Confidence, want.Confidence)
+				}
+				got.Confidence = want.Confidence
+				if got != want {
+					t.Fatalf("segment %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// writeWAV writes mono s16le PCM as a WAV file
+func writeWAV(t *testing.T, pcm []byte, sampleRate int) string {
+	t.Helper()
+	header := make([]byte, 44)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+len(pcm)))
+	copy(header[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:], 1) // Mono
+	binary.LittleEndian.PutUint32(header[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(2*sampleRate))
+	binary.LittleEndian.PutUint16(header[32:], 2)
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], uint32(len(pcm)))
+
+	path := filepath.Join(t.TempDir(), "recording.wav")
+	if err := os.WriteFile(path, append(header, pcm...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRecognizeTimeline(t *testing.T) {
+	// The recording is the song from 3 seconds on
+	pcm, samples := eurekatest.ChirpPCM(t, 20)
+	catalog := &eurekatest.Catalog{}
+	catalog.Add(mysql.SongInfo{ID: 1, Name: "Chirp"}, eurekatest.Fingerprints(t, samples), 3000)
+
+	e := NewEurekaWithDatabase(config.Default(), catalog)
+	segments, err := e.RecognizeTimeline(context.Background(), writeWAV(t, pcm, eurekatest.SampleRate), DefaultTimelineOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("segments = %+v, want one", segments)
+	}
+	got := segments[0]
+	if got.SongID != 1 || got.Start != 0 || got.End != 20 {
+		t.Fatalf("segment = %+v, want song 1 from 0 to 20 seconds", got)
+	}
+	if drift := got.ReferenceOffset - 3000; drift < -100 || drift > 100 {
+		t.Fatalf("reference offset = %d ms, want 3000", got.ReferenceOffset)
+	}
+}
@@ -0,0 +1,50 @@
+package eureka
+
+import (
+	"context"
+
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer returns the tracer of the recognition stages. It is looked up on each use, so
+// that spans go to the global tracer provider currently installed, which records
+// nothing until eureka serve or the application installs one.
+func tracer() trace.Tracer {
+	return otel.Tracer("github.com/media-luna/eureka/internal/eureka")
+}
+
+// Span attributes of the recognition stages
+const (
+	attrSampleRate = attribute.Key("eureka.sample_rate")
+	attrSamples    = attribute.Key("eureka.samples")
+	attrPeaks      = attribute.Key("eureka.peaks")
+	attrHashes     = attribute.Key("eureka.hashes")
+	attrPrints     = attribute.Key("eureka.fingerprints")
+	attrStopListed = attribute.Key("eureka.stop_listed_hashes")
+	attrBatches    = attribute.Key("eureka.batches")
+	attrBatch      = attribute.Key("eureka.batch")
+	attrRows       = attribute.Key("eureka.db_rows")
+	attrMatches    = attribute.Key("eureka.matches")
+	attrSongID     = attribute.Key("eureka.song_id")
+)
+
+// endSpan ends span, marking it as failed when err is not nil
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// getSongByID looks up the information of a matched song in a span of its own
+func (e *Eureka) getSongByID(ctx context.Context, songID int) (mysql.SongInfo, error) {
+	ctx, span := tracer().Start(ctx, "GetSongByID", trace.WithAttributes(attrSongID.Int(songID)))
+	info, err := e.database.GetSongByID(ctx, songID)
+	endSpan(span, err)
+	return info, err
+}
@@ -0,0 +1,105 @@
+package eureka
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eurekatest"
+	"github.com/media-luna/eureka/internal/metrics"
+)
+
+// intAttribute returns the value of an integer attribute of span
+func intAttribute(t *testing.T, span sdktrace.ReadOnlySpan, key attribute.Key) int64 {
+	t.Helper()
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.AsInt64()
+		}
+	}
+	t.Fatalf("span %s has no %s attribute", span.Name(), key)
+	return 0
+}
+
+func TestRecognizeSpans(t *testing.T) {
+	recorder := eurekatest.RecordSpans(t)
+
+	// The catalog holds the fingerprints of the same audio
+	pcm, samples := eurekatest.ChirpPCM(t, 8)
+	fingerprints := eurekatest.Fingerprints(t, samples)
+	hashes := make(map[string]bool)
+	for _, fp := range fingerprints {
+		hashes[fp.Hash] = true
+	}
+	catalog := &eurekatest.Catalog{}
+	catalog.Add(mysql.SongInfo{ID: 1, Name: "Chirp"}, fingerprints, 0)
+
+	e := NewEurekaWithDatabase(config.Default(), catalog)
+	matches, err := e.RecognizePCM(context.Background(), bytes.NewReader(pcm), eurekatest.Format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].SongID != 1 {
+		t.Fatalf("matches = %+v, want song 1", matches)
+	}
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	only := func(name string) sdktrace.ReadOnlySpan {
+		t.Helper()
+		if len(spans[name]) != 1 {
+			t.Fatalf("%d %s spans, want 1", len(spans[name]), name)
+		}
+		return spans[name][0]
+	}
+	childOf := func(span, parent sdktrace.ReadOnlySpan) {
+		t.Helper()
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() || span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+			t.Fatalf("span %s is not a child of %s", span.Name(), parent.Name())
+		}
+	}
+
+	root := only("RecognizePCM")
+	if root.Parent().IsValid() {
+		t.Fatal("RecognizePCM has a parent")
+	}
+	stages := make(map[string]sdktrace.ReadOnlySpan)
+	for _, stage := range []string{metrics.StageDecode, metrics.StageSpectrogram, metrics.StagePeaks, metrics.StageQuery, metrics.StageScoring} {
+		stages[stage] = only(stage)
+		childOf(stages[stage], root)
+	}
+
+	peaks := intAttribute(t, stages[metrics.StagePeaks], attrPeaks)
+	if peaks == 0 || intAttribute(t, root, attrPeaks) != peaks {
+		t.Fatalf("peaks = %d, recognition peaks = %d", peaks, intAttribute(t, root, attrPeaks))
+	}
+
+	query := stages[metrics.StageQuery]
+	if queried := intAttribute(t, query, attrHashes); queried != int64(len(hashes)) {
+		t.Fatalf("db_query hashes = %d, want %d", queried, len(hashes))
+	}
+	batches := spans["QueryFingerprints"]
+	if int64(len(batches)) != intAttribute(t, query, attrBatches) || len(batches) == 0 {
+		t.Fatalf("%d QueryFingerprints spans, db_query has %d batches", len(batches), intAttribute(t, query, attrBatches))
+	}
+	var rows int64
+	for _, batch := range batches {
+		childOf(batch, query)
+		rows += intAttribute(t, batch, attrRows)
+	}
+	if total := intAttribute(t, query, attrRows); total != rows || total != int64(len(hashes)) {
+		t.Fatalf("db_query rows = %d, batches = %d, want %d", total, rows, len(hashes))
+	}
+
+	childOf(only("GetSongByID"), stages[metrics.StageScoring])
+	if n := intAttribute(t, stages[metrics.StageScoring], attrMatches); n != 1 {
+		t.Fatalf("scoring matches = %d, want 1", n)
+	}
+}
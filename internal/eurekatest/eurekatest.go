@@ -0,0 +1,128 @@
+// Package eurekatest provides fixtures for the tests of the recognition packages: a
+// synthetic song, an in-memory catalog and a span recorder.
+package eurekatest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"sort"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/database"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/fingerprint"
+)
+
+// SampleRate is the rate of the synthetic audio, the one the fingerprints are computed at
+const SampleRate = fingerprint.SAMPLE_RATE
+
+// Format is the PCM format of ChirpPCM
+var Format = audio.PCMFormat{Encoding: "s16le", SampleRate: SampleRate, Channels: 1}
+
+// ChirpPCM returns seconds of a rising two-tone chirp as mono s16le PCM, and its
+// samples as the recognition decodes them
+func ChirpPCM(t testing.TB, seconds int) ([]byte, []float64) {
+	t.Helper()
+	pcm := make([]byte, 2*seconds*SampleRate)
+	for i := 0; i < seconds*SampleRate; i++ {
+		t := float64(i) / SampleRate
+		f := 300 + 2000*float64(i%SampleRate)/SampleRate + 500*float64(i/SampleRate)
+		v := int16(12000 * (math.Sin(2*math.Pi*f*t) + 0.3*math.Sin(2*math.Pi*1.7*f*t)))
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(v))
+	}
+
+	samples, err := fingerprint.DecodePCMReader(bytes.NewReader(pcm), Format, seconds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pcm, samples
+}
+
+// Fingerprints returns the fingerprints of samples at SampleRate with the batch
+// pipeline used to ingest songs
+func Fingerprints(t testing.TB, samples []float64) []fingerprint.Fingerprint {
+	t.Helper()
+	spectrogram, err := fingerprint.SamplesToSpectrogram(samples, SampleRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fingerprint.GenerateFingerprints(fingerprint.PickPeaks(spectrogram, SampleRate))
+}
+
+// Catalog is an in-memory database of songs given by their fingerprints. Methods not
+// needed by recognition panic through the nil embedded Database. It must not be
+// changed while in use.
+type Catalog struct {
+	database.Database
+	songs map[int]catalogSong
+}
+
+type catalogSong struct {
+	info    mysql.SongInfo
+	offsets map[string]int
+}
+
+// Add stores a song holding fingerprints, which start shiftMs milliseconds into it
+func (c *Catalog) Add(info mysql.SongInfo, fingerprints []fingerprint.Fingerprint, shiftMs int) {
+	if c.songs == nil {
+		c.songs = make(map[int]catalogSong)
+	}
+	song := catalogSong{info: info, offsets: make(map[string]int, len(fingerprints))}
+	for _, fp := range fingerprints {
+		song.offsets[fp.Hash] = fp.Offset + shiftMs
+	}
+	c.songs[info.ID] = song
+}
+
+// QueryFingerprints returns the stored fingerprints of hashes, by song ID
+func (c *Catalog) QueryFingerprints(ctx context.Context, hashes []string) ([]mysql.FingerprintMatch, error) {
+	ids := make([]int, 0, len(c.songs))
+	for id := range c.songs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var matches []mysql.FingerprintMatch
+	for _, id := range ids {
+		for _, hash := range hashes {
+			if offset, ok := c.songs[id].offsets[hash]; ok {
+				matches = append(matches, mysql.FingerprintMatch{Hash: hash, SongID: id, Offset: offset})
+			}
+		}
+	}
+	return matches, nil
+}
+
+// GetSongByID returns the information of a stored song
+func (c *Catalog) GetSongByID(ctx context.Context, songID int) (mysql.SongInfo, error) {
+	song, ok := c.songs[songID]
+	if !ok {
+		return mysql.SongInfo{}, mysql.ErrNotFound
+	}
+	return song.info, nil
+}
+
+// RecordSpans installs a tracer provider recording the spans, and the W3C trace
+// context propagator, until the end of the test
+func RecordSpans(t testing.TB) *tracetest.SpanRecorder {
+	previous, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(propagator)
+	})
+	return recorder
+}
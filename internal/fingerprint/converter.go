@@ -0,0 +1,43 @@
+package fingerprint
+
+// Converter turns interleaved audio at any rate into the mono SAMPLE_RATE samples
+// the fingerprinting pipeline expects. It reuses its buffers between calls.
+type Converter struct {
+	channels  int
+	resampler *Resampler // nil when the input already is at SAMPLE_RATE
+	mono      []float32
+	resampled []float32
+	samples   []float64
+}
+
+// NewConverter creates a converter for interleaved audio at sampleRate with channels
+func NewConverter(sampleRate, channels int) *Converter {
+	c := &Converter{channels: channels}
+	if sampleRate != SAMPLE_RATE {
+		c.resampler = NewResampler(sampleRate, SAMPLE_RATE)
+	}
+	return c
+}
+
+// Convert downmixes and resamples whole frames of interleaved. The returned slice
+// is reused by the next call.
+func (c *Converter) Convert(interleaved []float32) []float64 {
+	frames := len(interleaved) / c.channels
+	if cap(c.mono) < frames {
+		c.mono = make([]float32, frames)
+	}
+	mono := Downmix(c.mono[:frames], interleaved[:frames*c.channels], c.channels)
+
+	if c.resampler != nil {
+		if max := c.resampler.MaxOutput(len(mono)); cap(c.resampled) < max {
+			c.resampled = make([]float32, 0, max)
+		}
+		mono = c.resampler.Process(c.resampled, mono)
+	}
+
+	c.samples = c.samples[:0]
+	for _, v := range mono {
+		c.samples = append(c.samples, float64(v))
+	}
+	return c.samples
+}
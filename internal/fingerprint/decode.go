@@ -0,0 +1,167 @@
+package fingerprint
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/flac"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/wav"
+
+	"github.com/media-luna/eureka/internal/audio"
+)
+
+// DetectFormat sniffs the container of encoded audio from its first bytes and
+// returns "wav", "flac", "mp3", or an empty string if it is not recognized
+func DetectFormat(header []byte) string {
+	switch {
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return "wav"
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return "flac"
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return "mp3"
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		// MPEG audio frame sync, a raw MP3 without ID3 tag
+		return "mp3"
+	default:
+		return ""
+	}
+}
+
+// Decoder reads an encoded stream (WAV, FLAC or MP3, detected from its content) as
+// mono samples, without holding more of it than asked for
+type Decoder struct {
+	streamer   beep.StreamCloser
+	sampleRate int
+	buf        [][2]float64
+}
+
+// NewDecoder detects the format of r and reads its header
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(12)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading audio stream: %v", err)
+	}
+
+	var streamer beep.StreamCloser
+	var format beep.Format
+
+	switch DetectFormat(header) {
+	case "wav":
+		streamer, format, err = wav.Decode(buffered)
+	case "flac":
+		streamer, format, err = flac.Decode(buffered)
+	case "mp3":
+		streamer, format, err = mp3.Decode(io.NopCloser(buffered))
+	default:
+		return nil, errors.New("unrecognized audio stream format, use raw PCM mode for headerless audio")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding audio stream: %v", err)
+	}
+
+	return &Decoder{streamer: streamer, sampleRate: int(format.SampleRate), buf: make([][2]float64, 4096)}, nil
+}
+
+// SampleRate returns the sample rate of the stream
+func (d *Decoder) SampleRate() int {
+	return d.sampleRate
+}
+
+// Read decodes up to len(dst) mono samples into dst and returns how many it decoded.
+// It returns io.EOF at the end of the stream.
+func (d *Decoder) Read(dst []float64) (int, error) {
+	n, ok := d.streamer.Stream(d.buf[:min(len(dst), len(d.buf))])
+	for i, frame := range d.buf[:n] {
+		// Average left and right channels, mono streams have both set to the same value
+		dst[i] = (frame[0] + frame[1]) / 2
+	}
+	if !ok {
+		if err := d.streamer.Err(); err != nil {
+			return n, fmt.Errorf("error decoding audio stream: %v", err)
+		}
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close releases the decoder, not the underlying reader
+func (d *Decoder) Close() error {
+	return d.streamer.Close()
+}
+
+// DecodeReader decodes an encoded stream (WAV, FLAC or MP3, detected from its
+// content) into mono samples. It stops reading after maxSeconds of audio, 0 reads
+// the whole stream.
+func DecodeReader(r io.Reader, maxSeconds int) ([]float64, int, error) {
+	decoder, err := NewDecoder(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer decoder.Close()
+
+	limit := maxSeconds * decoder.SampleRate()
+
+	var samples []float64
+	buf := make([]float64, 4096)
+	for limit == 0 || len(samples) < limit {
+		n, err := decoder.Read(buf)
+		samples = append(samples, buf[:n]...)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if limit > 0 && len(samples) > limit {
+		samples = samples[:limit]
+	}
+	return samples, decoder.SampleRate(), nil
+}
+
+// DecodePCMReader reads headerless PCM in the given format into mono samples.
+// It stops reading after maxSeconds of audio, 0 reads the whole stream.
+func DecodePCMReader(r io.Reader, format audio.PCMFormat, maxSeconds int) ([]float64, error) {
+	source := audio.NewPCMSource(r, format)
+	if err := source.Start(); err != nil {
+		return nil, err
+	}
+	defer source.Stop()
+
+	limit := maxSeconds * format.SampleRate
+
+	var samples []float64
+	buf := make([]float32, 4096*format.Channels)
+	mono := make([]float32, 4096)
+	var carry int // Samples of an incomplete frame kept at the start of buf
+	for limit == 0 || len(samples) < limit {
+		n, err := source.Read(buf[carry:])
+		n += carry
+
+		frames := n / format.Channels
+		for _, v := range Downmix(mono[:frames], buf[:frames*format.Channels], format.Channels) {
+			samples = append(samples, float64(v))
+		}
+		carry = copy(buf, buf[frames*format.Channels:n])
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	if limit > 0 && len(samples) > limit {
+		samples = samples[:limit]
+	}
+	return samples, nil
+}
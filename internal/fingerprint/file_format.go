@@ -10,6 +10,7 @@ import (
 	"github.com/faiface/beep/flac"
 	"github.com/faiface/beep/mp3"
 	"github.com/faiface/beep/wav"
+	"github.com/media-luna/eureka/utils/logger"
 )
 
 // monoStreamer combines multiple channels into a single mono channel
@@ -92,7 +93,7 @@ func ConvertToWAV(inputPath string, outputPath string) (string, error) {
 		}
 	}
 
-	fmt.Println("Conversion completed:", outputPath)
+	logger.Debug("Conversion completed", "wav", outputPath)
 	return outputPath, nil
 }
 
@@ -24,6 +24,10 @@ const (
 
 	// Maximum peaks per time frame
 	MAX_PEAKS_PER_FRAME = 3
+
+	// Version of the hashing scheme, bump it with any change making new hashes differ
+	// from stored ones so that dumps of older catalogs are refused
+	ALGORITHM_VERSION = 1
 )
 
 // Fingerprint represents a single audio fingerprint
@@ -249,37 +253,8 @@ func generateFingerprintsWithMinimalTolerance(peaks []Peak) []Fingerprint {
 				continue
 			}
 
-			// Generate only ±1 frequency bin tolerance (minimal set)
-			tolerances := [][]int{
-				{-1, 0}, // Anchor -1
-				{1, 0},  // Anchor +1
-				{0, -1}, // Target -1
-				{0, 1},  // Target +1
-			}
-
-			for _, tol := range tolerances {
-				anchorBin := anchor.FreqBin + tol[0]
-				targetBin := target.FreqBin + tol[1]
-
-				// Ensure bins are within reasonable range
-				if anchorBin < 0 || targetBin < 0 || anchorBin > 2048 || targetBin > 2048 {
-					continue
-				}
-
-				hashInput := fmt.Sprintf("%d|%d|%d",
-					anchorBin,
-					targetBin,
-					int(timeDelta))
-
-				hasher := sha1.New()
-				hasher.Write([]byte(hashInput))
-				hashBytes := hasher.Sum(nil)
-				hashStr := hex.EncodeToString(hashBytes)
-
-				fingerprints = append(fingerprints, Fingerprint{
-					Hash:   hashStr,
-					Offset: int(anchor.TimeMS),
-				})
+			for _, fp := range toleranceFingerprints(anchor, target, timeDelta) {
+				fingerprints = append(fingerprints, fp)
 
 				processed++
 				// Limit total tolerance fingerprints to avoid MySQL issues
@@ -310,18 +285,8 @@ func generateFingerprintsWithTolerance(peaks []Peak, microphoneTolerance bool) [
 			}
 
 			// Always use original exact matching now
-			hashInput := fmt.Sprintf("%d|%d|%d",
-				anchor.FreqBin,
-				target.FreqBin,
-				int(timeDelta))
-
-			hasher := sha1.New()
-			hasher.Write([]byte(hashInput))
-			hashBytes := hasher.Sum(nil)
-			hashStr := hex.EncodeToString(hashBytes)
-
 			fingerprints = append(fingerprints, Fingerprint{
-				Hash:   hashStr,
+				Hash:   hashPeakPair(anchor.FreqBin, target.FreqBin, timeDelta),
 				Offset: int(anchor.TimeMS),
 			})
 		}
@@ -329,3 +294,44 @@ func generateFingerprintsWithTolerance(peaks []Peak, microphoneTolerance bool) [
 
 	return fingerprints
 }
+
+// toleranceFingerprints generates the ±1 frequency bin variations of an anchor/target pair
+func toleranceFingerprints(anchor, target Peak, timeDelta float64) []Fingerprint {
+	// Generate only ±1 frequency bin tolerance (minimal set)
+	tolerances := [][]int{
+		{-1, 0}, // Anchor -1
+		{1, 0},  // Anchor +1
+		{0, -1}, // Target -1
+		{0, 1},  // Target +1
+	}
+
+	fingerprints := make([]Fingerprint, 0, len(tolerances))
+	for _, tol := range tolerances {
+		anchorBin := anchor.FreqBin + tol[0]
+		targetBin := target.FreqBin + tol[1]
+
+		// Ensure bins are within reasonable range
+		if anchorBin < 0 || targetBin < 0 || anchorBin > 2048 || targetBin > 2048 {
+			continue
+		}
+
+		fingerprints = append(fingerprints, Fingerprint{
+			Hash:   hashPeakPair(anchorBin, targetBin, timeDelta),
+			Offset: int(anchor.TimeMS),
+		})
+	}
+
+	return fingerprints
+}
+
+// hashPeakPair hashes the frequency bins of two peaks and the time between them
+func hashPeakPair(anchorBin, targetBin int, timeDelta float64) string {
+	hashInput := fmt.Sprintf("%d|%d|%d",
+		anchorBin,
+		targetBin,
+		int(timeDelta))
+
+	hasher := sha1.New()
+	hasher.Write([]byte(hashInput))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
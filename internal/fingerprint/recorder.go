@@ -0,0 +1,192 @@
+package fingerprint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+const (
+	SAMPLE_RATE        = 44100
+	FRAMES_PER_BUFFER  = 1024
+	RECORDING_DURATION = 10 // seconds
+	BUFFER_DURATION    = 5  // seconds for recognition window
+)
+
+// Recorder handles real-time recording from an audio source (microphone, file
+// playback, stdin...). A capture goroutine downmixes the source to mono, resamples
+// it to SAMPLE_RATE and writes it into a lock-free ring buffer, which the recognition
+// loop reads concurrently with ReadSince.
+type Recorder struct {
+	source      audio.Source
+	sampleRate  int
+	channels    int
+	resampler   *Resampler // nil when the source already records at SAMPLE_RATE
+	audioBuffer *RingBuffer
+	isRecording atomic.Bool
+	done        chan struct{}
+	err         error // Set before done is closed
+	wg          sync.WaitGroup
+}
+
+// NewRecorder creates a new recorder reading from source
+func NewRecorder(source audio.Source) (*Recorder, error) {
+	if source.SampleRate() <= 0 || source.Channels() <= 0 {
+		return nil, fmt.Errorf("invalid audio source format: %d Hz, %d channels", source.SampleRate(), source.Channels())
+	}
+
+	r := &Recorder{
+		source:      source,
+		sampleRate:  SAMPLE_RATE,
+		channels:    source.Channels(),
+		audioBuffer: NewRingBuffer(SAMPLE_RATE * RECORDING_DURATION),
+		done:        make(chan struct{}),
+	}
+	if source.SampleRate() != SAMPLE_RATE {
+		r.resampler = NewResampler(source.SampleRate(), SAMPLE_RATE)
+	}
+	return r, nil
+}
+
+// StartRecording starts the source and begins continuous recording
+func (r *Recorder) StartRecording() error {
+	if !r.isRecording.CompareAndSwap(false, true) {
+		return fmt.Errorf("recording is already in progress")
+	}
+
+	if err := r.source.Start(); err != nil {
+		r.isRecording.Store(false)
+		return fmt.Errorf("failed to start audio source: %v", err)
+	}
+
+	r.wg.Add(1)
+	go r.capture()
+
+	logger.Info("Recording started, press Ctrl+C to stop", "sample_rate", r.source.SampleRate(), "channels", r.source.Channels())
+	return nil
+}
+
+// capture reads the source until it ends or is stopped
+func (r *Recorder) capture() {
+	defer r.wg.Done()
+	defer close(r.done)
+
+	buf := make([]float32, FRAMES_PER_BUFFER*r.channels)
+	mono := make([]float32, FRAMES_PER_BUFFER)
+	var resampled []float32
+	if r.resampler != nil {
+		resampled = make([]float32, 0, r.resampler.MaxOutput(FRAMES_PER_BUFFER))
+	}
+	var carry int // Samples of an incomplete frame kept at the start of buf
+
+	for {
+		n, err := r.source.Read(buf[carry:])
+		n += carry
+
+		frames := n / r.channels
+		if frames > 0 {
+			samples := Downmix(mono[:frames], buf[:frames*r.channels], r.channels)
+			if r.resampler != nil {
+				samples = r.resampler.Process(resampled, samples)
+			}
+			r.audioCallback(samples)
+		}
+		carry = copy(buf, buf[frames*r.channels:n])
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				r.err = err
+			}
+			return
+		}
+	}
+}
+
+// Downmix averages interleaved frames of src into dst, one sample per frame.
+// Mono input is returned as is.
+func Downmix(dst, src []float32, channels int) []float32 {
+	if channels == 1 {
+		return src
+	}
+	for i := range dst {
+		var sum float32
+		for c := 0; c < channels; c++ {
+			sum += src[i*channels+c]
+		}
+		dst[i] = sum / float32(channels)
+	}
+	return dst
+}
+
+// audioCallback processes incoming mono audio on the capture goroutine.
+// It only copies into the ring buffer and never allocates or blocks.
+func (r *Recorder) audioCallback(in []float32) {
+	if len(in) == 0 {
+		return
+	}
+
+	// Add incoming audio to buffer, the oldest audio beyond 10 seconds is overwritten
+	r.audioBuffer.Write(in)
+}
+
+// StopRecording stops the source and waits for the capture goroutine
+func (r *Recorder) StopRecording() error {
+	if !r.isRecording.CompareAndSwap(true, false) {
+		return fmt.Errorf("no recording in progress")
+	}
+
+	err := r.source.Stop()
+	r.wg.Wait()
+
+	logger.Debug("Recording stopped")
+	if err != nil {
+		return fmt.Errorf("failed to stop audio source: %v", err)
+	}
+	return nil
+}
+
+// Done is closed when the source ends on its own or recording is stopped
+func (r *Recorder) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns the error that ended the source, if any. Only valid once Done is closed.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+// Cleanup stops recording if needed
+func (r *Recorder) Cleanup() error {
+	if r.isRecording.Load() {
+		r.StopRecording()
+	}
+	return nil
+}
+
+// SampleRate returns the sample rate of the recorded audio, after resampling
+func (r *Recorder) SampleRate() int {
+	return r.sampleRate
+}
+
+// GetAudioBuffer returns a copy of the last 5 seconds of audio (or less, if not
+// recorded yet) for external processing. It is safe to call while recording.
+func (r *Recorder) GetAudioBuffer() []float64 {
+	return r.audioBuffer.Snapshot(nil, r.sampleRate*BUFFER_DURATION)
+}
+
+// ReadSince returns the samples received after position pos, and the position to
+// pass to the next call. Samples that already left the buffer are skipped.
+// It is safe to call while recording.
+func (r *Recorder) ReadSince(pos int64) ([]float64, int64) {
+	return r.audioBuffer.ReadSince(nil, pos)
+}
+
+// IsRecording returns true if currently recording
+func (r *Recorder) IsRecording() bool {
+	return r.isRecording.Load()
+}
@@ -0,0 +1,138 @@
+package fingerprint
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/media-luna/eureka/internal/audio"
+)
+
+// positionSource is a stereo source at SAMPLE_RATE whose samples hold their frame
+// number, so that the recorded audio can be checked without resampling
+func positionSource(seconds float64, realtime bool) *audio.SyntheticSource {
+	return audio.NewSyntheticSource(SAMPLE_RATE, 2, seconds, realtime, func(t float64) float64 {
+		return math.Round(t * SAMPLE_RATE)
+	})
+}
+
+func TestRecorderReadWhileRecording(t *testing.T) {
+	recorder, err := NewRecorder(positionSource(30, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recorder.Cleanup()
+
+	if err := recorder.StartRecording(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for reader := 0; reader < 3; reader++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var pos int64
+			for {
+				select {
+				case <-recorder.Done():
+					return
+				default:
+				}
+
+				var samples []float64
+				samples, pos = recorder.ReadSince(pos)
+				if err := contiguous(samples, pos-int64(len(samples))); err != nil {
+					t.Error(err)
+					return
+				}
+				if buffered := recorder.GetAudioBuffer(); len(buffered) > SAMPLE_RATE*BUFFER_DURATION {
+					t.Errorf("GetAudioBuffer returned %d samples", len(buffered))
+					return
+				}
+				recorder.IsRecording()
+			}
+		}()
+	}
+
+	select {
+	case <-recorder.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("the source did not end")
+	}
+	wg.Wait()
+
+	if err := recorder.Err(); err != nil {
+		t.Fatal(err)
+	}
+	samples, end := recorder.ReadSince(0)
+	if end != 30*SAMPLE_RATE {
+		t.Fatalf("recorded %d samples, want %d", end, 30*SAMPLE_RATE)
+	}
+	if err := contiguous(samples, end-int64(len(samples))); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecorderStartStopConcurrently(t *testing.T) {
+	recorder, err := NewRecorder(positionSource(0, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recorder.Cleanup()
+
+	// Exactly one of several concurrent calls starts, then stops, the recording
+	count := func(call func() error) int {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeeded := 0
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if call() == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		return succeeded
+	}
+
+	if started := count(recorder.StartRecording); started != 1 {
+		t.Fatalf("%d calls started the recording, want 1", started)
+	}
+
+	// Read while the endless source is captured in real time
+	deadline := time.Now().Add(300 * time.Millisecond)
+	var pos int64
+	for time.Now().Before(deadline) {
+		var samples []float64
+		samples, pos = recorder.ReadSince(pos)
+		if err := contiguous(samples, pos-int64(len(samples))); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if pos == 0 {
+		t.Fatal("no audio was recorded")
+	}
+
+	if stopped := count(recorder.StopRecording); stopped != 1 {
+		t.Fatalf("%d calls stopped the recording, want 1", stopped)
+	}
+	select {
+	case <-recorder.Done():
+	default:
+		t.Fatal("Done is not closed after StopRecording")
+	}
+	if recorder.IsRecording() {
+		t.Fatal("still recording after StopRecording")
+	}
+}
@@ -0,0 +1,56 @@
+package fingerprint
+
+import "math"
+
+// Resampler converts a mono stream between sample rates with linear interpolation.
+// It keeps the last input sample between calls, so chunks can be of any size and
+// the output is continuous across them.
+type Resampler struct {
+	step   float64 // Input samples per output sample
+	pos    float64 // Position of the next output sample, -1 is the last sample of the previous chunk
+	last   float32
+	primed bool
+}
+
+// NewResampler creates a resampler from fromRate to toRate
+func NewResampler(fromRate, toRate int) *Resampler {
+	return &Resampler{step: float64(fromRate) / float64(toRate)}
+}
+
+// MaxOutput returns the most samples Process can produce for n input samples
+func (r *Resampler) MaxOutput(n int) int {
+	return int(float64(n)/r.step) + 2
+}
+
+// Process resamples src and appends the result to dst[:0]. dst should have a
+// capacity of at least MaxOutput(len(src)) to avoid allocating.
+func (r *Resampler) Process(dst, src []float32) []float32 {
+	dst = dst[:0]
+	if len(src) == 0 {
+		return dst
+	}
+	if !r.primed {
+		r.last = src[0]
+		r.primed = true
+	}
+
+	n := len(src)
+	for r.pos < float64(n-1) {
+		i := int(math.Floor(r.pos))
+		frac := float32(r.pos - float64(i))
+
+		a := r.last
+		if i >= 0 {
+			a = src[i]
+		}
+		b := src[i+1]
+
+		dst = append(dst, a+(b-a)*frac)
+		r.pos += r.step
+	}
+
+	// Make positions relative to the next chunk
+	r.pos -= float64(n)
+	r.last = src[n-1]
+	return dst
+}
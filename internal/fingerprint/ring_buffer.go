@@ -0,0 +1,96 @@
+package fingerprint
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// RingBuffer keeps the most recent samples of an audio stream in a fixed amount
+// of memory. One writer (typically the audio callback) and any number of readers
+// can use it concurrently without locks: every slot is read and written atomically,
+// and readers discard the samples that the writer overwrote while they were copying.
+type RingBuffer struct {
+	slots    []atomic.Uint32 // float32 samples stored as their bit patterns
+	reserved atomic.Int64    // Samples claimed by the writer, including those being written
+	written  atomic.Int64    // Samples completely written and visible to readers
+}
+
+// NewRingBuffer creates a ring buffer holding up to capacity samples
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{slots: make([]atomic.Uint32, capacity)}
+}
+
+// Capacity returns the maximum number of samples the buffer holds
+func (r *RingBuffer) Capacity() int {
+	return len(r.slots)
+}
+
+// Written returns the total number of samples written since the buffer was created
+func (r *RingBuffer) Written() int64 {
+	return r.written.Load()
+}
+
+// Write appends samples, overwriting the oldest ones when the buffer is full.
+// It never allocates. Only one goroutine may call Write at a time.
+func (r *RingBuffer) Write(samples []float32) {
+	capacity := int64(len(r.slots))
+	if int64(len(samples)) > capacity {
+		// Only the newest samples fit
+		skipped := int64(len(samples)) - capacity
+		r.reserved.Add(skipped)
+		r.written.Add(skipped)
+		samples = samples[skipped:]
+	}
+
+	start := r.written.Load()
+	r.reserved.Store(start + int64(len(samples)))
+	for i, sample := range samples {
+		r.slots[(start+int64(i))%capacity].Store(math.Float32bits(sample))
+	}
+	r.written.Store(start + int64(len(samples)))
+}
+
+// Snapshot appends the most recent n samples (or fewer, if not available yet) to dst
+func (r *RingBuffer) Snapshot(dst []float64, n int) []float64 {
+	end := r.written.Load()
+	start := end - int64(n)
+	if start < 0 {
+		start = 0
+	}
+	return r.copyRange(dst, start, end)
+}
+
+// ReadSince appends the samples written after position pos to dst and returns the
+// position to pass to the next call. Samples that were already overwritten are skipped.
+func (r *RingBuffer) ReadSince(dst []float64, pos int64) ([]float64, int64) {
+	end := r.written.Load()
+	if pos > end {
+		pos = end
+	}
+	return r.copyRange(dst, pos, end), end
+}
+
+// copyRange copies the samples at absolute positions [start, end) to dst,
+// dropping the prefix that is no longer or not reliably in the buffer
+func (r *RingBuffer) copyRange(dst []float64, start, end int64) []float64 {
+	capacity := int64(len(r.slots))
+	if oldest := end - capacity; start < oldest {
+		start = oldest
+	}
+
+	base := len(dst)
+	for i := start; i < end; i++ {
+		dst = append(dst, float64(math.Float32frombits(r.slots[i%capacity].Load())))
+	}
+
+	// The writer may have wrapped around while we were copying
+	if oldest := r.reserved.Load() - capacity; start < oldest {
+		stale := oldest - start
+		if stale > end-start {
+			stale = end - start
+		}
+		dst = append(dst[:base], dst[base+int(stale):]...)
+	}
+
+	return dst
+}
@@ -0,0 +1,112 @@
+package fingerprint
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// contiguous returns an error unless samples hold the positions first, first+1, ...
+func contiguous(samples []float64, first int64) error {
+	for i, sample := range samples {
+		if want := float64(first + int64(i)); sample != want {
+			return fmt.Errorf("sample %d = %v, want %v", i, sample, want)
+		}
+	}
+	return nil
+}
+
+func TestRingBufferConcurrentReaders(t *testing.T) {
+	const (
+		capacity = 257 // Not a multiple of the write size, so writes wrap mid-chunk
+		total    = 200_000
+		chunk    = 100
+	)
+	buffer := NewRingBuffer(capacity)
+
+	// Each sample holds its own position, so readers can check what they got
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		samples := make([]float32, chunk)
+		for pos := 0; pos < total; pos += chunk {
+			for i := range samples {
+				samples[i] = float32(pos + i)
+			}
+			buffer.Write(samples)
+		}
+	}()
+
+	for reader := 0; reader < 4; reader++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var pos int64
+			var dst []float64
+			for {
+				finished := false
+				select {
+				case <-done:
+					finished = true
+				default:
+				}
+
+				var end int64
+				dst, end = buffer.ReadSince(dst[:0], pos)
+				if end < pos {
+					t.Errorf("ReadSince went back from %d to %d", pos, end)
+					return
+				}
+				// Overwritten samples are skipped, the rest ends at the returned position
+				if err := contiguous(dst, end-int64(len(dst))); err != nil {
+					t.Error(err)
+					return
+				}
+				if int64(len(dst)) > end-pos || len(dst) > capacity {
+					t.Errorf("ReadSince(%d) returned %d samples up to %d", pos, len(dst), end)
+					return
+				}
+				pos = end
+
+				snapshot := buffer.Snapshot(nil, capacity/2)
+				if len(snapshot) > 0 {
+					if err := contiguous(snapshot, int64(snapshot[0])); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+
+				if finished {
+					if pos != total {
+						t.Errorf("reader stopped at %d, want %d", pos, total)
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if got := buffer.Written(); got != total {
+		t.Fatalf("Written() = %d, want %d", got, total)
+	}
+}
+
+func TestRingBufferOversizedWrite(t *testing.T) {
+	buffer := NewRingBuffer(4)
+	buffer.Write([]float32{0, 1, 2, 3, 4, 5})
+
+	samples, end := buffer.ReadSince(nil, 0)
+	if end != 6 {
+		t.Fatalf("end = %d, want 6", end)
+	}
+	if len(samples) != 4 {
+		t.Fatalf("got %d samples, want the newest 4", len(samples))
+	}
+	if err := contiguous(samples, 2); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/maddyblue/go-dsp/fft"
 	"github.com/maddyblue/go-dsp/window"
+	"github.com/media-luna/eureka/utils/logger"
 )
 
 // Spectrogram computes the spectrogram of a WAV file using proper STFT.
@@ -98,18 +99,16 @@ func SpectrogramToImage(spectrogram [][]complex128, peaks []Peak, sampleRate int
 	// Save file
 	f, err := os.Create(path)
 	if err != nil {
-		fmt.Println(err)
-		return err
+		return fmt.Errorf("error creating image file: %v", err)
 	}
 	defer f.Close()
 
 	err = png.Encode(f, img)
 	if err != nil {
-		fmt.Println("Error encoding image:", err)
-		return err
+		return fmt.Errorf("error encoding image: %v", err)
 	}
 
-	fmt.Println("Spectrogram image saved to spectrogram.png")
+	logger.Debug("Spectrogram image saved", "path", path)
 
 	return nil
 }
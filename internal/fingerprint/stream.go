@@ -0,0 +1,193 @@
+package fingerprint
+
+import (
+	"math/cmplx"
+
+	"github.com/maddyblue/go-dsp/fft"
+	"github.com/maddyblue/go-dsp/window"
+)
+
+// StreamFingerprinter fingerprints audio incrementally as it arrives. Every call to
+// Write only analyzes the STFT frames completed by the new samples, and produces the
+// same hashes and offsets as the batch pipeline (SamplesToSpectrogram, PickPeaks and
+// GenerateFingerprints) would for the whole stream so far.
+type StreamFingerprinter struct {
+	sampleRate int
+	microphone bool
+	hopSize    int
+	hamming    []float64
+	bands      []FrequencyBand
+
+	raw     []float64 // Raw samples still needed by upcoming frames
+	rawBase int64     // Absolute index of raw[0]
+
+	nextFrame  int64       // Absolute index of the next STFT frame to compute
+	recent     [][]float64 // Magnitudes of the last frames, needed to confirm local peaks
+	recentBase int64       // Absolute frame index of recent[0]
+
+	peaks     []Peak // Last peaks that can still anchor a fingerprint
+	peakCount int64  // Number of peaks found so far
+}
+
+// NewStreamFingerprinter creates a streaming fingerprinter for mono audio at sampleRate.
+// With microphone set, it also emits the tolerance hashes of GenerateFingerprintsForMicrophone.
+func NewStreamFingerprinter(sampleRate int, microphone bool) *StreamFingerprinter {
+	return &StreamFingerprinter{
+		sampleRate: sampleRate,
+		microphone: microphone,
+		hopSize:    WINDOW_SIZE / 4, // Same as used in spectrogram generation
+		hamming:    window.Hamming(WINDOW_SIZE),
+		bands:      getFrequencyBands(sampleRate, WINDOW_SIZE),
+	}
+}
+
+// Write appends samples to the stream and returns the fingerprints they completed.
+// Offsets are milliseconds from the start of the stream.
+func (s *StreamFingerprinter) Write(samples []float64) []Fingerprint {
+	s.raw = append(s.raw, samples...)
+
+	var fingerprints []Fingerprint
+	halfWindow := int64(WINDOW_SIZE / 2)
+
+	// A frame needs the low-pass filter output for all its samples, and the filter
+	// looks half a window ahead of each sample
+	for s.nextFrame*int64(s.hopSize)+int64(WINDOW_SIZE)+halfWindow <= s.rawBase+int64(len(s.raw)) {
+		magnitudes := s.computeFrame(s.nextFrame)
+		s.recent = append(s.recent, magnitudes)
+		s.nextFrame++
+
+		// The previous frame now has both neighbors and its peaks are final
+		if s.nextFrame >= 2 {
+			for _, peak := range s.framePeaks(s.nextFrame - 2) {
+				fingerprints = append(fingerprints, s.addPeak(peak)...)
+			}
+		}
+
+		// Keep only the frames needed for the next local peak check
+		if len(s.recent) > 3 {
+			s.recent = s.recent[len(s.recent)-3:]
+			s.recentBase = s.nextFrame - 3
+		}
+
+		// Drop raw samples the next frame's filter no longer reaches
+		keepFrom := s.nextFrame*int64(s.hopSize) - halfWindow
+		if keepFrom > s.rawBase {
+			drop := keepFrom - s.rawBase
+			s.raw = append(s.raw[:0], s.raw[drop:]...)
+			s.rawBase = keepFrom
+		}
+	}
+
+	return fingerprints
+}
+
+// Seconds returns the amount of audio that has been fully analyzed
+func (s *StreamFingerprinter) Seconds() float64 {
+	if s.nextFrame == 0 {
+		return 0
+	}
+	return float64((s.nextFrame-1)*int64(s.hopSize)) / float64(s.sampleRate)
+}
+
+// computeFrame low-pass filters, windows and transforms a single STFT frame
+func (s *StreamFingerprinter) computeFrame(frameIndex int64) []float64 {
+	start := frameIndex * int64(s.hopSize)
+	halfWindow := int64(WINDOW_SIZE / 2)
+
+	// Moving average with the same edges as lowPassFilter: the first half window is zero
+	frame := make([]float64, WINDOW_SIZE)
+	var sum float64
+	summing := false
+	for i := int64(0); i < WINDOW_SIZE; i++ {
+		abs := start + i
+		if abs < halfWindow {
+			continue
+		}
+		if !summing {
+			for j := abs - halfWindow; j <= abs+halfWindow; j++ {
+				sum += s.raw[j-s.rawBase]
+			}
+			summing = true
+		} else {
+			sum += s.raw[abs+halfWindow-s.rawBase] - s.raw[abs-halfWindow-1-s.rawBase]
+		}
+		frame[i] = sum / float64(WINDOW_SIZE) * s.hamming[i]
+	}
+
+	fftOut := fft.FFTReal(frame)
+	halfSize := len(fftOut) / 2
+
+	magnitudes := make([]float64, halfSize)
+	for i, val := range fftOut[:halfSize] {
+		magnitudes[i] = cmplx.Abs(val)
+	}
+	return magnitudes
+}
+
+// framePeaks picks the band peaks of a frame whose neighbors have been computed
+func (s *StreamFingerprinter) framePeaks(frameIndex int64) []Peak {
+	t := int(frameIndex - s.recentBase)
+	frame := s.recent[t]
+	timeMS := float64(frameIndex) * float64(s.hopSize) / float64(s.sampleRate) * 1000
+
+	var peaks []Peak
+	for _, band := range s.bands {
+		if band.Start >= len(frame) || band.End >= len(frame) {
+			continue
+		}
+
+		// Find the maximum in this band
+		maxMag := 0.0
+		maxBin := -1
+
+		for f := band.Start; f <= band.End; f++ {
+			if frame[f] > maxMag && isLocalPeak(s.recent, t, f) {
+				maxMag = frame[f]
+				maxBin = f
+			}
+		}
+
+		// Add peak if it exceeds threshold
+		if maxBin != -1 && maxMag > PEAK_THRESHOLD {
+			peaks = append(peaks, Peak{
+				Time:      float64(frameIndex),
+				TimeMS:    timeMS,
+				FreqBin:   maxBin,
+				Magnitude: maxMag,
+			})
+		}
+	}
+
+	return peaks
+}
+
+// addPeak pairs a new peak as target with the previous peaks in its fan-out zone
+func (s *StreamFingerprinter) addPeak(target Peak) []Fingerprint {
+	var fingerprints []Fingerprint
+
+	for i, anchor := range s.peaks {
+		timeDelta := target.TimeMS - anchor.TimeMS
+		if timeDelta <= float64(MIN_HASH_TIME_DELTA) || timeDelta > float64(MAX_HASH_TIME_DELTA) {
+			continue
+		}
+
+		fingerprints = append(fingerprints, Fingerprint{
+			Hash:   hashPeakPair(anchor.FreqBin, target.FreqBin, timeDelta),
+			Offset: int(anchor.TimeMS),
+		})
+
+		// Same anchor selection as generateFingerprintsWithMinimalTolerance
+		anchorIndex := s.peakCount - int64(len(s.peaks)) + int64(i)
+		if s.microphone && anchorIndex%4 == 0 {
+			fingerprints = append(fingerprints, toleranceFingerprints(anchor, target, timeDelta)...)
+		}
+	}
+
+	s.peaks = append(s.peaks, target)
+	if len(s.peaks) > FAN_VALUE-1 {
+		s.peaks = s.peaks[len(s.peaks)-(FAN_VALUE-1):]
+	}
+	s.peakCount++
+
+	return fingerprints
+}
@@ -0,0 +1,67 @@
+package fingerprint
+
+import (
+	"math"
+	"testing"
+)
+
+// chirp returns seconds of a rising two-tone chirp at SAMPLE_RATE, the signal of the
+// recognition tests
+func chirp(seconds int) []float64 {
+	samples := make([]float64, seconds*SAMPLE_RATE)
+	for i := range samples {
+		t := float64(i) / SAMPLE_RATE
+		f := 300 + 2000*float64(i%SAMPLE_RATE)/SAMPLE_RATE + 500*float64(i/SAMPLE_RATE)
+		samples[i] = 0.37 * (math.Sin(2*math.Pi*f*t) + 0.3*math.Sin(2*math.Pi*1.7*f*t))
+	}
+	return samples
+}
+
+func TestStreamFingerprinterMatchesBatch(t *testing.T) {
+	samples := chirp(6)
+	spectrogram, err := SamplesToSpectrogram(samples, SAMPLE_RATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peaks := PickPeaks(spectrogram, SAMPLE_RATE)
+
+	tests := []struct {
+		name       string
+		microphone bool
+		batch      []Fingerprint
+	}{
+		{"file", false, GenerateFingerprints(peaks)},
+		{"microphone", true, GenerateFingerprintsForMicrophone(peaks)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.batch) == 0 {
+				t.Fatal("the batch pipeline found no fingerprints")
+			}
+			want := make(map[Fingerprint]int, len(tt.batch))
+			for _, fp := range tt.batch {
+				want[fp]++
+			}
+
+			// Odd chunk sizes, so that frames and filter windows straddle the writes
+			stream := NewStreamFingerprinter(SAMPLE_RATE, tt.microphone)
+			chunks := []int{1, 333, 4097, 17, 2048, 9999}
+			var got []Fingerprint
+			for pos, i := 0, 0; pos < len(samples); i++ {
+				end := min(pos+chunks[i%len(chunks)], len(samples))
+				got = append(got, stream.Write(samples[pos:end])...)
+				pos = end
+			}
+
+			for _, fp := range got {
+				if want[fp] == 0 {
+					t.Fatalf("stream fingerprint %+v is not in the batch output", fp)
+				}
+				want[fp]--
+			}
+			if len(got) != len(tt.batch) {
+				t.Fatalf("stream produced %d fingerprints, batch %d", len(got), len(tt.batch))
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eurekav1 "github.com/media-luna/eureka/api/eureka/v1"
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+const (
+	// defaultMaxMessageMB is used when the configuration sets no upload limit
+	defaultMaxMessageMB = 50
+	// defaultStreamSeconds bounds streams when the configuration sets no limits
+	defaultStreamSeconds = 30
+	// defaultPageSize is the number of songs listed when the request sets no page size
+	defaultPageSize = 50
+	// maxPageSize caps the page size of ListSongs and ListRecognitions
+	maxPageSize = 500
+	// clientMetadata names the API client in the recognition history, the peer
+	// address is recorded without it
+	clientMetadata = "x-client-id"
+)
+
+// Service is the part of the Eureka API exposed over gRPC, implemented by *eureka.Eureka
+type Service interface {
+	RecognizeReader(ctx context.Context, r io.Reader) ([]eureka.Match, error)
+	RecognizePCM(ctx context.Context, r io.Reader, format audio.PCMFormat) ([]eureka.Match, error)
+	SaveSong(ctx context.Context, path string, title string, artist string) (int, error)
+	List(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error)
+	GetSong(ctx context.Context, songID int) (mysql.SongInfo, error)
+	UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) (mysql.SongInfo, error)
+	Delete(ctx context.Context, songID int) error
+	History(ctx context.Context, query mysql.RecognitionQuery) (mysql.RecognitionPage, error)
+	NewStreamRecognizer(opts eureka.StreamOptions) *eureka.StreamRecognizer
+}
+
+// Server implements eurekav1.EurekaServiceServer on top of a Service
+type Server struct {
+	eurekav1.UnimplementedEurekaServiceServer
+
+	service          Service
+	cfg              config.Server
+	streamTimeout    time.Duration
+	streamMaxSeconds float64
+}
+
+// NewServer creates a gRPC service implementation. Streams share the live session
+// limits of the HTTP API.
+func NewServer(service Service, cfg config.Server) *Server {
+	timeout := cfg.LiveTimeoutSeconds
+	if timeout <= 0 {
+		timeout = defaultStreamSeconds
+	}
+	maxSeconds := cfg.LiveMaxAudioSeconds
+	if maxSeconds <= 0 {
+		maxSeconds = defaultStreamSeconds
+	}
+
+	return &Server{
+		service:          service,
+		cfg:              cfg,
+		streamTimeout:    time.Duration(timeout) * time.Second,
+		streamMaxSeconds: float64(maxSeconds),
+	}
+}
+
+// Register creates a grpc.Server with the message size limit and tracing and registers
+// s on it
+func (s *Server) Register(opts ...grpc.ServerOption) *grpc.Server {
+	maxMessageMB := s.cfg.MaxUploadMB
+	if maxMessageMB <= 0 {
+		maxMessageMB = defaultMaxMessageMB
+	}
+
+	opts = append([]grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxMessageMB << 20),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()), // Spans per RPC, child of the caller's trace context
+		grpc.ChainUnaryInterceptor(unaryClient),
+		grpc.ChainStreamInterceptor(streamClient),
+	}, opts...)
+	srv := grpc.NewServer(opts...)
+	eurekav1.RegisterEurekaServiceServer(srv, s)
+	return srv
+}
+
+// Serve serves the API on lis until ctx is cancelled, then stops gracefully
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	srv := s.Register()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(lis)
+	}()
+	logger.Info("gRPC API listening", "address", lis.Addr().String())
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("error serving gRPC API: %v", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down gRPC server")
+	srv.GracefulStop()
+	return nil
+}
+
+// ListenAndServe listens on the configured gRPC address and serves until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.cfg.GRPCAddress)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %v", s.cfg.GRPCAddress, err)
+	}
+	return s.Serve(ctx, lis)
+}
+
+// Recognize matches a complete audio clip
+func (s *Server) Recognize(ctx context.Context, req *eurekav1.RecognizeRequest) (*eurekav1.RecognizeResponse, error) {
+	if len(req.GetAudio()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing audio")
+	}
+
+	var matches []eureka.Match
+	var err error
+	if req.GetRawFormat() != nil {
+		format, formatErr := pcmFormat(req.GetRawFormat())
+		if formatErr != nil {
+			return nil, status.Error(codes.InvalidArgument, formatErr.Error())
+		}
+		matches, err = s.service.RecognizePCM(ctx, bytes.NewReader(req.GetAudio()), format)
+	} else {
+		matches, err = s.service.RecognizeReader(ctx, bytes.NewReader(req.GetAudio()))
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &eurekav1.RecognizeResponse{}
+	for _, match := range matches {
+		resp.Matches = append(resp.Matches, newMatch(match))
+	}
+	return resp, nil
+}
+
+// IngestSong fingerprints a song and adds it to the catalog
+func (s *Server) IngestSong(ctx context.Context, req *eurekav1.IngestSongRequest) (*eurekav1.IngestSongResponse, error) {
+	if len(req.GetAudio()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing audio")
+	}
+
+	artist, title := eureka.ParseSongFilename(req.GetFilename())
+	if v := strings.TrimSpace(req.GetTitle()); v != "" {
+		title = v
+	}
+	if v := strings.TrimSpace(req.GetArtist()); v != "" {
+		artist = v
+	}
+	if title == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing song title")
+	}
+
+	// Decoding picks the format by extension, keep it on the temporary copy
+	tmp, err := os.CreateTemp("", "eureka-upload-*"+strings.ToLower(filepath.Ext(req.GetFilename())))
+	if err != nil {
+		return nil, statusError(fmt.Errorf("error creating temporary file: %v", err))
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(req.GetAudio())
+	tmp.Close()
+	if err != nil {
+		return nil, statusError(fmt.Errorf("error storing upload: %v", err))
+	}
+
+	songID, err := s.service.SaveSong(ctx, tmp.Name(), title, artist)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &eurekav1.IngestSongResponse{
+		Song: &eurekav1.Song{Id: int64(songID), Title: title, Artist: artist},
+	}, nil
+}
+
+// ListSongs lists the catalog matching the request filters, the page token is the
+// offset of the next page
+func (s *Server) ListSongs(ctx context.Context, req *eurekav1.ListSongsRequest) (*eurekav1.ListSongsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	}
+
+	offset := 0
+	if token := req.GetPageToken(); token != "" {
+		var err error
+		if offset, err = strconv.Atoi(token); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	query := catalog.SongQuery{
+		Name:          req.GetTitleContains(),
+		Artist:        req.GetArtistContains(),
+		Fingerprinted: req.Fingerprinted,
+		Limit:         pageSize,
+		Offset:        offset,
+	}
+	if req.CreatedAfter != nil {
+		query.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		query.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+	if orderBy := strings.Fields(req.GetOrderBy()); len(orderBy) > 0 {
+		query.Sort = orderBy[0]
+		if query.Sort == "title" {
+			query.Sort = "name"
+		}
+		switch {
+		case len(orderBy) == 2 && orderBy[1] == "desc":
+			query.Descending = true
+		case len(orderBy) > 1:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid order_by %q", req.GetOrderBy())
+		}
+	}
+
+	page, err := s.service.List(ctx, query)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &eurekav1.ListSongsResponse{TotalSize: int32(page.Total)}
+	for _, song := range page.Songs {
+		resp.Songs = append(resp.Songs, newSong(song))
+	}
+	if offset+len(page.Songs) < page.Total {
+		resp.NextPageToken = strconv.Itoa(offset + len(page.Songs))
+	}
+	return resp, nil
+}
+
+// ListRecognitions lists the recognition history matching the request filters, newest
+// first. The page token is the offset of the next page.
+func (s *Server) ListRecognitions(ctx context.Context, req *eurekav1.ListRecognitionsRequest) (*eurekav1.ListRecognitionsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	}
+
+	offset := 0
+	if token := req.GetPageToken(); token != "" {
+		var err error
+		if offset, err = strconv.Atoi(token); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	if source := req.GetSource(); source != "" && !slices.Contains(eureka.Sources, source) {
+		return nil, status.Errorf(codes.InvalidArgument, "source must be one of %s", strings.Join(eureka.Sources, ", "))
+	}
+	if req.GetSongId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "song_id must not be negative")
+	}
+
+	query := mysql.RecognitionQuery{
+		Source:   req.GetSource(),
+		ClientID: req.GetClientId(),
+		SongID:   int(req.GetSongId()),
+		Matched:  req.Matched,
+		Limit:    pageSize,
+		Offset:   offset,
+	}
+	if req.Since != nil {
+		query.Since = req.Since.AsTime()
+	}
+	if req.Until != nil {
+		query.Until = req.Until.AsTime()
+	}
+
+	page, err := s.service.History(ctx, query)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &eurekav1.ListRecognitionsResponse{TotalSize: int32(page.Total)}
+	for _, rec := range page.Recognitions {
+		recognition := &eurekav1.Recognition{
+			Id:           rec.ID,
+			RecognizedAt: timestamppb.New(rec.RecognizedAt),
+			Source:       rec.Source,
+			ClientId:     rec.ClientID,
+			AudioMs:      rec.AudioMs,
+			LatencyMs:    rec.LatencyMs,
+		}
+		for _, m := range rec.Matches {
+			recognition.Matches = append(recognition.Matches, &eurekav1.Match{
+				SongId:   int64(m.SongID),
+				Title:    m.Song,
+				Artist:   m.Artist,
+				Score:    m.Score,
+				OffsetMs: int64(m.Offset),
+			})
+		}
+		resp.Recognitions = append(resp.Recognitions, recognition)
+	}
+	if offset+len(page.Recognitions) < page.Total {
+		resp.NextPageToken = strconv.Itoa(offset + len(page.Recognitions))
+	}
+	return resp, nil
+}
+
+// GetSong returns a single song
+func (s *Server) GetSong(ctx context.Context, req *eurekav1.GetSongRequest) (*eurekav1.GetSongResponse, error) {
+	song, err := s.service.GetSong(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &eurekav1.GetSongResponse{
+		Song: &eurekav1.Song{Id: int64(song.ID), Title: song.Name, Artist: song.Artist, Album: song.Album},
+	}, nil
+}
+
+// UpdateSong changes the metadata set in the request
+func (s *Server) UpdateSong(ctx context.Context, req *eurekav1.UpdateSongRequest) (*eurekav1.UpdateSongResponse, error) {
+	if req.Title == nil && req.Artist == nil && req.Album == nil {
+		return nil, status.Error(codes.InvalidArgument, "nothing to change, set title, artist or album")
+	}
+
+	song, err := s.service.UpdateSong(ctx, int(req.GetId()), mysql.SongUpdate{Name: req.Title, Artist: req.Artist, Album: req.Album})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &eurekav1.UpdateSongResponse{
+		Song: &eurekav1.Song{Id: int64(song.ID), Title: song.Name, Artist: song.Artist, Album: song.Album},
+	}, nil
+}
+
+// DeleteSong deletes a song and its fingerprints
+func (s *Server) DeleteSong(ctx context.Context, req *eurekav1.DeleteSongRequest) (*eurekav1.DeleteSongResponse, error) {
+	if err := s.service.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, statusError(err)
+	}
+	return &eurekav1.DeleteSongResponse{}, nil
+}
+
+// statusError maps a service error to a gRPC status, internal errors are logged and not exposed
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, mysql.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, eureka.ErrInvalidAudio), errors.Is(err, eureka.ErrInvalidMetadata), errors.Is(err, catalog.ErrInvalidQuery):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errors.ErrUnsupported):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		logger.Error(err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// withClient tags ctx with the client recorded in the recognition history
+func withClient(ctx context.Context) context.Context {
+	clientID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(clientMetadata); len(values) > 0 {
+			clientID = strings.TrimSpace(values[0])
+		}
+	}
+	if clientID == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			clientID = p.Addr.String()
+			if host, _, err := net.SplitHostPort(clientID); err == nil {
+				clientID = host
+			}
+		}
+	}
+	return eureka.WithClient(ctx, clientID)
+}
+
+// unaryClient sets the history client of unary calls
+func unaryClient(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withClient(ctx), req)
+}
+
+// clientStream overrides the context of a server stream
+type clientStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamClient sets the history client of streaming calls
+func streamClient(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &clientStream{ServerStream: stream, ctx: withClient(stream.Context())})
+}
+
+// pcmFormat converts a PCM format message
+func pcmFormat(f *eurekav1.PCMFormat) (audio.PCMFormat, error) {
+	encodings := map[eurekav1.PCMEncoding]string{
+		eurekav1.PCMEncoding_PCM_ENCODING_S16LE: "s16le",
+		eurekav1.PCMEncoding_PCM_ENCODING_S32LE: "s32le",
+		eurekav1.PCMEncoding_PCM_ENCODING_F32LE: "f32le",
+		eurekav1.PCMEncoding_PCM_ENCODING_U8:    "u8",
+	}
+
+	encoding, ok := encodings[f.GetEncoding()]
+	if !ok {
+		return audio.PCMFormat{}, fmt.Errorf("unsupported PCM encoding: %s", f.GetEncoding())
+	}
+	if f.GetSampleRate() <= 0 || f.GetChannels() <= 0 {
+		return audio.PCMFormat{}, fmt.Errorf("sample_rate and channels must be positive")
+	}
+
+	return audio.PCMFormat{Encoding: encoding, SampleRate: int(f.GetSampleRate()), Channels: int(f.GetChannels())}, nil
+}
+
+// newMatch converts a match to its message
+func newMatch(match eureka.Match) *eurekav1.Match {
+	return &eurekav1.Match{
+		SongId:   int64(match.SongID),
+		Title:    match.SongName,
+		Artist:   match.Artist,
+		Score:    match.Score,
+		OffsetMs: int64(match.Offset),
+	}
+}
+
+// newSong converts a database song to its message
+func newSong(song mysql.Song) *eurekav1.Song {
+	return &eurekav1.Song{
+		Id:            int64(song.ID),
+		Title:         song.Name,
+		Artist:        song.Artist,
+		Album:         song.Album,
+		Fingerprinted: song.Fingerprinted,
+		FileSha1:      song.FileSHA1,
+		TotalHashes:   int64(song.TotalHashes),
+		DateCreated:   song.DateCreated,
+	}
+}
@@ -0,0 +1,231 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	eurekav1 "github.com/media-luna/eureka/api/eureka/v1"
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/internal/eurekatest"
+	"github.com/media-luna/eureka/internal/metrics"
+)
+
+// fakeService recognizes one-shot audio as song 7 and keeps what it received. The
+// streams are recognized by the embedded Eureka against its catalog.
+type fakeService struct {
+	*eureka.Eureka
+	audio  []byte
+	format audio.PCMFormat
+}
+
+func (f *fakeService) RecognizeReader(ctx context.Context, r io.Reader) ([]eureka.Match, error) {
+	var err error
+	f.audio, err = io.ReadAll(r)
+	return []eureka.Match{{SongID: 7, SongName: "Song", Artist: "Artist", Score: 0.5, Offset: 1200}}, err
+}
+
+func (f *fakeService) RecognizePCM(ctx context.Context, r io.Reader, format audio.PCMFormat) ([]eureka.Match, error) {
+	f.format = format
+	return f.RecognizeReader(ctx, r)
+}
+
+// startServer serves a Server backed by service over an in-memory connection
+func startServer(t *testing.T, service Service, cfg config.Server) eurekav1.EurekaServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewServer(service, cfg).Serve(ctx, lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		<-done
+	})
+	return eurekav1.NewEurekaServiceClient(conn)
+}
+
+func newFakeService(catalog *eurekatest.Catalog) *fakeService {
+	return &fakeService{Eureka: eureka.NewEurekaWithDatabase(config.Default(), catalog)}
+}
+
+func TestRecognize(t *testing.T) {
+	service := newFakeService(&eurekatest.Catalog{})
+	client := startServer(t, service, config.Server{})
+	ctx := context.Background()
+
+	pcm, _ := eurekatest.ChirpPCM(t, 1)
+	resp, err := client.Recognize(ctx, &eurekav1.RecognizeRequest{
+		Audio:     pcm,
+		RawFormat: &eurekav1.PCMFormat{Encoding: eurekav1.PCMEncoding_PCM_ENCODING_S16LE, SampleRate: eurekatest.SampleRate, Channels: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetMatches()) != 1 || resp.GetMatches()[0].GetSongId() != 7 {
+		t.Fatalf("matches = %v", resp.GetMatches())
+	}
+	if !bytes.Equal(service.audio, pcm) {
+		t.Fatalf("service received %d bytes, want the %d sent", len(service.audio), len(pcm))
+	}
+	if want := (audio.PCMFormat{Encoding: "s16le", SampleRate: eurekatest.SampleRate, Channels: 1}); service.format != want {
+		t.Fatalf("format = %v, want %v", service.format, want)
+	}
+
+	if _, err := client.Recognize(ctx, &eurekav1.RecognizeRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Recognize without audio: %v, want InvalidArgument", err)
+	}
+	_, err = client.Recognize(ctx, &eurekav1.RecognizeRequest{
+		Audio:     pcm,
+		RawFormat: &eurekav1.PCMFormat{Encoding: eurekav1.PCMEncoding_PCM_ENCODING_UNSPECIFIED, SampleRate: eurekatest.SampleRate, Channels: 1},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Recognize without an encoding: %v, want InvalidArgument", err)
+	}
+}
+
+func TestRecognizeMessageLimit(t *testing.T) {
+	client := startServer(t, newFakeService(&eurekatest.Catalog{}), config.Server{MaxUploadMB: 1})
+
+	_, err := client.Recognize(context.Background(), &eurekav1.RecognizeRequest{Audio: make([]byte, 2<<20)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Recognize of 2 MB: %v, want ResourceExhausted", err)
+	}
+}
+
+// streamAudio sends the format and pcm in chunks of a tenth of a second, then
+// returns the events received until the server ends the stream
+func streamAudio(t *testing.T, client eurekav1.EurekaServiceClient, pcm []byte) []*eurekav1.StreamRecognizeResponse {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamRecognize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&eurekav1.StreamRecognizeRequest{Request: &eurekav1.StreamRecognizeRequest_Format{
+		Format: &eurekav1.PCMFormat{Encoding: eurekav1.PCMEncoding_PCM_ENCODING_S16LE, SampleRate: eurekatest.SampleRate, Channels: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		const chunk = eurekatest.SampleRate / 10 * 2
+		for start := 0; start < len(pcm); start += chunk {
+			end := min(start+chunk, len(pcm))
+			// Fails once the server ended the stream after a match
+			if stream.Send(&eurekav1.StreamRecognizeRequest{Request: &eurekav1.StreamRecognizeRequest_Audio{Audio: pcm[start:end]}}) != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	var events []*eurekav1.StreamRecognizeResponse
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return events
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+}
+
+func TestStreamRecognizeMatch(t *testing.T) {
+	// The song is ingested with the batch pipeline and starts a second before the stream
+	pcm, samples := eurekatest.ChirpPCM(t, 8)
+	catalog := &eurekatest.Catalog{}
+	catalog.Add(mysql.SongInfo{ID: 1, Name: "Chirp", Artist: "Generator"}, eurekatest.Fingerprints(t, samples), 1000)
+	client := startServer(t, newFakeService(catalog), config.Server{})
+	sessions := metrics.StreamSessions.WithLabelValues(eureka.SourceAPI, metrics.ResultMatch)
+	before := testutil.ToFloat64(sessions)
+
+	events := streamAudio(t, client, pcm)
+	if len(events) < 2 || events[0].GetType() != eurekav1.StreamEventType_STREAM_EVENT_TYPE_LISTENING {
+		t.Fatalf("events = %v, want listening first", events)
+	}
+	last := events[len(events)-1]
+	if last.GetType() != eurekav1.StreamEventType_STREAM_EVENT_TYPE_MATCH {
+		t.Fatalf("last event = %v, want a match", last)
+	}
+	if match := last.GetMatch(); match.GetSongId() != 1 || match.GetTitle() != "Chirp" || match.GetOffsetMs() != 1000 {
+		t.Fatalf("match = %v, want song 1 a second in", match)
+	}
+	if last.GetSeconds() <= 0 || last.GetSeconds() > 8 {
+		t.Fatalf("matched after %v seconds", last.GetSeconds())
+	}
+	// The recognizer is closed when the handler returns, before the stream ends
+	if got := testutil.ToFloat64(sessions) - before; got != 1 {
+		t.Fatalf("counted %v matching api sessions, want 1", got)
+	}
+}
+
+func TestStreamRecognizeNoMatch(t *testing.T) {
+	pcm, _ := eurekatest.ChirpPCM(t, 2)
+	client := startServer(t, newFakeService(&eurekatest.Catalog{}), config.Server{})
+
+	events := streamAudio(t, client, pcm)
+	last := events[len(events)-1]
+	if last.GetType() != eurekav1.StreamEventType_STREAM_EVENT_TYPE_NO_MATCH || last.GetReason() != "end" {
+		t.Fatalf("last event = %v, want no_match at the end of the audio", last)
+	}
+}
+
+func TestStreamRecognizeNeedsFormat(t *testing.T) {
+	client := startServer(t, newFakeService(&eurekatest.Catalog{}), config.Server{})
+	stream, err := client.StreamRecognize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Send(&eurekav1.StreamRecognizeRequest{Request: &eurekav1.StreamRecognizeRequest_Audio{Audio: []byte{0, 0}}})
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("stream without format: %v, want InvalidArgument", err)
+	}
+}
+
+func TestStreamRecognizeWithoutAudio(t *testing.T) {
+	client := startServer(t, newFakeService(&eurekatest.Catalog{}), config.Server{})
+	total := func() (sum float64) {
+		for _, outcome := range []string{metrics.ResultMatch, metrics.ResultNoMatch} {
+			sum += testutil.ToFloat64(metrics.StreamSessions.WithLabelValues(eureka.SourceAPI, outcome))
+		}
+		return sum
+	}
+	before := total()
+
+	events := streamAudio(t, client, nil)
+	if last := events[len(events)-1]; last.GetType() != eurekav1.StreamEventType_STREAM_EVENT_TYPE_NO_MATCH {
+		t.Fatalf("last event = %v, want no_match", last)
+	}
+	if got := total() - before; got != 0 {
+		t.Fatalf("counted %v sessions without audio", got)
+	}
+}
@@ -0,0 +1,146 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	eurekav1 "github.com/media-luna/eureka/api/eureka/v1"
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/eureka"
+	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+)
+
+// StreamRecognize matches live PCM audio. It sends a listening event, then
+// possible_match events, and ends the stream after a match or a no_match event.
+func (s *Server) StreamRecognize(stream eurekav1.EurekaService_StreamRecognizeServer) error {
+	ctx, cancel := context.WithTimeout(stream.Context(), s.streamTimeout)
+	defer cancel()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if first.GetFormat() == nil {
+		return status.Error(codes.InvalidArgument, "the first message must set the PCM format")
+	}
+	format, err := pcmFormat(first.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	decoder := audio.NewPCMFrameDecoder(format)
+	converter := fingerprint.NewConverter(format.SampleRate, format.Channels)
+	recognizer := s.service.NewStreamRecognizer(eureka.DefaultStreamOptions())
+	defer recognizer.Close()
+
+	if err := stream.Send(&eurekav1.StreamRecognizeResponse{Type: eurekav1.StreamEventType_STREAM_EVENT_TYPE_LISTENING}); err != nil {
+		return err
+	}
+
+	chunks, recvErr := receiveAudio(ctx, stream)
+	noMatch := func(reason string) error {
+		return stream.Send(&eurekav1.StreamRecognizeResponse{
+			Type:    eurekav1.StreamEventType_STREAM_EVENT_TYPE_NO_MATCH,
+			Seconds: recognizer.Seconds(),
+			Reason:  reason,
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && stream.Context().Err() == nil {
+				return noMatch("timeout")
+			}
+			return status.FromContextError(ctx.Err()).Err()
+
+		case chunk, ok := <-chunks:
+			if !ok {
+				if err := <-recvErr; err != nil {
+					return err
+				}
+				return noMatch("end")
+			}
+
+			samples, err := decoder.Decode(chunk)
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			if err := recognizer.WriteContext(ctx, converter.Convert(samples)); err != nil {
+				return statusError(err)
+			}
+
+			if done, err := sendEvents(stream, recognizer); done || err != nil {
+				return err
+			}
+			if recognizer.Seconds() >= s.streamMaxSeconds {
+				return noMatch("audio_limit")
+			}
+		}
+	}
+}
+
+// receiveAudio receives audio chunks in the background. The chunks channel is
+// closed when the client closes its side or the stream fails, the error (nil for
+// a clean end) is then available on the second channel.
+func receiveAudio(ctx context.Context, stream eurekav1.EurekaService_StreamRecognizeServer) (<-chan []byte, <-chan error) {
+	chunks := make(chan []byte, 16)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(chunks)
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				errs <- nil
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			if req.GetFormat() != nil {
+				errs <- status.Error(codes.InvalidArgument, "the PCM format can only be set once")
+				return
+			}
+
+			select {
+			case chunks <- req.GetAudio():
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return chunks, errs
+}
+
+// sendEvents forwards pending recognizer events and reports whether a match ended the stream
+func sendEvents(stream eurekav1.EurekaService_StreamRecognizeServer, recognizer *eureka.StreamRecognizer) (bool, error) {
+	for {
+		select {
+		case event := <-recognizer.Events():
+			resp := &eurekav1.StreamRecognizeResponse{
+				Type:    eurekav1.StreamEventType_STREAM_EVENT_TYPE_POSSIBLE_MATCH,
+				Seconds: event.Seconds,
+				Match:   newMatch(event.Match),
+			}
+			if event.Type == eureka.EventMatch {
+				resp.Type = eurekav1.StreamEventType_STREAM_EVENT_TYPE_MATCH
+			}
+			if err := stream.Send(resp); err != nil {
+				return true, err
+			}
+			if event.Type == eureka.EventMatch {
+				return true, nil
+			}
+		default:
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package metrics defines the Prometheus metrics of Eureka. They are updated by the
+// eureka package and served on /metrics by eureka serve.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Recognition stages, the values of the stage label
+const (
+	StageDecode      = "decode"
+	StageSpectrogram = "spectrogram"
+	StagePeaks       = "peaks"
+	StageQuery       = "db_query"
+	StageScoring     = "scoring"
+)
+
+// Recognition and session results, the values of the result and outcome labels
+const (
+	ResultMatch   = "match"
+	ResultNoMatch = "no_match"
+	ResultError   = "error"
+)
+
+// Registry holds the Eureka metrics with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// SongsIngested counts the songs fingerprinted and stored
+	SongsIngested = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "eureka",
+		Subsystem: "ingest",
+		Name:      "songs_total",
+		Help:      "Songs fingerprinted and stored.",
+	})
+
+	// FingerprintsIngested counts the fingerprints stored by successful ingests
+	FingerprintsIngested = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "eureka",
+		Subsystem: "ingest",
+		Name:      "fingerprints_total",
+		Help:      "Fingerprints stored by successful ingests.",
+	})
+
+	// IngestFailures counts the ingests that returned an error
+	IngestFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "eureka",
+		Subsystem: "ingest",
+		Name:      "failures_total",
+		Help:      "Ingests that failed.",
+	})
+
+	// Recognitions counts the one-shot recognitions by result
+	Recognitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eureka",
+		Name:      "recognitions_total",
+		Help:      "One-shot recognitions by result: match, no_match or error.",
+	}, []string{"result"})
+
+	// RecognitionDuration observes the latency of successful one-shot recognitions
+	RecognitionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "eureka",
+		Name:      "recognition_duration_seconds",
+		Help:      "Latency of successful one-shot recognitions, decoding included.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	// StageDuration observes the duration of each recognition stage
+	StageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "eureka",
+		Subsystem: "recognition",
+		Name:      "stage_duration_seconds",
+		Help:      "Duration of the recognition stages: decode, spectrogram, peaks, db_query and scoring.",
+		Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"stage"})
+
+	// BatchSize observes the number of hashes of each QueryFingerprints batch
+	BatchSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "eureka",
+		Subsystem: "db",
+		Name:      "fingerprint_batch_size",
+		Help:      "Hashes looked up per fingerprint query batch.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 6), // 1 to 1024, batches hold at most 1000
+	})
+
+	// PrunedHashes counts the query hashes skipped because they are stop-listed
+	PrunedHashes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "eureka",
+		Subsystem: "recognition",
+		Name:      "stop_listed_hashes_total",
+		Help:      "Query hashes skipped because they are stop-listed.",
+	})
+
+	// StreamSessions counts the live recognition sessions that received audio, by
+	// source and outcome. The sources are those of the recognition history, the eureka
+	// package exports their series.
+	StreamSessions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eureka",
+		Name:      "stream_sessions_total",
+		Help:      "Live recognition sessions that received audio, by source (mic for listen and the library, api for WebSocket and gRPC streams) and outcome: match or no_match.",
+	}, []string{"source", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		SongsIngested,
+		FingerprintsIngested,
+		IngestFailures,
+		Recognitions,
+		RecognitionDuration,
+		StageDuration,
+		BatchSize,
+		PrunedHashes,
+		StreamSessions,
+	)
+
+	// Export every series from the start, so that ratios are defined before the first event
+	for _, result := range []string{ResultMatch, ResultNoMatch, ResultError} {
+		Recognitions.WithLabelValues(result)
+	}
+	for _, stage := range []string{StageDecode, StageSpectrogram, StagePeaks, StageQuery, StageScoring} {
+		StageDuration.WithLabelValues(stage)
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,316 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+const (
+	// reconnectDelay is how long a channel waits before reopening a failed or finished source
+	reconnectDelay = 5 * time.Second
+	// readBufferSize is the number of bytes read from a source at a time
+	readBufferSize = 16 * 1024
+	// flushTimeout bounds storing the plays still open when the monitor stops
+	flushTimeout = 5 * time.Second
+)
+
+// Recognizer is the part of the Eureka API used by the monitor
+type Recognizer interface {
+	RecognizeWindow(ctx context.Context, audioWindow []float64, sampleRate int) ([]eureka.Match, error)
+	RecordPlay(ctx context.Context, play mysql.Play) error
+}
+
+// Monitor watches several continuous audio feeds and logs every detected song
+type Monitor struct {
+	recognizer Recognizer
+	cfg        config.Monitor
+}
+
+// chunk is a block of mono samples read from a channel source
+type chunk struct {
+	samples    []float64
+	sampleRate int
+	reconnect  bool // First chunk since the source was (re)opened, the stream time restarts
+}
+
+// NewMonitor creates a monitor for the channels in the given configuration
+func NewMonitor(recognizer Recognizer, cfg config.Monitor) (*Monitor, error) {
+	if len(cfg.Channels) == 0 {
+		return nil, fmt.Errorf("no monitor channels configured")
+	}
+	if cfg.WindowSeconds <= 0 || cfg.HopSeconds <= 0 {
+		return nil, fmt.Errorf("monitor window and hop must be positive")
+	}
+
+	for _, ch := range cfg.Channels {
+		if ch.Name == "" || ch.Source == "" {
+			return nil, fmt.Errorf("monitor channels need a name and a source")
+		}
+		if ch.SampleRate <= 0 {
+			return nil, fmt.Errorf("channel %s: sample_rate must be positive", ch.Name)
+		}
+	}
+
+	return &Monitor{recognizer: recognizer, cfg: cfg}, nil
+}
+
+// Run starts one pipeline per channel and blocks until ctx is cancelled
+// and every pipeline has flushed its current play
+func (m *Monitor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, ch := range m.cfg.Channels {
+		chunks := make(chan chunk, 64)
+
+		wg.Add(2)
+		go func(ch config.MonitorChannel) {
+			defer wg.Done()
+			defer close(chunks)
+			m.readChannel(ctx, ch, chunks)
+		}(ch)
+		go func(ch config.MonitorChannel) {
+			defer wg.Done()
+			m.analyzeChannel(ctx, ch, chunks)
+		}(ch)
+
+		logger.Info("Monitoring channel", "channel", ch.Name, "source", ch.Source)
+	}
+
+	wg.Wait()
+	logger.Info("Monitor stopped")
+}
+
+// readChannel keeps the channel source open, reconnecting after failures,
+// and forwards decoded samples until ctx is cancelled
+func (m *Monitor) readChannel(ctx context.Context, ch config.MonitorChannel, chunks chan<- chunk) {
+	for {
+		source, err := m.open(ctx, ch)
+		if err == nil {
+			err = pump(ctx, source, newPCMDecoder(ch.SampleRate, ch.Channels), chunks)
+			source.Close()
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			logger.Error(err, "channel", ch.Name)
+		}
+		logger.Info("Reconnecting channel", "channel", ch.Name, "delay", reconnectDelay.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// open opens the channel source without blocking shutdown, since opening
+// a named pipe waits until a writer connects
+func (m *Monitor) open(ctx context.Context, ch config.MonitorChannel) (io.ReadCloser, error) {
+	type result struct {
+		source io.ReadCloser
+		err    error
+	}
+
+	opened := make(chan result, 1)
+	go func() {
+		source, err := openSource(ctx, ch.Source, ch.Channels)
+		opened <- result{source, err}
+	}()
+
+	select {
+	case r := <-opened:
+		return r.source, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-opened; r.source != nil {
+				r.source.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// pump reads from the source until EOF, an error or cancellation, sending decoded samples on chunks
+func pump(ctx context.Context, source io.ReadCloser, decoder *pcmDecoder, chunks chan<- chunk) error {
+	// Closing the source unblocks a pending read on pipes and HTTP bodies
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			source.Close()
+		case <-done:
+		}
+	}()
+
+	buf := make([]byte, readBufferSize)
+	reconnect := true
+	for {
+		n, err := source.Read(buf)
+		if n > 0 {
+			samples, decodeErr := decoder.decode(buf[:n])
+			if decodeErr != nil {
+				return fmt.Errorf("error decoding source: %v", decodeErr)
+			}
+			if len(samples) > 0 {
+				select {
+				case chunks <- chunk{samples: samples, sampleRate: decoder.sampleRate, reconnect: reconnect}:
+					reconnect = false
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+		if err == io.EOF {
+			return fmt.Errorf("source ended")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// analyzeChannel slides the recognition window over the channel audio and
+// turns consecutive detections into plays
+func (m *Monitor) analyzeChannel(ctx context.Context, ch config.MonitorChannel, chunks <-chan chunk) {
+	tracker := &playTracker{
+		channel:  ch.Name,
+		minScore: m.cfg.MinScore,
+		timeout:  time.Duration(m.cfg.PlayTimeoutSeconds) * time.Second,
+	}
+
+	var window []float64
+	var anchor time.Time // Wall-clock time of the first sample in the stream
+	var position int64   // Number of samples received so far
+	var sinceLast int    // Samples received since the last analysis
+	sampleRate := ch.SampleRate
+
+	for c := range chunks {
+		if c.reconnect || c.sampleRate != sampleRate {
+			// First audio since the source was opened, the outage is not part of the stream
+			sampleRate = c.sampleRate
+			anchor = time.Now().Add(-time.Duration(float64(len(c.samples)) / float64(sampleRate) * float64(time.Second)))
+			window = window[:0]
+			position = 0
+			sinceLast = 0
+		}
+
+		windowSamples := m.cfg.WindowSeconds * sampleRate
+		hopSamples := m.cfg.HopSeconds * sampleRate
+
+		window = append(window, c.samples...)
+		if len(window) > windowSamples {
+			window = append(window[:0], window[len(window)-windowSamples:]...)
+		}
+		position += int64(len(c.samples))
+		sinceLast += len(c.samples)
+
+		if len(window) < windowSamples || sinceLast < hopSamples {
+			continue
+		}
+		sinceLast = 0
+
+		windowEnd := anchor.Add(time.Duration(float64(position) / float64(sampleRate) * float64(time.Second)))
+		windowStart := windowEnd.Add(-time.Duration(m.cfg.WindowSeconds) * time.Second)
+
+		matches, err := m.recognizer.RecognizeWindow(ctx, window, sampleRate)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			logger.Error(err, "channel", ch.Name)
+			continue
+		}
+
+		var best *eureka.Match
+		if len(matches) > 0 {
+			best = &matches[0]
+		}
+
+		if play := tracker.observe(best, windowStart, windowEnd); play != nil {
+			m.record(ctx, *play)
+		}
+	}
+
+	// The monitor is shutting down, give the last play its own deadline to be stored
+	if play := tracker.flush(); play != nil {
+		flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+		m.record(flushCtx, *play)
+	}
+}
+
+// record stores a finished play
+func (m *Monitor) record(ctx context.Context, play mysql.Play) {
+	logger.Info("Played song", "channel", play.Channel, "song_id", play.SongID,
+		"started_at", play.StartedAt.Format(time.RFC3339), "ended_at", play.EndedAt.Format(time.RFC3339),
+		"duration_ms", play.EndedAt.Sub(play.StartedAt).Milliseconds(), "score", play.Score)
+
+	if err := m.recognizer.RecordPlay(ctx, play); err != nil {
+		logger.Error(err, "channel", play.Channel, "song_id", play.SongID)
+	}
+}
+
+// playTracker merges window detections on a single channel into plays
+type playTracker struct {
+	channel  string
+	minScore float64
+	timeout  time.Duration
+	current  *mysql.Play
+}
+
+// observe records the best match of a window and returns the play that
+// finished because of it, if any
+func (t *playTracker) observe(match *eureka.Match, windowStart, windowEnd time.Time) *mysql.Play {
+	if match == nil || match.Score < t.minScore {
+		// Nothing recognized, end the current play once it has been silent long enough
+		if t.current != nil && windowEnd.Sub(t.current.EndedAt) > t.timeout {
+			return t.flush()
+		}
+		return nil
+	}
+
+	if t.current != nil && t.current.SongID == match.SongID {
+		t.current.EndedAt = windowEnd
+		if match.Score > t.current.Score {
+			t.current.Score = match.Score
+		}
+		return nil
+	}
+
+	finished := t.flush()
+	if finished != nil && finished.EndedAt.After(windowStart) {
+		// Consecutive windows overlap, the new song starts where the old one ends
+		finished.EndedAt = windowStart
+	}
+
+	logger.Info("Now playing", "channel", t.channel, "song_id", match.SongID, "song", match.SongName, "artist", match.Artist, "score", match.Score)
+	t.current = &mysql.Play{
+		Channel:   t.channel,
+		SongID:    match.SongID,
+		StartedAt: windowStart,
+		EndedAt:   windowEnd,
+		Score:     match.Score,
+	}
+
+	return finished
+}
+
+// flush ends the current play and returns it
+func (t *playTracker) flush() *mysql.Play {
+	play := t.current
+	t.current = nil
+	return play
+}
@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+)
+
+// fakeRecognizer recognizes song 1 in every window and keeps the recorded plays
+type fakeRecognizer struct {
+	mu    sync.Mutex
+	plays []mysql.Play
+}
+
+func (f *fakeRecognizer) RecognizeWindow(ctx context.Context, audioWindow []float64, sampleRate int) ([]eureka.Match, error) {
+	return []eureka.Match{{SongID: 1, Score: 1}}, nil
+}
+
+func (f *fakeRecognizer) RecordPlay(ctx context.Context, play mysql.Play) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plays = append(f.plays, play)
+	return nil
+}
+
+func TestAnalyzeChannelResetsTimeOnReconnect(t *testing.T) {
+	const sampleRate = 100
+	recognizer := &fakeRecognizer{}
+	m := &Monitor{recognizer: recognizer, cfg: config.Monitor{WindowSeconds: 1, HopSeconds: 1, PlayTimeoutSeconds: 60}}
+	ch := config.MonitorChannel{Name: "radio", SampleRate: sampleRate}
+
+	chunks := make(chan chunk)
+	done := make(chan struct{})
+	go func() {
+		m.analyzeChannel(context.Background(), ch, chunks)
+		close(done)
+	}()
+
+	second := make([]float64, sampleRate)
+	chunks <- chunk{samples: second, sampleRate: sampleRate, reconnect: true}
+
+	// The source is down for a while, then plays one more second
+	outage := 300 * time.Millisecond
+	time.Sleep(outage)
+	reconnected := time.Now()
+	chunks <- chunk{samples: second, sampleRate: sampleRate, reconnect: true}
+	close(chunks)
+	<-done
+
+	if len(recognizer.plays) != 1 {
+		t.Fatalf("recorded %d plays, want 1", len(recognizer.plays))
+	}
+	// Without the reset the play would end an outage before the audio was received
+	if drift := reconnected.Sub(recognizer.plays[0].EndedAt); drift > outage/2 || drift < -outage/2 {
+		t.Fatalf("play ends %v before the last audio was received", drift)
+	}
+}
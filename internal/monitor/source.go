@@ -0,0 +1,259 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// tailPollInterval is how often an appended file is checked for new data at EOF
+	tailPollInterval = 200 * time.Millisecond
+	// maxWAVHeaderSize bounds the bytes buffered before the data chunk of a WAV feed
+	maxWAVHeaderSize = 64 * 1024
+)
+
+// openSource opens a channel source for reading. Regular files are followed like
+// `tail -f` starting at their current end, named pipes are read as they are written
+// and http(s) URLs are streamed from the response body. channels is the layout of
+// raw PCM files, WAV files announce their own.
+func openSource(ctx context.Context, source string, channels int) (io.ReadCloser, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating stream request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error requesting stream: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected stream status: %s", resp.Status)
+		}
+		return resp.Body, nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("error stating source: %v", err)
+	}
+
+	// Opening a named pipe blocks until a writer shows up, which is what we want
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("error opening source: %v", err)
+	}
+
+	if info.Mode()&os.ModeNamedPipe != 0 {
+		return file, nil
+	}
+
+	offset, header, err := tailStart(file, info.Size(), channels)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error seeking to end of source: %v", err)
+	}
+
+	return &tailReader{file: file, ctx: ctx, header: header}, nil
+}
+
+// tailStart returns where following a file starts: the last frame boundary of the
+// audio already written, counted from the data chunk of a WAV file. The WAV header is
+// returned too, to be read before the samples so that the decoder learns the format.
+func tailStart(file *os.File, size int64, channels int) (int64, []byte, error) {
+	head := make([]byte, min(size, maxWAVHeaderSize))
+	if _, err := file.ReadAt(head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return 0, nil, fmt.Errorf("error reading source header: %v", err)
+	}
+
+	if len(head) < 4 {
+		// Too short to tell raw PCM from a WAV header being written
+		return 0, nil, nil
+	}
+	if !bytes.Equal(head[:4], []byte("RIFF")) {
+		frameSize := int64(2 * max(channels, 1))
+		return size / frameSize * frameSize, nil, nil
+	}
+
+	format, ok, err := parseWAVHeader(head)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		// The header is still being written, read the file from its start
+		return 0, nil, nil
+	}
+
+	dataOffset := int64(format.dataOffset)
+	frameSize := int64(2 * format.channels)
+	return dataOffset + (size-dataOffset)/frameSize*frameSize, head[:format.dataOffset], nil
+}
+
+// wavFormat is the PCM layout announced by a WAV header
+type wavFormat struct {
+	channels   int
+	sampleRate int
+	dataOffset int // Position of the first sample in the stream
+}
+
+// parseWAVHeader walks the RIFF chunks at the start of data up to the data chunk.
+// It returns false when data ends before the data chunk, and an error for anything
+// but 16-bit PCM.
+func parseWAVHeader(data []byte) (wavFormat, bool, error) {
+	if len(data) < 12 {
+		return wavFormat{}, false, nil
+	}
+	if !bytes.Equal(data[:4], []byte("RIFF")) || !bytes.Equal(data[8:12], []byte("WAVE")) {
+		return wavFormat{}, false, fmt.Errorf("invalid WAV header")
+	}
+
+	var format wavFormat
+	offset := 12
+	for offset+8 <= len(data) {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return wavFormat{}, false, fmt.Errorf("invalid WAV fmt chunk of %d bytes", size)
+			}
+			if body+16 > len(data) {
+				return wavFormat{}, false, nil
+			}
+			encoding := binary.LittleEndian.Uint16(data[body : body+2])
+			channels := int(binary.LittleEndian.Uint16(data[body+2 : body+4]))
+			bitsPerSample := binary.LittleEndian.Uint16(data[body+14 : body+16])
+			if encoding != 1 && encoding != 0xFFFE { // PCM or WAVE_FORMAT_EXTENSIBLE
+				return wavFormat{}, false, fmt.Errorf("unsupported WAV encoding %d, need PCM", encoding)
+			}
+			if channels == 0 {
+				return wavFormat{}, false, fmt.Errorf("invalid WAV header with 0 channels")
+			}
+			if bitsPerSample != 16 {
+				return wavFormat{}, false, fmt.Errorf("unsupported WAV sample size of %d bits, need 16", bitsPerSample)
+			}
+			format.channels = channels
+			format.sampleRate = int(binary.LittleEndian.Uint32(data[body+4 : body+8]))
+		case "data":
+			if format.channels == 0 {
+				return wavFormat{}, false, fmt.Errorf("WAV data chunk before the fmt chunk")
+			}
+			format.dataOffset = body
+			return format, true, nil
+		}
+
+		// Chunks are padded to an even size
+		offset = body + size + size%2
+	}
+	return wavFormat{}, false, nil
+}
+
+// tailReader reads a file that is still being appended to, waiting at EOF
+// for more data until the context is cancelled. The header is read first.
+type tailReader struct {
+	file   *os.File
+	ctx    context.Context
+	header []byte
+}
+
+func (t *tailReader) Read(p []byte) (int, error) {
+	if len(t.header) > 0 {
+		n := copy(p, t.header)
+		t.header = t.header[n:]
+		return n, nil
+	}
+
+	for {
+		n, err := t.file.Read(p)
+		if n > 0 || !errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		select {
+		case <-t.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(tailPollInterval):
+		}
+	}
+}
+
+func (t *tailReader) Close() error {
+	return t.file.Close()
+}
+
+// pcmDecoder converts interleaved 16-bit little-endian PCM into mono samples,
+// keeping partial frames between reads
+type pcmDecoder struct {
+	channels   int
+	sampleRate int
+	pending    []byte
+	started    bool
+}
+
+// newPCMDecoder creates a decoder for the configured channel layout, which a
+// WAV header at the start of the stream overrides
+func newPCMDecoder(sampleRate, channels int) *pcmDecoder {
+	if channels <= 0 {
+		channels = 1
+	}
+	return &pcmDecoder{channels: channels, sampleRate: sampleRate}
+}
+
+// decode appends the bytes to any pending partial frame and returns the complete
+// mono samples. A WAV header at the very start of the stream is parsed and skipped,
+// the error reports a header that is not 16-bit PCM.
+func (d *pcmDecoder) decode(data []byte) ([]float64, error) {
+	d.pending = append(d.pending, data...)
+
+	if !d.started {
+		if len(d.pending) < 4 {
+			return nil, nil
+		}
+		if bytes.Equal(d.pending[:4], []byte("RIFF")) {
+			format, ok, err := parseWAVHeader(d.pending)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				if len(d.pending) > maxWAVHeaderSize {
+					return nil, fmt.Errorf("no WAV data chunk in the first %d bytes", maxWAVHeaderSize)
+				}
+				return nil, nil
+			}
+			d.channels = format.channels
+			d.sampleRate = format.sampleRate
+			d.pending = d.pending[format.dataOffset:]
+		}
+		d.started = true
+	}
+
+	frameSize := 2 * d.channels
+	frames := len(d.pending) / frameSize
+	samples := make([]float64, frames)
+
+	for i := 0; i < frames; i++ {
+		var sum float64
+		for c := 0; c < d.channels; c++ {
+			offset := i*frameSize + c*2
+			sum += float64(int16(binary.LittleEndian.Uint16(d.pending[offset:offset+2]))) / 32768.0
+		}
+		samples[i] = sum / float64(d.channels)
+	}
+
+	d.pending = append(d.pending[:0], d.pending[frames*frameSize:]...)
+	return samples, nil
+}
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// wavHeader builds a WAV header up to the data chunk, with the extra chunk between
+// the fmt and data chunks when it is not nil
+func wavHeader(encoding, channels, bitsPerSample uint16, sampleRate uint32, extra []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(0xFFFFFFFF))
+	b.WriteString("WAVE")
+
+	b.WriteString("fmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	binary.Write(&b, binary.LittleEndian, encoding)
+	binary.Write(&b, binary.LittleEndian, channels)
+	binary.Write(&b, binary.LittleEndian, sampleRate)
+	binary.Write(&b, binary.LittleEndian, sampleRate*uint32(channels)*uint32(bitsPerSample/8))
+	binary.Write(&b, binary.LittleEndian, channels*bitsPerSample/8)
+	binary.Write(&b, binary.LittleEndian, bitsPerSample)
+
+	if extra != nil {
+		b.WriteString("LIST")
+		binary.Write(&b, binary.LittleEndian, uint32(len(extra)))
+		b.Write(extra)
+		if len(extra)%2 == 1 {
+			b.WriteByte(0)
+		}
+	}
+
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(0xFFFFFFFF))
+	return b.Bytes()
+}
+
+// pcm encodes interleaved 16-bit samples
+func pcm(values ...int16) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, values)
+	return b.Bytes()
+}
+
+func TestDecodeRejectsUnsupportedHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+	}{
+		{"no channels", wavHeader(1, 0, 16, 8000, nil)},
+		{"8-bit", wavHeader(1, 1, 8, 8000, nil)},
+		{"float", wavHeader(3, 1, 16, 8000, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := newPCMDecoder(11025, 1)
+			if _, err := decoder.decode(append(tt.header, pcm(1, 2, 3, 4)...)); err == nil {
+				t.Fatal("decode accepted the header")
+			}
+		})
+	}
+}
+
+func TestDecodeFindsDataChunk(t *testing.T) {
+	header := wavHeader(1, 2, 16, 22050, []byte("odd"))
+	stream := append(header, pcm(16384, -16384, 8192, 8192, 100)...)
+
+	decoder := newPCMDecoder(11025, 1)
+	var samples []float64
+	// Byte by byte, so that the header and the frames arrive split
+	for _, b := range stream {
+		decoded, err := decoder.decode([]byte{b})
+		if err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, decoded...)
+	}
+
+	if decoder.channels != 2 || decoder.sampleRate != 22050 {
+		t.Fatalf("format = %d channels at %d Hz, want 2 at 22050", decoder.channels, decoder.sampleRate)
+	}
+	if want := []float64{0, 0.25}; len(samples) != len(want) || samples[0] != want[0] || samples[1] != want[1] {
+		t.Fatalf("samples = %v, want %v", samples, want)
+	}
+}
+
+func TestOpenSourceTailsFromFrameBoundary(t *testing.T) {
+	header := wavHeader(1, 2, 16, 8000, []byte("metadata"))
+	// Two full stereo frames and the first byte of a third already written
+	written := append(append([]byte{}, header...), pcm(1, 2, 3, 4)...)
+	written = append(written, pcm(16384)[0])
+
+	path := filepath.Join(t.TempDir(), "feed.wav")
+	if err := os.WriteFile(path, written, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source, err := openSource(ctx, path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	// The writer completes the frame, then appends one more
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write(pcm(16384, 16384)[1:])
+	file.Write(pcm(-16384, -16384))
+	file.Close()
+
+	buf := make([]byte, len(header)+8)
+	if _, err := io.ReadFull(source, buf); err != nil {
+		t.Fatal(err)
+	}
+	decoder := newPCMDecoder(44100, 1)
+	samples, err := decoder.decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoder.channels != 2 || decoder.sampleRate != 8000 {
+		t.Fatalf("format = %d channels at %d Hz, want 2 at 8000", decoder.channels, decoder.sampleRate)
+	}
+	// The partial frame is read from its first byte, so the samples stay aligned
+	if want := []float64{0.5, -0.5}; len(samples) != 2 || samples[0] != want[0] || samples[1] != want[1] {
+		t.Fatalf("samples = %v, want %v", samples, want)
+	}
+}
+
+func TestTailStartRawPCM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.pcm")
+	if err := os.WriteFile(path, make([]byte, 4*100+3), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	offset, header, err := tailStart(file, 4*100+3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 400 || header != nil {
+		t.Fatalf("tailStart = %d, %v, want 400 without header", offset, header)
+	}
+}
@@ -0,0 +1,408 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+)
+
+const (
+	// defaultPageSize is the number of songs listed when the request sets no limit
+	defaultPageSize = 50
+	// maxPageSize caps the limit parameter of GET /songs
+	maxPageSize = 500
+)
+
+// recognizeResponse is the body of POST /recognize
+type recognizeResponse struct {
+	Matches []eureka.Match `json:"matches"`
+}
+
+// songResponse is a song as returned by the /songs endpoints
+type songResponse struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Artist        string `json:"artist"`
+	Album         string `json:"album"`
+	Fingerprinted *bool  `json:"fingerprinted,omitempty"`
+	FileSHA1      string `json:"file_sha1,omitempty"`
+	TotalHashes   *int   `json:"total_hashes,omitempty"`
+	DateCreated   string `json:"date_created,omitempty"`
+}
+
+// songUpdateRequest is the body of PATCH /songs/{id}, absent fields are unchanged
+type songUpdateRequest struct {
+	Name   *string `json:"name"`
+	Artist *string `json:"artist"`
+	Album  *string `json:"album"`
+}
+
+// songListResponse is the body of GET /songs
+type songListResponse struct {
+	Songs  []songResponse `json:"songs"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// recognitionResponse is a recognition as returned by GET /history
+type recognitionResponse struct {
+	ID           int64                    `json:"id"`
+	RecognizedAt time.Time                `json:"recognized_at"`
+	Source       string                   `json:"source"`
+	ClientID     string                   `json:"client_id,omitempty"`
+	AudioMs      int64                    `json:"audio_ms"`
+	LatencyMs    int64                    `json:"latency_ms"`
+	Matches      []mysql.RecognitionMatch `json:"matches"`
+}
+
+// historyResponse is the body of GET /history
+type historyResponse struct {
+	Recognitions []recognitionResponse `json:"recognitions"`
+	Total        int                   `json:"total"`
+	Limit        int                   `json:"limit"`
+	Offset       int                   `json:"offset"`
+}
+
+// handleRecognize matches the audio uploaded in the "file" form field
+func (s *Server) handleRecognize(w http.ResponseWriter, r *http.Request) {
+	file, _, err := s.formFile(w, r)
+	if err != nil {
+		writeError(w, uploadStatus(err), err)
+		return
+	}
+	defer file.Close()
+
+	matches, err := s.service.RecognizeReader(r.Context(), file)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	if matches == nil {
+		matches = []eureka.Match{}
+	}
+	writeJSON(w, http.StatusOK, recognizeResponse{Matches: matches})
+}
+
+// handleCreateSong ingests the song uploaded in the "file" form field. The
+// "title" and "artist" fields default to the "Artist--Title.ext" file name.
+func (s *Server) handleCreateSong(w http.ResponseWriter, r *http.Request) {
+	file, header, err := s.formFile(w, r)
+	if err != nil {
+		writeError(w, uploadStatus(err), err)
+		return
+	}
+	defer file.Close()
+
+	artist, title := eureka.ParseSongFilename(header.Filename)
+	if v := strings.TrimSpace(r.FormValue("title")); v != "" {
+		title = v
+	}
+	if v := strings.TrimSpace(r.FormValue("artist")); v != "" {
+		artist = v
+	}
+	if title == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing song title"))
+		return
+	}
+
+	// Decoding picks the format by extension, keep it on the temporary copy
+	tmp, err := os.CreateTemp("", "eureka-upload-*"+strings.ToLower(filepath.Ext(header.Filename)))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("error creating temporary file: %v", err))
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, file)
+	tmp.Close()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("error storing upload: %v", err))
+		return
+	}
+
+	songID, err := s.service.SaveSong(r.Context(), tmp.Name(), title, artist)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, songResponse{ID: songID, Name: title, Artist: artist})
+}
+
+// handleListSongs lists songs, paginated with the limit and offset query parameters.
+// The name and artist parameters filter by substring, fingerprinted by status and
+// created_after and created_before (RFC 3339) by creation time; sort and order
+// (asc or desc) sort them.
+func (s *Server) handleListSongs(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultPageSize)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("offset must be a non-negative integer"))
+		return
+	}
+
+	params := r.URL.Query()
+	query := catalog.SongQuery{
+		Name:   params.Get("name"),
+		Artist: params.Get("artist"),
+		Sort:   params.Get("sort"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if v := params.Get("fingerprinted"); v != "" {
+		fingerprinted, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("fingerprinted must be true or false"))
+			return
+		}
+		query.Fingerprinted = &fingerprinted
+	}
+	for _, bound := range []struct {
+		name  string
+		value *time.Time
+	}{{"created_after", &query.CreatedAfter}, {"created_before", &query.CreatedBefore}} {
+		if v := params.Get(bound.name); v != "" {
+			if *bound.value, err = time.Parse(time.RFC3339, v); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%s must be an RFC 3339 time", bound.name))
+				return
+			}
+		}
+	}
+	switch params.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("order must be asc or desc"))
+		return
+	}
+
+	page, err := s.service.List(r.Context(), query)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	resp := songListResponse{Songs: []songResponse{}, Total: page.Total, Limit: limit, Offset: offset}
+	for _, song := range page.Songs {
+		resp.Songs = append(resp.Songs, newSongResponse(song))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleGetSong returns a single song
+func (s *Server) handleGetSong(w http.ResponseWriter, r *http.Request) {
+	songID, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	song, err := s.service.GetSong(r.Context(), songID)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, songResponse{ID: song.ID, Name: song.Name, Artist: song.Artist, Album: song.Album})
+}
+
+// handleUpdateSong changes the name, artist or album given in the JSON body
+func (s *Server) handleUpdateSong(w http.ResponseWriter, r *http.Request) {
+	songID, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req songUpdateRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, uploadStatus(err), fmt.Errorf("invalid JSON body: %w", err))
+		return
+	}
+	if req.Name == nil && req.Artist == nil && req.Album == nil {
+		writeError(w, http.StatusBadRequest, errors.New("nothing to change, set name, artist or album"))
+		return
+	}
+
+	song, err := s.service.UpdateSong(r.Context(), songID, mysql.SongUpdate{Name: req.Name, Artist: req.Artist, Album: req.Album})
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, songResponse{ID: song.ID, Name: song.Name, Artist: song.Artist, Album: song.Album})
+}
+
+// handleDeleteSong deletes a song and its fingerprints
+func (s *Server) handleDeleteSong(w http.ResponseWriter, r *http.Request) {
+	songID, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.service.Delete(r.Context(), songID); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCleanup removes duplicate and unfingerprinted songs and orphaned fingerprints
+func (s *Server) handleCleanup(w http.ResponseWriter, r *http.Request) {
+	if err := s.service.Cleanup(r.Context()); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// formFile limits the request body and returns the uploaded "file" form field
+func (s *Server) formFile(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadBytes)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		return nil, nil, fmt.Errorf("error parsing upload: %w", err)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing audio in form field \"file\": %w", err)
+	}
+	return file, header, nil
+}
+
+// uploadStatus maps an upload error to an HTTP status
+func uploadStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// pathID parses the {id} path parameter
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid song ID: %s", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// queryInt parses an integer query parameter, returning def when it is absent
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
+
+// newSongResponse converts a database song to its JSON form
+func newSongResponse(song mysql.Song) songResponse {
+	return songResponse{
+		ID:            song.ID,
+		Name:          song.Name,
+		Artist:        song.Artist,
+		Album:         song.Album,
+		Fingerprinted: &song.Fingerprinted,
+		FileSHA1:      song.FileSHA1,
+		TotalHashes:   &song.TotalHashes,
+		DateCreated:   song.DateCreated,
+	}
+}
+
+// handleHistory lists the recorded recognitions, newest first
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultPageSize)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxPageSize))
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("offset must be a non-negative integer"))
+		return
+	}
+	songID, err := queryInt(r, "song_id", 0)
+	if err != nil || songID < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("song_id must be a positive integer"))
+		return
+	}
+
+	params := r.URL.Query()
+	query := mysql.RecognitionQuery{
+		Source:   params.Get("source"),
+		ClientID: params.Get("client_id"),
+		SongID:   songID,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if query.Source != "" && !slices.Contains(eureka.Sources, query.Source) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("source must be one of %s", strings.Join(eureka.Sources, ", ")))
+		return
+	}
+	if v := params.Get("matched"); v != "" {
+		matched, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("matched must be true or false"))
+			return
+		}
+		query.Matched = &matched
+	}
+	for _, bound := range []struct {
+		name  string
+		value *time.Time
+	}{{"since", &query.Since}, {"until", &query.Until}} {
+		if v := params.Get(bound.name); v != "" {
+			if *bound.value, err = time.Parse(time.RFC3339, v); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%s must be an RFC 3339 time", bound.name))
+				return
+			}
+		}
+	}
+
+	page, err := s.service.History(r.Context(), query)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	resp := historyResponse{Recognitions: []recognitionResponse{}, Total: page.Total, Limit: limit, Offset: offset}
+	for _, rec := range page.Recognitions {
+		resp.Recognitions = append(resp.Recognitions, recognitionResponse{
+			ID:           rec.ID,
+			RecognizedAt: rec.RecognizedAt,
+			Source:       rec.Source,
+			ClientID:     rec.ClientID,
+			AudioMs:      rec.AudioMs,
+			LatencyMs:    rec.LatencyMs,
+			Matches:      rec.Matches,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
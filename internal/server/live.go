@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/eureka"
+	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+	"github.com/media-luna/eureka/utils/logger"
+)
+
+const (
+	// defaultLiveSeconds bounds live sessions when the configuration sets no limits
+	defaultLiveSeconds = 30
+	// maxLiveMessageBytes is the largest audio frame a client may send
+	maxLiveMessageBytes = 1 << 20
+	// liveWriteTimeout bounds sending a single event
+	liveWriteTimeout = 5 * time.Second
+)
+
+// Live session event types, in addition to the possible_match and match stream events
+const (
+	eventListening = "listening"
+	eventNoMatch   = "no_match"
+	eventError     = "error"
+)
+
+// liveEvent is a JSON text message sent to live recognition clients
+type liveEvent struct {
+	Type    string        `json:"type"`
+	Seconds float64       `json:"seconds"`
+	Match   *eureka.Match `json:"match,omitempty"`
+	Reason  string        `json:"reason,omitempty"` // Why a session ended without a match: timeout, audio_limit or end
+	Error   string        `json:"error,omitempty"`
+}
+
+// liveControl is a JSON text message sent by clients, {"type": "end"} marks the end of the audio
+type liveControl struct {
+	Type string `json:"type"`
+}
+
+// frameDecoder turns the binary messages of a live session into interleaved samples
+type frameDecoder interface {
+	Decode(frame []byte) ([]float32, error)
+}
+
+// newUpgrader creates the WebSocket upgrader of live sessions, accepting the origins
+// allowed by checkOrigin
+func (s *Server) newUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  16 * 1024,
+		WriteBufferSize: 4 * 1024,
+		CheckOrigin:     s.checkOrigin,
+	}
+}
+
+// checkOrigin accepts handshakes from clients other than browsers, which send no Origin
+// header, from pages served by the API's own host, and from server.allowed_origins
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleLive runs a live recognition session over a WebSocket. The audio format is
+// set with the encoding (s16le, s32le, f32le, u8 or opus), sample_rate and channels
+// query parameters. Clients send audio as binary messages, one Opus packet per
+// message, and receive JSON events until a match is found or the session ends.
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	decoder, sampleRate, channels, err := newFrameDecoder(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an error status
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxLiveMessageBytes)
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.liveTimeout)
+	defer cancel()
+
+	session := &liveSession{
+		conn:       conn,
+		decoder:    decoder,
+		converter:  fingerprint.NewConverter(sampleRate, channels),
+		recognizer: s.service.NewStreamRecognizer(eureka.DefaultStreamOptions()),
+		maxSeconds: s.liveMaxAudioSeconds,
+	}
+	defer session.recognizer.Close()
+
+	session.run(ctx)
+}
+
+// newFrameDecoder creates the decoder for the audio format requested in the query
+func newFrameDecoder(r *http.Request) (frameDecoder, int, int, error) {
+	query := r.URL.Query()
+
+	encoding := query.Get("encoding")
+	if encoding == "" {
+		encoding = "s16le"
+	}
+	sampleRate, err := queryInt(r, "sample_rate", fingerprint.SAMPLE_RATE)
+	if err != nil || sampleRate <= 0 {
+		return nil, 0, 0, fmt.Errorf("invalid sample_rate: %s", query.Get("sample_rate"))
+	}
+	channels, err := queryInt(r, "channels", 1)
+	if err != nil || channels < 1 || channels > 2 {
+		return nil, 0, 0, fmt.Errorf("invalid channels: %s, expected 1 or 2", query.Get("channels"))
+	}
+
+	if encoding == "opus" {
+		decoder, err := audio.NewOpusDecoder(sampleRate, channels)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return decoder, sampleRate, channels, nil
+	}
+
+	format, err := audio.ParsePCMFormat(encoding + ":" + strconv.Itoa(sampleRate) + ":" + strconv.Itoa(channels))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return audio.NewPCMFrameDecoder(format), sampleRate, channels, nil
+}
+
+// liveSession is the state of one WebSocket recognition session
+type liveSession struct {
+	conn       *websocket.Conn
+	decoder    frameDecoder
+	converter  *fingerprint.Converter
+	recognizer *eureka.StreamRecognizer
+	maxSeconds float64
+}
+
+// run feeds the client's audio to the recognizer until a match, the end of the
+// audio, the audio cap or ctx ends the session. It is the only writer on conn.
+func (ls *liveSession) run(ctx context.Context) {
+	frames, readErr := ls.readFrames(ctx)
+
+	if !ls.send(liveEvent{Type: eventListening}) {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				ls.finish(liveEvent{Type: eventNoMatch, Seconds: ls.recognizer.Seconds(), Reason: "timeout"})
+			}
+			return
+
+		case frame, ok := <-frames:
+			if !ok {
+				if err := <-readErr; err != nil {
+					// The client disconnected, nobody is left to notify
+					return
+				}
+				ls.finish(liveEvent{Type: eventNoMatch, Seconds: ls.recognizer.Seconds(), Reason: "end"})
+				return
+			}
+
+			if err := ls.write(ctx, frame); err != nil {
+				ls.finish(liveEvent{Type: eventError, Seconds: ls.recognizer.Seconds(), Error: err.Error()})
+				return
+			}
+			if done := ls.flushEvents(); done {
+				return
+			}
+			if ls.recognizer.Seconds() >= ls.maxSeconds {
+				ls.finish(liveEvent{Type: eventNoMatch, Seconds: ls.recognizer.Seconds(), Reason: "audio_limit"})
+				return
+			}
+		}
+	}
+}
+
+// readFrames reads binary audio messages in the background. The frames channel
+// is closed when the client ends the audio or the connection fails, the error (nil
+// for a clean end) is then available on the second channel.
+func (ls *liveSession) readFrames(ctx context.Context) (<-chan []byte, <-chan error) {
+	frames := make(chan []byte, 16)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(frames)
+		for {
+			messageType, data, err := ls.conn.ReadMessage()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if messageType == websocket.TextMessage {
+				var control liveControl
+				if json.Unmarshal(data, &control) == nil && control.Type == "end" {
+					errs <- nil
+					return
+				}
+				continue
+			}
+
+			select {
+			case frames <- data:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return frames, errs
+}
+
+// write decodes a frame, converts it to 44100 Hz mono and feeds it to the recognizer
+func (ls *liveSession) write(ctx context.Context, frame []byte) error {
+	samples, err := ls.decoder.Decode(frame)
+	if err != nil {
+		return err
+	}
+	return ls.recognizer.WriteContext(ctx, ls.converter.Convert(samples))
+}
+
+// flushEvents forwards pending recognizer events and reports whether a match ended the session
+func (ls *liveSession) flushEvents() bool {
+	for {
+		select {
+		case event := <-ls.recognizer.Events():
+			match := event.Match
+			if event.Type == eureka.EventMatch {
+				ls.finish(liveEvent{Type: string(event.Type), Seconds: event.Seconds, Match: &match})
+				return true
+			}
+			if !ls.send(liveEvent{Type: string(event.Type), Seconds: event.Seconds, Match: &match}) {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+// send writes an event and reports whether the client is still reachable
+func (ls *liveSession) send(event liveEvent) bool {
+	ls.conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	if err := ls.conn.WriteJSON(event); err != nil {
+		logger.Warn("Error sending live event", "error", err)
+		return false
+	}
+	return true
+}
+
+// finish sends the final event and closes the WebSocket cleanly
+func (ls *liveSession) finish(event liveEvent) {
+	if !ls.send(event) {
+		return
+	}
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, event.Type)
+	ls.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(liveWriteTimeout))
+}
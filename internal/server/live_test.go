@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/internal/eurekatest"
+)
+
+// liveService recognizes live sessions against a catalog. Methods not needed by the
+// tests panic through the nil embedded Service.
+type liveService struct {
+	Service
+	eureka *eureka.Eureka
+}
+
+func (l *liveService) NewStreamRecognizer(opts eureka.StreamOptions) *eureka.StreamRecognizer {
+	return l.eureka.NewStreamRecognizer(opts)
+}
+
+// startLive serves the API with catalog behind a real listener and returns the URL of
+// its live endpoint
+func startLive(t *testing.T, catalog *eurekatest.Catalog, cfg config.Server) string {
+	t.Helper()
+	service := &liveService{eureka: eureka.NewEurekaWithDatabase(config.Default(), catalog)}
+	srv := httptest.NewServer(NewServer(service, cfg).Handler())
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/live"
+}
+
+// dialLive opens a live session, failing the test unless the handshake succeeds
+func dialLive(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("dial %s: %v (status %d)", url, err, status)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// sendAudio sends pcm as binary messages of an odd size
+func sendAudio(conn *websocket.Conn, pcm []byte) error {
+	for len(pcm) > 0 {
+		n := min(len(pcm), 6002)
+		if err := conn.WriteMessage(websocket.BinaryMessage, pcm[:n]); err != nil {
+			return err
+		}
+		pcm = pcm[n:]
+	}
+	return nil
+}
+
+// readEvents reads events until the server closes the session with a normal closure
+func readEvents(t *testing.T, conn *websocket.Conn) []liveEvent {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var events []liveEvent
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				t.Fatalf("session ended with %v after %+v", err, events)
+			}
+			return events
+		}
+		var event liveEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		events = append(events, event)
+	}
+}
+
+// checkSequence checks that events start with listening, carry only possible matches
+// in between, and end with last
+func checkSequence(t *testing.T, events []liveEvent, last liveEvent) {
+	t.Helper()
+	if len(events) < 2 || events[0].Type != eventListening {
+		t.Fatalf("events = %+v, want listening first", events)
+	}
+	for _, event := range events[1 : len(events)-1] {
+		if event.Type != string(eureka.EventPossibleMatch) {
+			t.Fatalf("events = %+v, want only possible matches before the last one", events)
+		}
+	}
+	got := events[len(events)-1]
+	if got.Type != last.Type || got.Reason != last.Reason {
+		t.Fatalf("last event = %+v, want type %q and reason %q", got, last.Type, last.Reason)
+	}
+}
+
+func TestLiveMatch(t *testing.T) {
+	pcm, samples := eurekatest.ChirpPCM(t, 10)
+	catalog := &eurekatest.Catalog{}
+	catalog.Add(mysql.SongInfo{ID: 1, Name: "Chirp", Artist: "Generator"}, eurekatest.Fingerprints(t, samples), 2000)
+
+	conn := dialLive(t, startLive(t, catalog, config.Server{})+"?encoding=s16le&sample_rate=44100&channels=1")
+	// The session may end before all the audio is sent
+	go sendAudio(conn, pcm)
+
+	events := readEvents(t, conn)
+	checkSequence(t, events, liveEvent{Type: string(eureka.EventMatch)})
+	match := events[len(events)-1].Match
+	if match == nil || match.SongID != 1 || match.SongName != "Chirp" {
+		t.Fatalf("match = %+v, want song 1", match)
+	}
+	if drift := match.Offset - 2000; drift < -100 || drift > 100 {
+		t.Fatalf("match offset = %d ms, want 2000", match.Offset)
+	}
+}
+
+func TestLiveEnd(t *testing.T) {
+	pcm, _ := eurekatest.ChirpPCM(t, 2)
+	conn := dialLive(t, startLive(t, &eurekatest.Catalog{}, config.Server{}))
+	if err := sendAudio(conn, pcm); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteJSON(liveControl{Type: "end"}); err != nil {
+		t.Fatal(err)
+	}
+
+	events := readEvents(t, conn)
+	checkSequence(t, events, liveEvent{Type: eventNoMatch, Reason: "end"})
+	if seconds := events[len(events)-1].Seconds; seconds < 1 || seconds > 2 {
+		t.Fatalf("no_match after %v seconds, want the 2 seconds sent", seconds)
+	}
+}
+
+func TestLiveAudioLimit(t *testing.T) {
+	pcm, _ := eurekatest.ChirpPCM(t, 4)
+	conn := dialLive(t, startLive(t, &eurekatest.Catalog{}, config.Server{LiveMaxAudioSeconds: 1}))
+	// The session may end before all the audio is sent
+	go sendAudio(conn, pcm)
+
+	events := readEvents(t, conn)
+	checkSequence(t, events, liveEvent{Type: eventNoMatch, Reason: "audio_limit"})
+	if seconds := events[len(events)-1].Seconds; seconds < 1 || seconds > 2 {
+		t.Fatalf("no_match after %v seconds, want just over the 1 second limit", seconds)
+	}
+}
+
+func TestLiveTimeout(t *testing.T) {
+	conn := dialLive(t, startLive(t, &eurekatest.Catalog{}, config.Server{LiveTimeoutSeconds: 1}))
+
+	start := time.Now()
+	events := readEvents(t, conn)
+	checkSequence(t, events, liveEvent{Type: eventNoMatch, Reason: "timeout"})
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond || elapsed > 5*time.Second {
+		t.Fatalf("timeout after %v, want 1s", elapsed)
+	}
+}
+
+func TestLiveInvalidFormat(t *testing.T) {
+	url := startLive(t, &eurekatest.Catalog{}, config.Server{})
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"unknown encoding", "encoding=mp3", "mp3"},
+		{"zero sample rate", "sample_rate=0", "invalid sample_rate: 0"},
+		{"sample rate not a number", "sample_rate=fast", "invalid sample_rate: fast"},
+		{"too many channels", "channels=6", "invalid channels: 6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp, err := websocket.DefaultDialer.Dial(url+"?"+tt.query, nil)
+			if err == nil {
+				t.Fatal("handshake succeeded")
+			}
+			if resp == nil || resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("response = %+v, want 400", resp)
+			}
+			var body map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(body["error"], tt.want) {
+				t.Fatalf("error %q does not mention %q", body["error"], tt.want)
+			}
+		})
+	}
+}
+
+func TestLiveOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  func(url string) string
+		want    bool
+	}{
+		{"no origin", nil, func(string) string { return "" }, true},
+		{"same origin", nil, func(url string) string { return "http" + strings.TrimSuffix(strings.TrimPrefix(url, "ws"), "/live") }, true},
+		{"other origin", nil, func(string) string { return "https://app.example.com" }, false},
+		{"allowed origin", []string{"https://app.example.com"}, func(string) string { return "https://APP.example.com" }, true},
+		{"other allowed origin", []string{"https://app.example.com"}, func(string) string { return "https://evil.example.com" }, false},
+		{"any origin", []string{"*"}, func(string) string { return "https://evil.example.com" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := startLive(t, &eurekatest.Catalog{}, config.Server{AllowedOrigins: tt.allowed})
+			header := http.Header{}
+			if origin := tt.origin(url); origin != "" {
+				header.Set("Origin", origin)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			conn, resp, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+			if err == nil {
+				conn.Close()
+			}
+			if got := err == nil; got != tt.want {
+				t.Fatalf("handshake succeeded = %v, want %v (%v)", got, tt.want, err)
+			}
+			if !tt.want && (resp == nil || resp.StatusCode != http.StatusForbidden) {
+				t.Fatalf("response = %+v, want 403", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/internal/metrics"
+	"github.com/media-luna/eureka/utils/logger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	// defaultMaxUploadMB is used when the configuration sets no upload limit
+	defaultMaxUploadMB = 50
+	// multipartMemory is how much of a multipart upload is kept in memory, the rest spills to disk
+	multipartMemory = 8 << 20
+	// maxJSONBytes limits JSON request bodies
+	maxJSONBytes = 64 << 10
+	// shutdownTimeout bounds waiting for in-flight requests when the server stops
+	shutdownTimeout = 10 * time.Second
+	// clientHeader names the API client in the recognition history, the remote
+	// address is recorded without it
+	clientHeader = "X-Client-ID"
+)
+
+// Service is the part of the Eureka API exposed over HTTP. *eureka.Eureka
+// implements it; tests can pass a fake and drive the handler with httptest.
+type Service interface {
+	RecognizeReader(ctx context.Context, r io.Reader) ([]eureka.Match, error)
+	SaveSong(ctx context.Context, path string, title string, artist string) (int, error)
+	List(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error)
+	GetSong(ctx context.Context, songID int) (mysql.SongInfo, error)
+	UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) (mysql.SongInfo, error)
+	Delete(ctx context.Context, songID int) error
+	Cleanup(ctx context.Context) error
+	History(ctx context.Context, query mysql.RecognitionQuery) (mysql.RecognitionPage, error)
+	NewStreamRecognizer(opts eureka.StreamOptions) *eureka.StreamRecognizer
+}
+
+// Server exposes a Service as a JSON REST API. All requests share the service,
+// and therefore its database connection pool.
+type Server struct {
+	service             Service
+	cfg                 config.Server
+	maxUploadBytes      int64
+	liveTimeout         time.Duration
+	liveMaxAudioSeconds float64
+	upgrader            *websocket.Upgrader
+}
+
+// NewServer creates a server for service with the given settings
+func NewServer(service Service, cfg config.Server) *Server {
+	maxUploadMB := cfg.MaxUploadMB
+	if maxUploadMB <= 0 {
+		maxUploadMB = defaultMaxUploadMB
+	}
+
+	liveTimeout := cfg.LiveTimeoutSeconds
+	if liveTimeout <= 0 {
+		liveTimeout = defaultLiveSeconds
+	}
+	liveMaxAudio := cfg.LiveMaxAudioSeconds
+	if liveMaxAudio <= 0 {
+		liveMaxAudio = defaultLiveSeconds
+	}
+
+	s := &Server{
+		service:             service,
+		cfg:                 cfg,
+		maxUploadBytes:      int64(maxUploadMB) << 20,
+		liveTimeout:         time.Duration(liveTimeout) * time.Second,
+		liveMaxAudioSeconds: float64(liveMaxAudio),
+	}
+	s.upgrader = s.newUpgrader()
+	return s
+}
+
+// Handler returns the HTTP handler with every API route
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	// Each API request is a span named after its route, child of the caller's trace context
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle(pattern, otelhttp.NewHandler(handler, pattern))
+	}
+	handle("POST /recognize", s.handleRecognize)
+	handle("POST /songs", s.handleCreateSong)
+	handle("GET /songs", s.handleListSongs)
+	handle("GET /songs/{id}", s.handleGetSong)
+	handle("PATCH /songs/{id}", s.handleUpdateSong)
+	handle("DELETE /songs/{id}", s.handleDeleteSong)
+	handle("POST /admin/cleanup", s.handleCleanup)
+	handle("GET /live", s.handleLive)
+	handle("GET /history", s.handleHistory)
+	mux.Handle("GET /metrics", metrics.Handler())
+	return withClient(mux)
+}
+
+// withClient tags the request context with the client recorded in the recognition history
+func withClient(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID := strings.TrimSpace(r.Header.Get(clientHeader))
+		if clientID == "" {
+			clientID = r.RemoteAddr
+			if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+				clientID = host
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(eureka.WithClient(r.Context(), clientID)))
+	})
+}
+
+// ListenAndServe serves the API on the configured address until ctx is cancelled,
+// then waits for in-flight requests to finish
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.cfg.Address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	logger.Info("API listening", "address", s.cfg.Address)
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("error serving API: %v", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down API server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("error shutting down API: %v", err)
+	}
+	return nil
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON writes v as the response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("Error writing response", "error", err)
+	}
+}
+
+// writeError writes an error response, internal errors are logged and not exposed
+func writeError(w http.ResponseWriter, status int, err error) {
+	message := err.Error()
+	if status >= http.StatusInternalServerError {
+		logger.Error(err)
+		message = http.StatusText(status)
+	}
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// statusFor maps a service error to an HTTP status
+func statusFor(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, mysql.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, eureka.ErrInvalidAudio):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, eureka.ErrInvalidMetadata), errors.Is(err, catalog.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, errors.ErrUnsupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, context.Canceled):
+		// The client went away, nobody reads the status
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
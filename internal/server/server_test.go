@@ -0,0 +1,246 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eureka"
+	"github.com/media-luna/eureka/internal/eurekatest"
+)
+
+// fakeService serves a fixed catalog. Methods not needed by the tests panic through
+// the nil embedded Service.
+type fakeService struct {
+	Service
+	songs      []mysql.Song
+	lastQuery  catalog.SongQuery
+	recognized int               // Bytes read by RecognizeReader
+	span       trace.SpanContext // Span of the last GetSong call
+}
+
+func (f *fakeService) RecognizeReader(ctx context.Context, r io.Reader) ([]eureka.Match, error) {
+	n, err := io.Copy(io.Discard, r)
+	f.recognized = int(n)
+	return nil, err
+}
+
+func (f *fakeService) GetSong(ctx context.Context, songID int) (mysql.SongInfo, error) {
+	f.span = trace.SpanContextFromContext(ctx)
+	for _, song := range f.songs {
+		if song.ID == songID {
+			return mysql.SongInfo{ID: song.ID, Name: song.Name, Artist: song.Artist, Album: song.Album}, nil
+		}
+	}
+	return mysql.SongInfo{}, fmt.Errorf("%w: song %d", mysql.ErrNotFound, songID)
+}
+
+func (f *fakeService) List(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error) {
+	f.lastQuery = query
+	if err := query.Check(); err != nil {
+		return mysql.SongPage{}, err
+	}
+	page := mysql.SongPage{Total: len(f.songs)}
+	for i := query.Offset; i < len(f.songs) && len(page.Songs) < query.Limit; i++ {
+		page.Songs = append(page.Songs, f.songs[i])
+	}
+	return page, nil
+}
+
+func (f *fakeService) Cleanup(ctx context.Context) error {
+	return errors.New("connection refused by db.internal:3306")
+}
+
+func newTestServer(cfg config.Server) (*fakeService, http.Handler) {
+	service := &fakeService{}
+	for i := 1; i <= 5; i++ {
+		service.songs = append(service.songs, mysql.Song{ID: i, Name: fmt.Sprintf("Song %d", i), Artist: "Artist"})
+	}
+	return service, NewServer(service, cfg).Handler()
+}
+
+// serve runs one request and decodes the JSON body into v, unless v is nil
+func serve(t *testing.T, handler http.Handler, req *http.Request, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+// checkError checks that the response is a JSON error with the given status and a
+// body made of the error message only
+func checkError(t *testing.T, rec *httptest.ResponseRecorder, status int) string {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d, body %s", rec.Code, status, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON error %q: %v", rec.Body.String(), err)
+	}
+	message, ok := body["error"].(string)
+	if len(body) != 1 || !ok || message == "" {
+		t.Fatalf("error body = %s, want {\"error\": message}", rec.Body.String())
+	}
+	return message
+}
+
+// upload builds a POST request with content in the "file" form field
+func upload(t *testing.T, target string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "sample.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestRecognizeUploadLimit(t *testing.T) {
+	service, handler := newTestServer(config.Server{MaxUploadMB: 1})
+
+	rec := serve(t, handler, upload(t, "/recognize", make([]byte, 512<<10)), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body %s", rec.Code, rec.Body.String())
+	}
+	if service.recognized != 512<<10 {
+		t.Fatalf("recognized %d bytes, want %d", service.recognized, 512<<10)
+	}
+
+	rec = serve(t, handler, upload(t, "/recognize", make([]byte, 2<<20)), nil)
+	checkError(t, rec, http.StatusRequestEntityTooLarge)
+}
+
+func TestRecognizeMissingFile(t *testing.T) {
+	_, handler := newTestServer(config.Server{})
+	req := httptest.NewRequest(http.MethodPost, "/recognize", strings.NewReader("audio"))
+	req.Header.Set("Content-Type", "audio/wav")
+	checkError(t, serve(t, handler, req, nil), http.StatusBadRequest)
+}
+
+func TestGetSong(t *testing.T) {
+	_, handler := newTestServer(config.Server{})
+
+	var song songResponse
+	rec := serve(t, handler, httptest.NewRequest(http.MethodGet, "/songs/3", nil), &song)
+	if rec.Code != http.StatusOK || song.ID != 3 || song.Name != "Song 3" {
+		t.Fatalf("GET /songs/3 = %d %+v", rec.Code, song)
+	}
+
+	message := checkError(t, serve(t, handler, httptest.NewRequest(http.MethodGet, "/songs/42", nil), nil), http.StatusNotFound)
+	if !strings.Contains(message, "song 42") {
+		t.Fatalf("error = %q, want the unknown song", message)
+	}
+
+	checkError(t, serve(t, handler, httptest.NewRequest(http.MethodGet, "/songs/abc", nil), nil), http.StatusBadRequest)
+}
+
+func TestListSongsPagination(t *testing.T) {
+	service, handler := newTestServer(config.Server{})
+
+	var page songListResponse
+	rec := serve(t, handler, httptest.NewRequest(http.MethodGet, "/songs?limit=2&offset=3&order=desc&fingerprinted=true", nil), &page)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if page.Total != 5 || page.Limit != 2 || page.Offset != 3 || len(page.Songs) != 2 || page.Songs[0].ID != 4 {
+		t.Fatalf("page = %+v", page)
+	}
+	if q := service.lastQuery; q.Limit != 2 || q.Offset != 3 || !q.Descending || q.Fingerprinted == nil || !*q.Fingerprinted {
+		t.Fatalf("query = %+v", q)
+	}
+
+	// Pages past the end are empty, not null
+	rec = serve(t, handler, httptest.NewRequest(http.MethodGet, "/songs?offset=10", nil), nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"songs":[]`) {
+		t.Fatalf("GET /songs?offset=10 = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListSongsValidation(t *testing.T) {
+	_, handler := newTestServer(config.Server{})
+
+	for _, query := range []string{
+		"limit=0",
+		"limit=501",
+		"limit=ten",
+		"offset=-1",
+		"order=sideways",
+		"fingerprinted=maybe",
+		"created_after=yesterday",
+		"sort=popularity", // Rejected by the query itself
+		"created_after=2024-02-01T00:00:00Z&created_before=2024-01-01T00:00:00Z",
+	} {
+		t.Run(query, func(t *testing.T) {
+			checkError(t, serve(t, handler, httptest.NewRequest(http.MethodGet, "/songs?"+query, nil), nil), http.StatusBadRequest)
+		})
+	}
+}
+
+func TestInternalErrorsAreNotExposed(t *testing.T) {
+	_, handler := newTestServer(config.Server{})
+
+	message := checkError(t, serve(t, handler, httptest.NewRequest(http.MethodPost, "/admin/cleanup", nil), nil), http.StatusInternalServerError)
+	if message != http.StatusText(http.StatusInternalServerError) {
+		t.Fatalf("error = %q, want the status text only", message)
+	}
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	recorder := eurekatest.RecordSpans(t)
+
+	service, handler := newTestServer(config.Server{})
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodGet, "/songs/3", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+spanID+"-01")
+	if rec := serve(t, handler, req, nil); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans ended, want the request span", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /songs/{id}" {
+		t.Fatalf("span name = %q, want the route", span.Name())
+	}
+	if span.SpanContext().TraceID().String() != traceID || span.Parent().SpanID().String() != spanID || !span.Parent().IsRemote() {
+		t.Fatalf("span %s has parent %s, want %s-%s from the caller", span.SpanContext().TraceID(), span.Parent().SpanID(), traceID, spanID)
+	}
+	// The service runs in the request span, so the recognition stages are its children
+	if service.span.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("service span = %s, want the request span %s", service.span.SpanID(), span.SpanContext().SpanID())
+	}
+}
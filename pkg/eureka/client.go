@@ -0,0 +1,274 @@
+package eureka
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	config "github.com/media-luna/eureka/configs"
+	"github.com/media-luna/eureka/internal/audio"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/dump"
+	core "github.com/media-luna/eureka/internal/eureka"
+)
+
+// ErrInvalidAudio is wrapped by errors caused by input audio that cannot be decoded
+var ErrInvalidAudio = core.ErrInvalidAudio
+
+// ErrInvalidMetadata is wrapped by errors of UpdateSong caused by metadata that cannot be stored
+var ErrInvalidMetadata = core.ErrInvalidMetadata
+
+// Match is a song recognized in a clip
+type Match = core.Match
+
+// PCMFormat describes headerless PCM audio passed to RecognizePCM
+type PCMFormat = audio.PCMFormat
+
+// ParsePCMFormat parses a format written as encoding:rate:channels, e.g. s16le:44100:2
+func ParsePCMFormat(spec string) (PCMFormat, error) {
+	return audio.ParsePCMFormat(spec)
+}
+
+// StreamRecognizer recognizes a song from audio pushed in chunks, see Client.NewStream
+type StreamRecognizer = core.StreamRecognizer
+
+// StreamOptions configures a StreamRecognizer
+type StreamOptions = core.StreamOptions
+
+// MatchEvent is emitted by a StreamRecognizer as its confidence in a song grows
+type MatchEvent = core.MatchEvent
+
+// MatchEventType distinguishes interim and final stream recognition events
+type MatchEventType = core.MatchEventType
+
+const (
+	// EventPossibleMatch reports a new leading candidate that is not yet confident enough
+	EventPossibleMatch = core.EventPossibleMatch
+	// EventMatch reports that a song crossed the match threshold, it is sent once per stream
+	EventMatch = core.EventMatch
+)
+
+// DefaultStreamOptions returns the options used for live microphone recognition
+func DefaultStreamOptions() StreamOptions {
+	return core.DefaultStreamOptions()
+}
+
+// Client stores songs and recognizes audio against them. It is safe for concurrent use.
+type Client struct {
+	eureka *core.Eureka
+}
+
+// New creates a Client. Without options it uses config.Default() and connects to the
+// MySQL database configured there, creating the tables if needed. ctx bounds connecting
+// to the database.
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	o := options{config: config.Default()}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.storage != nil {
+		return &Client{eureka: core.NewEurekaWithDatabase(o.config, storageDatabase{o.storage})}, nil
+	}
+
+	e, err := core.NewEureka(ctx, o.config)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Eureka: %v", err)
+	}
+	return &Client{eureka: e}, nil
+}
+
+// Close releases the storage
+func (c *Client) Close() error {
+	return c.eureka.Close()
+}
+
+// IngestFile fingerprints an audio file (WAV, FLAC or MP3, by extension) and stores it
+// with the given metadata, returning the song ID
+func (c *Client) IngestFile(ctx context.Context, path string, title string, artist string) (int, error) {
+	return c.eureka.SaveSong(ctx, path, title, artist)
+}
+
+// RecognizeFile recognizes the first 30 seconds of an audio file (WAV, FLAC or MP3)
+func (c *Client) RecognizeFile(ctx context.Context, path string) ([]Match, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audio file: %v", err)
+	}
+	defer file.Close()
+
+	return c.eureka.RecognizeReader(ctx, file)
+}
+
+// RecognizeReader recognizes the first 30 seconds of an encoded stream (WAV, FLAC or
+// MP3, detected from its content)
+func (c *Client) RecognizeReader(ctx context.Context, r io.Reader) ([]Match, error) {
+	return c.eureka.RecognizeReader(ctx, r)
+}
+
+// RecognizePCM recognizes the first 30 seconds of a headerless PCM stream
+func (c *Client) RecognizePCM(ctx context.Context, r io.Reader, format PCMFormat) ([]Match, error) {
+	return c.eureka.RecognizePCM(ctx, r, format)
+}
+
+// RecognizeSamples recognizes mono samples in [-1, 1] at the given sample rate.
+// Only the first 30 seconds are used.
+func (c *Client) RecognizeSamples(ctx context.Context, samples []float64, sampleRate int) ([]Match, error) {
+	return c.eureka.RecognizeSamples(ctx, samples, sampleRate)
+}
+
+// NewStream creates a StreamRecognizer for audio arriving in chunks. Close it when done.
+func (c *Client) NewStream(opts StreamOptions) *StreamRecognizer {
+	return c.eureka.NewStreamRecognizer(opts)
+}
+
+// GetSong returns a stored song, the error wraps ErrNotFound if it does not exist
+func (c *Client) GetSong(ctx context.Context, songID int) (SongInfo, error) {
+	info, err := c.eureka.GetSong(ctx, songID)
+	if err != nil {
+		return SongInfo{}, err
+	}
+	return SongInfo(info), nil
+}
+
+// ListSongs returns all stored songs. Custom storages must implement SongLister.
+func (c *Client) ListSongs(ctx context.Context) ([]Song, error) {
+	page, err := c.SearchSongs(ctx, SongQuery{})
+	return page.Songs, err
+}
+
+// SearchSongs returns the songs selected by query, with the number of songs matching
+// its filters to paginate through them. The error wraps ErrInvalidQuery for an invalid
+// query. Custom storages must implement SongLister.
+func (c *Client) SearchSongs(ctx context.Context, query SongQuery) (SongPage, error) {
+	page, err := c.eureka.List(ctx, catalog.SongQuery(query))
+	if err != nil {
+		return SongPage{}, err
+	}
+	songs := make([]Song, len(page.Songs))
+	for i, song := range page.Songs {
+		songs[i] = Song(song)
+	}
+	return SongPage{Songs: songs, Total: page.Total}, nil
+}
+
+// UpdateSong changes the metadata of a song without touching its fingerprints and
+// returns the updated song. Fields of update left nil are unchanged. The error wraps
+// ErrNotFound if the song does not exist, and ErrInvalidMetadata for an empty name or
+// a value longer than 250 characters. Custom storages must implement SongUpdater.
+func (c *Client) UpdateSong(ctx context.Context, songID int, update SongUpdate) (SongInfo, error) {
+	info, err := c.eureka.UpdateSong(ctx, songID, mysql.SongUpdate(update))
+	if err != nil {
+		return SongInfo{}, err
+	}
+	return SongInfo(info), nil
+}
+
+// DeleteSong removes a song and its fingerprints
+func (c *Client) DeleteSong(ctx context.Context, songID int) error {
+	return c.eureka.Delete(ctx, songID)
+}
+
+// DumpSummary counts the songs and fingerprints of an exported catalog
+type DumpSummary struct {
+	Songs        int `json:"songs"`
+	Fingerprints int `json:"fingerprints"`
+}
+
+// ImportResult summarizes an imported catalog
+type ImportResult struct {
+	Songs        int   `json:"songs"`        // Songs stored
+	Skipped      int   `json:"skipped"`      // Songs already fingerprinted in the storage
+	Fingerprints int   `json:"fingerprints"` // Fingerprints stored
+	DurationMs   int64 `json:"duration_ms"`
+}
+
+// ErrInvalidDump is wrapped by errors of Import reading a file that is not a complete dump
+var ErrInvalidDump = dump.ErrInvalid
+
+// Export writes the fingerprinted songs to w in the compressed, backend independent
+// format of eureka db export. Only the built-in storages support exporting.
+func (c *Client) Export(ctx context.Context, w io.Writer) (DumpSummary, error) {
+	summary, err := c.eureka.Export(ctx, w)
+	return DumpSummary(summary), err
+}
+
+// Import stores the songs of a catalog written by Export or eureka db export. Songs
+// already stored are skipped, so importing a catalog twice is harmless. Custom storages
+// without SongLister cannot skip songs, they must ignore duplicates themselves.
+func (c *Client) Import(ctx context.Context, r io.Reader) (ImportResult, error) {
+	result, err := c.eureka.Import(ctx, r)
+	return ImportResult(result), err
+}
+
+// Recognition is a recognition recorded in the history
+type Recognition struct {
+	ID           int64
+	RecognizedAt time.Time
+	Source       string // file, mic or api
+	ClientID     string // API client, empty for the CLI
+	AudioMs      int64  // Duration of the audio queried
+	LatencyMs    int64
+	Matches      []RecognitionMatch // Best first, empty when nothing matched
+}
+
+// RecognitionMatch is a song matched by a recorded recognition
+type RecognitionMatch struct {
+	SongID int     `json:"song_id"`
+	Song   string  `json:"song"`
+	Artist string  `json:"artist"`
+	Score  float64 `json:"score"`
+	Offset int     `json:"offset_ms"`
+}
+
+// RecognitionQuery filters and paginates History, newest first. The zero value lists
+// the whole history.
+type RecognitionQuery struct {
+	Source   string    // file, mic or api, any when empty
+	ClientID string    // API client, any when empty
+	SongID   int       // Recognitions whose best match is this song, unless 0
+	Matched  *bool     // Recognitions that matched a song or not, nil for any
+	Since    time.Time // Recognitions at or after this time, unless zero
+	Until    time.Time // Recognitions before this time, unless zero
+	Limit    int       // Maximum number of recognitions, 0 for no limit
+	Offset   int       // Number of matching recognitions skipped
+}
+
+// RecognitionPage is a page of the recognition history with the number of recognitions
+// matching the filters
+type RecognitionPage struct {
+	Recognitions []Recognition
+	Total        int // Recognitions matching the filters, ignoring Limit and Offset
+}
+
+// History returns the recorded recognitions selected by query. Recognitions are only
+// recorded when history.enabled is set, and only by the built-in storages.
+func (c *Client) History(ctx context.Context, query RecognitionQuery) (RecognitionPage, error) {
+	page, err := c.eureka.History(ctx, mysql.RecognitionQuery(query))
+	if err != nil {
+		return RecognitionPage{}, err
+	}
+
+	result := RecognitionPage{Recognitions: make([]Recognition, len(page.Recognitions)), Total: page.Total}
+	for i, r := range page.Recognitions {
+		matches := make([]RecognitionMatch, len(r.Matches))
+		for j, match := range r.Matches {
+			matches[j] = RecognitionMatch(match)
+		}
+		result.Recognitions[i] = Recognition{
+			ID:           r.ID,
+			RecognizedAt: r.RecognizedAt,
+			Source:       r.Source,
+			ClientID:     r.ClientID,
+			AudioMs:      r.AudioMs,
+			LatencyMs:    r.LatencyMs,
+			Matches:      matches,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package eureka
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/media-luna/eureka/internal/database/mysql"
+	"github.com/media-luna/eureka/internal/eurekatest"
+)
+
+// catalogStorage is a Storage over an in-memory catalog. Methods not needed by
+// recognition panic through the nil embedded Storage.
+type catalogStorage struct {
+	Storage
+	catalog *eurekatest.Catalog
+}
+
+func (s catalogStorage) QueryFingerprints(ctx context.Context, hashes []string) ([]FingerprintMatch, error) {
+	matches, err := s.catalog.QueryFingerprints(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	public := make([]FingerprintMatch, len(matches))
+	for i, match := range matches {
+		public[i] = FingerprintMatch(match)
+	}
+	return public, nil
+}
+
+func (s catalogStorage) GetSongByID(ctx context.Context, songID int) (SongInfo, error) {
+	info, err := s.catalog.GetSongByID(ctx, songID)
+	return SongInfo(info), err
+}
+
+func TestWithTopResults(t *testing.T) {
+	// Both songs hold the fingerprints of the recorded audio
+	pcm, samples := eurekatest.ChirpPCM(t, 8)
+	fingerprints := eurekatest.Fingerprints(t, samples)
+	catalog := &eurekatest.Catalog{}
+	catalog.Add(mysql.SongInfo{ID: 1, Name: "Chirp"}, fingerprints, 0)
+	catalog.Add(mysql.SongInfo{ID: 2, Name: "Chirp (Remaster)"}, fingerprints, 1000)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"default", nil, 2},
+		{"one", []Option{WithTopResults(1)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(context.Background(), append(tt.opts, WithStorage(catalogStorage{catalog: catalog}))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matches, err := client.RecognizePCM(context.Background(), bytes.NewReader(pcm), eurekatest.Format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) != tt.want {
+				t.Fatalf("%d matches, want %d: %+v", len(matches), tt.want, matches)
+			}
+		})
+	}
+}
+
+func TestWithTopResultsRejectsNonPositive(t *testing.T) {
+	if _, err := New(context.Background(), WithTopResults(0), WithStorage(catalogStorage{})); err == nil {
+		t.Fatal("New accepted zero top results")
+	}
+}
@@ -0,0 +1,36 @@
+// Package eureka is the public Go API of Eureka, for embedding audio fingerprinting
+// and song recognition in other programs.
+//
+// A Client stores songs and recognizes audio against them:
+//
+//	client, err := eureka.New(ctx, eureka.WithConfigFile("configs/config.yaml"))
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	id, err := client.IngestFile(ctx, "song.mp3", "Title", "Artist")
+//	matches, err := client.RecognizeFile(ctx, "clip.wav")
+//
+// Fingerprints can also be extracted without a database with FingerprintSamples
+// and FingerprintReader, and any backend implementing Storage can replace the
+// built-in MySQL storage through WithStorage.
+//
+// # Compatibility
+//
+// This package follows semantic versioning from v1.0.0 onwards. Within a major
+// version:
+//
+//   - Exported identifiers are not removed or renamed, and function signatures
+//     do not change.
+//   - Fields may be added to structs, so construct them with field names.
+//   - New functional options may be added, existing ones keep their meaning.
+//   - Methods are not added to the Storage interface, a backend written against
+//     one minor version keeps compiling against the next. Optional storage
+//     features are exposed as separate interfaces checked at run time.
+//   - Fingerprint hashes are stable, so stored songs keep matching after an
+//     upgrade. A change of the hashing scheme is a major version bump.
+//
+// Scores and the exact set of matches returned for a clip may improve between
+// minor versions. Everything under internal/ is outside these guarantees.
+package eureka
@@ -0,0 +1,45 @@
+package eureka
+
+import (
+	"fmt"
+	"io"
+
+	fingerprint "github.com/media-luna/eureka/internal/fingerprint"
+)
+
+// Fingerprint is a hash of a pair of spectrogram peaks and the time of the first peak
+type Fingerprint struct {
+	Hash   string // Hex encoded, stable across minor versions
+	Offset int    // Milliseconds from the start of the audio
+}
+
+// FingerprintSamples extracts the fingerprints of mono samples in [-1, 1] at the
+// given sample rate. The result is the same as for a stored song, so it can be
+// matched against a Storage or compared between clips.
+func FingerprintSamples(samples []float64, sampleRate int) ([]Fingerprint, error) {
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %d", sampleRate)
+	}
+
+	spectrogram, err := fingerprint.SamplesToSpectrogram(samples, sampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("error creating spectrogram: %v", err)
+	}
+
+	hashes := fingerprint.GenerateFingerprints(fingerprint.PickPeaks(spectrogram, sampleRate))
+	fingerprints := make([]Fingerprint, len(hashes))
+	for i, h := range hashes {
+		fingerprints[i] = Fingerprint{Hash: h.Hash, Offset: h.Offset}
+	}
+	return fingerprints, nil
+}
+
+// FingerprintReader decodes an encoded stream (WAV, FLAC or MP3, detected from its
+// content) and extracts its fingerprints. The whole stream is read, downmixed to mono.
+func FingerprintReader(r io.Reader) ([]Fingerprint, error) {
+	samples, sampleRate, err := fingerprint.DecodeReader(r, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAudio, err)
+	}
+	return FingerprintSamples(samples, sampleRate)
+}
@@ -0,0 +1,67 @@
+package eureka
+
+import (
+	"fmt"
+
+	config "github.com/media-luna/eureka/configs"
+)
+
+// Option configures a Client created by New
+type Option func(*options) error
+
+// options collects the settings applied by Option values
+type options struct {
+	config  config.Config
+	storage Storage
+}
+
+// WithConfig replaces the whole configuration, the default is config.Default()
+func WithConfig(cfg config.Config) Option {
+	return func(o *options) error {
+		o.config = cfg
+		return nil
+	}
+}
+
+// WithConfigFile loads the configuration from a YAML file in the format of configs/config.yaml
+func WithConfigFile(path string) Option {
+	return func(o *options) error {
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			return fmt.Errorf("error loading configuration: %v", err)
+		}
+		o.config = *cfg
+		return nil
+	}
+}
+
+// WithDatabase sets the database connection settings, keeping the rest of the configuration
+func WithDatabase(db config.DBConfig) Option {
+	return func(o *options) error {
+		o.config.Database = db
+		return nil
+	}
+}
+
+// WithTopResults sets the maximum number of matches returned by recognition
+func WithTopResults(n int) Option {
+	return func(o *options) error {
+		if n <= 0 {
+			return fmt.Errorf("top results must be positive, got %d", n)
+		}
+		o.config.Recognition.TopResults = n
+		return nil
+	}
+}
+
+// WithStorage stores songs and fingerprints in storage instead of connecting to the
+// configured database. The storage must be set up already, New does not call Setup.
+func WithStorage(storage Storage) Option {
+	return func(o *options) error {
+		if storage == nil {
+			return fmt.Errorf("storage must not be nil")
+		}
+		o.storage = storage
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/media-luna/eureka/internal/database"
+	"github.com/media-luna/eureka/internal/database/catalog"
+	"github.com/media-luna/eureka/internal/database/migrate"
+	"github.com/media-luna/eureka/internal/database/mysql"
+)
+
+// ErrNotFound is wrapped by errors reporting a missing song, check it with errors.Is
+var ErrNotFound = mysql.ErrNotFound
+
+// ErrNewerSchema is wrapped by the error of New when the database was migrated by a
+// newer release, check it with errors.Is
+var ErrNewerSchema = migrate.ErrNewerSchema
+
+// Song is a stored song with its fingerprinting status
+type Song struct {
+	ID            int
+	Name          string
+	Artist        string
+	Album         string
+	Fingerprinted bool
+	FileSHA1      string
+	TotalHashes   int
+	DateCreated   string
+}
+
+// SongInfo is the metadata of a stored song
+type SongInfo struct {
+	ID     int
+	Name   string
+	Artist string
+	Album  string
+}
+
+// FingerprintMatch is a stored fingerprint returned by a hash lookup
+type FingerprintMatch struct {
+	Hash   string
+	SongID int
+	Offset int // Milliseconds from the start of the song
+}
+
+// Play is a detected airing of a song on a monitored channel
+type Play struct {
+	ID        int
+	Channel   string
+	SongID    int
+	StartedAt time.Time
+	EndedAt   time.Time
+	Score     float64
+}
+
+// Storage persists songs and their fingerprints. Implement it to back a Client with
+// a database other than the built-in MySQL storage, and pass it to WithStorage.
+type Storage interface {
+	// Setup creates the tables if they do not exist, or migrates them to the latest schema
+	Setup(ctx context.Context) error
+	// Close releases the connection
+	Close() error
+	// InsertFingerprints stores one fingerprint hash of a song at offset milliseconds
+	InsertFingerprints(ctx context.Context, fingerprint string, songID int, offset int) error
+	// InsertSong stores a song that is not fingerprinted yet and returns its ID
+	InsertSong(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int, error)
+	// DeleteSong removes a song and its fingerprints
+	DeleteSong(ctx context.Context, songID int) error
+	// UpdateSongFingerprinted marks a song as completely fingerprinted
+	UpdateSongFingerprinted(ctx context.Context, songID int) error
+	// Cleanup removes duplicate and incompletely fingerprinted songs
+	Cleanup(ctx context.Context) error
+	// QueryFingerprints returns the stored fingerprints having one of the hashes
+	QueryFingerprints(ctx context.Context, hashes []string) ([]FingerprintMatch, error)
+	// GetSongByID returns a song, the error wraps ErrNotFound if it does not exist
+	GetSongByID(ctx context.Context, songID int) (SongInfo, error)
+	// InsertPlay stores a detected airing
+	InsertPlay(ctx context.Context, play Play) error
+}
+
+// SongUpdate holds the metadata changed by Client.UpdateSong, nil fields are left unchanged
+type SongUpdate struct {
+	Name   *string
+	Artist *string
+	Album  *string
+}
+
+// SongQuery filters, sorts and paginates Client.SearchSongs, the zero value lists
+// every song by ID
+type SongQuery struct {
+	Name          string    // Case-insensitive substring of the name
+	Artist        string    // Case-insensitive substring of the artist
+	Fingerprinted *bool     // Fingerprinting status, nil for any
+	CreatedAfter  time.Time // Songs created at or after this time, unless zero
+	CreatedBefore time.Time // Songs created before this time, unless zero
+	Sort          string    // id, name, artist or date_created, id when empty
+	Descending    bool
+	Limit         int // Maximum number of songs, 0 for no limit
+	Offset        int // Number of matching songs skipped
+}
+
+// SongPage is a page of songs with the number of songs matching the query filters
+type SongPage struct {
+	Songs []Song
+	Total int // Songs matching the filters, ignoring Limit and Offset
+}
+
+// ErrInvalidQuery is wrapped by errors reporting an invalid SongQuery
+var ErrInvalidQuery = catalog.ErrInvalidQuery
+
+// SongLister is implemented by storages supporting Client.ListSongs, Client.SearchSongs
+// and Client.Import
+type SongLister interface {
+	// ListSongs returns the songs selected by query and the number of songs matching
+	// its filters
+	ListSongs(ctx context.Context, query SongQuery) (SongPage, error)
+}
+
+// SongUpdater is implemented by storages supporting Client.UpdateSong
+type SongUpdater interface {
+	// UpdateSong changes the metadata set in update, the error wraps ErrNotFound if
+	// the song does not exist
+	UpdateSong(ctx context.Context, songID int, update SongUpdate) error
+}
+
+// The public types mirror internal ones field by field and are converted with Go
+// struct conversions, which only compile while the fields are identical: a change of
+// an internal type fails the build here instead of changing the public API.
+
+// storageDatabase adapts a Storage to the internal database interface, which has
+// grown methods that Storage cannot require, converting between the public and the
+// internal types. Optional features missing from the storage fail with an error
+// wrapping errors.ErrUnsupported.
+type storageDatabase struct {
+	storage Storage
+}
+
+var _ database.Database = storageDatabase{}
+
+func (s storageDatabase) Setup(ctx context.Context) error {
+	return s.storage.Setup(ctx)
+}
+
+func (s storageDatabase) Close() error {
+	return s.storage.Close()
+}
+
+func (s storageDatabase) InsertFingerprints(ctx context.Context, fingerprint string, songID int, offset int) error {
+	return s.storage.InsertFingerprints(ctx, fingerprint, songID, offset)
+}
+
+func (s storageDatabase) InsertSong(ctx context.Context, songName string, artistName string, fileHash string, totalHashes int) (int, error) {
+	return s.storage.InsertSong(ctx, songName, artistName, fileHash, totalHashes)
+}
+
+func (s storageDatabase) DeleteSong(ctx context.Context, songID int) error {
+	return s.storage.DeleteSong(ctx, songID)
+}
+
+func (s storageDatabase) UpdateSongFingerprinted(ctx context.Context, songID int) error {
+	return s.storage.UpdateSongFingerprinted(ctx, songID)
+}
+
+func (s storageDatabase) Cleanup(ctx context.Context) error {
+	return s.storage.Cleanup(ctx)
+}
+
+func (s storageDatabase) QueryFingerprints(ctx context.Context, hashes []string) ([]mysql.FingerprintMatch, error) {
+	matches, err := s.storage.QueryFingerprints(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	internal := make([]mysql.FingerprintMatch, len(matches))
+	for i, match := range matches {
+		internal[i] = mysql.FingerprintMatch(match)
+	}
+	return internal, nil
+}
+
+func (s storageDatabase) GetSongByID(ctx context.Context, songID int) (mysql.SongInfo, error) {
+	info, err := s.storage.GetSongByID(ctx, songID)
+	return mysql.SongInfo(info), err
+}
+
+func (s storageDatabase) InsertPlay(ctx context.Context, play mysql.Play) error {
+	return s.storage.InsertPlay(ctx, Play(play))
+}
+
+func (s storageDatabase) UpdateSong(ctx context.Context, songID int, update mysql.SongUpdate) error {
+	if u, ok := s.storage.(SongUpdater); ok {
+		return u.UpdateSong(ctx, songID, SongUpdate(update))
+	}
+	return fmt.Errorf("storage does not support updating songs: %w", errors.ErrUnsupported)
+}
+
+func (s storageDatabase) ListSongs(ctx context.Context, query catalog.SongQuery) (mysql.SongPage, error) {
+	l, ok := s.storage.(SongLister)
+	if !ok {
+		return mysql.SongPage{}, fmt.Errorf("storage does not support listing songs: %w", errors.ErrUnsupported)
+	}
+	page, err := l.ListSongs(ctx, SongQuery(query))
+	if err != nil {
+		return mysql.SongPage{}, err
+	}
+	internal := mysql.SongPage{Songs: make([]mysql.Song, len(page.Songs)), Total: page.Total}
+	for i, song := range page.Songs {
+		internal.Songs[i] = mysql.Song(song)
+	}
+	return internal, nil
+}
@@ -1,14 +1,99 @@
+// Package logger is the leveled, structured logger shared by all Eureka packages.
+// Logs go to stderr so that command results on stdout stay machine-readable.
 package logger
 
 import (
+	"context"
 	"fmt"
-	"time"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
 )
 
-func Info(message string) {
-	fmt.Printf("[%s] INFO: %s\n", time.Now().Format("15:04:05"), message)
+// Options configures the default logger
+type Options struct {
+	Level  slog.Level
+	Format string    // text (default) or json
+	Output io.Writer // Defaults to stderr
 }
 
-func Error(err error) {
-	fmt.Printf("[%s] ERROR: %s\n", time.Now().Format("15:04:05"), err)
+var (
+	current  atomic.Pointer[slog.Logger]
+	terminal atomic.Bool // Text output at info level or below, where progress bars are welcome
+)
+
+func init() {
+	Setup(Options{Level: slog.LevelInfo})
+}
+
+// Setup replaces the default logger
+func Setup(opts Options) error {
+	output := opts.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	format := strings.ToLower(opts.Format)
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+	var handler slog.Handler
+	switch format {
+	case "", "text":
+		handler = slog.NewTextHandler(output, handlerOpts)
+	case "json":
+		handler = slog.NewJSONHandler(output, handlerOpts)
+	default:
+		return fmt.Errorf("unsupported log format %q, expected text or json", opts.Format)
+	}
+
+	l := slog.New(handler)
+	current.Store(l)
+	slog.SetDefault(l)
+	terminal.Store(format != "json" && opts.Level <= slog.LevelInfo)
+	return nil
+}
+
+// ParseLevel parses debug, info, warn or error
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unsupported log level %q, expected debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// Logger returns the default logger, e.g. to derive one with fixed fields through With
+func Logger() *slog.Logger {
+	return current.Load()
+}
+
+// ShowProgress reports whether progress bars should be drawn on stderr
+func ShowProgress() bool {
+	return terminal.Load()
+}
+
+// Enabled reports whether messages at level are logged
+func Enabled(level slog.Level) bool {
+	return Logger().Enabled(context.Background(), level)
+}
+
+// Debug logs a diagnostic message with optional key-value fields
+func Debug(message string, args ...any) {
+	Logger().Debug(message, args...)
+}
+
+// Info logs a progress message with optional key-value fields
+func Info(message string, args ...any) {
+	Logger().Info(message, args...)
+}
+
+// Warn logs a recoverable problem with optional key-value fields
+func Warn(message string, args ...any) {
+	Logger().Warn(message, args...)
+}
+
+// Error logs an error with optional key-value fields
+func Error(err error, args ...any) {
+	Logger().Error(err.Error(), args...)
 }